@@ -0,0 +1,78 @@
+package sabot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	runIdKey       string = "run_id"
+	originRunIdKey string = "origin_run_id"
+)
+
+// Annotate tags err with the run_id found in ctx fields.
+//
+// When an annotated error is later logged via a ctx having a different run_id,
+// a worker's for example, the original is included as origin_run_id, linking producer and consumer logs.
+// Err is returned as-is when nil or ctx has no run_id.
+func Annotate(ctx context.Context, err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	runId, ok := getFields(ctx)[runIdKey]
+	if !ok {
+		return err
+	}
+
+	return &annotated{
+		error: err,
+		runId: runId,
+	}
+}
+
+//
+// unexported
+//
+
+type annotated struct {
+	error
+	runId any
+}
+
+func (an *annotated) Unwrap() error {
+
+	return an.error
+}
+
+func (an *annotated) Format(state fmt.State, verb rune) {
+
+	// delegate so as to keep a stack trace from pkg/errors, for example
+
+	if verb == 'v' && state.Flag('+') {
+		_, _ = fmt.Fprintf(state, "%+v", an.error)
+		return
+	}
+
+	_, _ = fmt.Fprint(state, an.error.Error())
+}
+
+func originFields(ctx context.Context, err error) (kv []any) {
+
+	var an *annotated
+	if !errors.As(err, &an) {
+		return
+	}
+
+	// stringified as run_id may not be comparable, []byte say
+
+	runId, ok := getFields(ctx)[runIdKey]
+	if ok && stringify(runId) == stringify(an.runId) {
+		return
+	}
+
+	return []any{originRunIdKey, an.runId}
+}
@@ -0,0 +1,90 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+var _ = Describe("Annotate", func() {
+
+	var (
+		buf      *bytes.Buffer
+		lgr      *Sabot
+		producer context.Context
+		consumer context.Context
+		err      error
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		producer = lgr.WithFields(context.Background(), "run_id", "abc")
+		consumer = lgr.WithFields(context.Background(), "run_id", "xyz")
+		err = fmt.Errorf("oops")
+	})
+
+	When("annotated error is logged in a different ctx", func() {
+		JustBeforeEach(func() {
+			lgr.Error(consumer, "failed", fmt.Errorf("wrapped: %w", Annotate(producer, err)))
+		})
+
+		It("should log the origin run_id", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":         "error",
+				"msg":           "failed",
+				"ts":            "nowish",
+				"run_id":        "xyz",
+				"origin_run_id": "abc",
+				"error_type":    "*errors.errorString",
+				"error":         "wrapped: oops",
+			}))
+		})
+	})
+
+	When("annotated error is logged in the same ctx", func() {
+		JustBeforeEach(func() {
+			lgr.Error(producer, "failed", Annotate(producer, err))
+		})
+
+		It("should not log the origin run_id", func() {
+			Expect(delog(buf)).ToNot(HaveKey("origin_run_id"))
+		})
+	})
+
+	When("run_id is not comparable", func() {
+		BeforeEach(func() {
+			producer = lgr.WithFields(context.Background(), "run_id", []byte("abc"))
+			consumer = lgr.WithFields(context.Background(), "run_id", []byte("xyz"))
+		})
+
+		It("should compare without panic", func() {
+			Expect(func() {
+				lgr.Error(producer, "failed", Annotate(producer, err))
+			}).ToNot(Panic())
+			Expect(delog(buf)).ToNot(HaveKey("origin_run_id"))
+
+			buf.Reset()
+			lgr.Error(consumer, "failed", Annotate(producer, err))
+			Expect(delog(buf)).To(HaveKey("origin_run_id"))
+		})
+	})
+
+	When("annotating an error carrying a stack", func() {
+		It("should format with the stack", func() {
+			annotated := Annotate(producer, errors.Errorf("oops"))
+			Expect(fmt.Sprintf("%v", annotated)).To(Equal("oops"))
+			Expect(fmt.Sprintf("%+v", annotated)).To(ContainSubstring("annotate_test.go"))
+		})
+	})
+
+	When("ctx has no run_id", func() {
+		It("should return the error as-is", func() {
+			Expect(Annotate(context.Background(), err)).To(Equal(err))
+		})
+	})
+})
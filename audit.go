@@ -0,0 +1,61 @@
+package sabot
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// auditRequired are the fields an audit event must have.
+var auditRequired = []string{"actor", "action", "target", "outcome"}
+
+// Audit logs an audit event for action, requiring actor, target, and outcome fields from kv, ctx, With, or Computed.
+//
+// Audit events are logged at the audit level regardless of level settings, routed as any level.
+// When required fields are missing, the event is logged with a logerror field, or when StrictAudit,
+// is refused and an error level event logged in it's stead.  Either way an error is returned.
+func (sabot *Sabot) Audit(ctx context.Context, action string, kv ...any) (err error) {
+
+	kv = append(kv, "action", action)
+
+	// check the event's fields, as required fields may come from With or Computed as well
+
+	ctxFields := sabot.ctxFields(ctx)
+	evt := sabot.newEvent(ctx, ctxFields, Audit.String(), action, nil, kv)
+
+	missing := missingFields(evt.Fields)
+	if len(missing) > 0 {
+		err = errors.Errorf("audit event missing required fields: %s", strings.Join(missing, ", "))
+
+		if sabot.StrictAudit {
+			sabot.log(ctx, Error.String(), "refused to log audit event", err, sabot.errorKv(ctx, err, []any{"action", action}))
+			return
+		}
+		evt.Fields[logErrorKey] = err.Error()
+	}
+
+	if !sabot.spend(ctx, Audit.String()) {
+		return
+	}
+
+	sabot.emitEvent(ctx, ctxFields, evt)
+	return
+}
+
+//
+// unexported
+//
+
+func missingFields(fields Fields) (missing []string) {
+
+	for _, key := range auditRequired {
+
+		_, ok := fields[key]
+		if !ok {
+			missing = append(missing, key)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,93 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audit", func() {
+
+	var (
+		buf      *bytes.Buffer
+		auditBuf *bytes.Buffer
+		lgr      *Sabot
+		ctx      context.Context
+		kv       []any
+		err      error
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		auditBuf = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer:   buf,
+			Routes:   map[Level]io.Writer{Audit: auditBuf},
+			Disabled: map[Level]bool{Info: true, Audit: true},
+		}
+		ctx = lgr.WithFields(context.Background(), "actor", "admin")
+		kv = []any{"target", "user:42", "outcome", "success"}
+	})
+
+	JustBeforeEach(func() {
+		err = lgr.Audit(ctx, "user.delete", kv...)
+	})
+
+	When("required fields are present", func() {
+		It("should log to the audit route regardless of level settings", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delog(auditBuf)).To(Equal(Fields{
+				"level":   "audit",
+				"msg":     "user.delete",
+				"ts":      "nowish",
+				"action":  "user.delete",
+				"actor":   "admin",
+				"target":  "user:42",
+				"outcome": "success",
+			}))
+		})
+	})
+
+	When("required fields are missing", func() {
+		BeforeEach(func() {
+			kv = []any{"target", "user:42"}
+		})
+
+		It("should log with a logerror and return an error", func() {
+			Expect(err).To(MatchError("audit event missing required fields: outcome"))
+			Expect(delog(auditBuf)).To(HaveKeyWithValue("logerror", "audit event missing required fields: outcome"))
+		})
+
+		When("supplied via With and Computed", func() {
+			BeforeEach(func() {
+				lgr = lgr.With("target", "user:42")
+				lgr.Computed = []FieldFunc{func(context.Context) (string, any) { return "outcome", "success" }}
+				kv = nil
+			})
+
+			It("should find them", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(delog(auditBuf)).To(HaveKeyWithValue("outcome", "success"))
+			})
+		})
+
+		When("strict", func() {
+			BeforeEach(func() {
+				lgr.StrictAudit = true
+			})
+
+			It("should refuse, logging an error instead", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(auditBuf.Len()).To(BeZero())
+
+				lgd := delog(buf)
+				Expect(lgd).To(HaveKeyWithValue("level", "error"))
+				Expect(lgd).To(HaveKeyWithValue("msg", "refused to log audit event"))
+				Expect(lgd).To(HaveKeyWithValue("action", "user.delete"))
+			})
+		})
+	})
+})
@@ -0,0 +1,221 @@
+// Package bench implements a harness sizing logging overhead against a configured pipeline, sinks included,
+// so that a config can be validated before rollout.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// samples is the number of latencies kept per worker, by reservoir sampling.
+const samples = 10000
+
+// Config is the configurable fields of Bench.
+type Config struct {
+	Duration time.Duration `json:"duration" desc:"how long events are logged for"`
+	Workers  int           `json:"workers" desc:"number of goroutines logging concurrently, one when zero"`
+	Fields   int           `json:"fields" desc:"number of kv fields logged with each event"`
+	ValueLen int           `json:"value_len" desc:"length of each field's value"`
+	Rate     int           `json:"rate" desc:"target events per second across workers, zero for as fast as possible"`
+}
+
+// New creates a Bench from Config, logging to lgr.
+func (cfg *Config) New(lgr *sabot.Sabot) *Bench {
+
+	return &Bench{
+		Logger:   lgr,
+		Duration: cfg.Duration,
+		Workers:  max(cfg.Workers, 1),
+		Fields:   cfg.Fields,
+		ValueLen: cfg.ValueLen,
+		Rate:     cfg.Rate,
+	}
+}
+
+// Bench logs events through a Sabot as configured for a service, measuring throughput and latency.
+//
+// Workers log concurrently, so the logger's writers must be safe for concurrent use, as they'd need to be in the service.
+type Bench struct {
+	// Logger is the pipeline under test, its writers safe for concurrent use.
+	Logger *sabot.Sabot
+	// Duration is how long events are logged for.
+	Duration time.Duration
+	// Workers is the number of goroutines logging concurrently.
+	Workers int
+	// Fields is the number of kv fields logged with each event.
+	Fields int
+	// ValueLen is the length of each field's value.
+	ValueLen int
+	// Rate is the target events per second across workers, zero for as fast as possible.
+	Rate int
+}
+
+// Result is the throughput and latency observed.
+type Result struct {
+	Events      int64         `json:"events"`
+	Elapsed     time.Duration `json:"elapsed"`
+	Rate        float64       `json:"rate"`
+	P50         time.Duration `json:"p50"`
+	P99         time.Duration `json:"p99"`
+	Max         time.Duration `json:"max"`
+	Dropped     int64         `json:"dropped"`
+	WriteErrors int64         `json:"write_errors"`
+}
+
+// String formats the result for reading.
+func (result Result) String() string {
+
+	return strings.Join([]string{
+		fmt.Sprintf("events:       %d in %s", result.Events, result.Elapsed.Round(time.Millisecond)),
+		fmt.Sprintf("rate:         %.0f/s", result.Rate),
+		fmt.Sprintf("latency:      p50 %s, p99 %s, max %s", result.P50, result.P99, result.Max),
+		fmt.Sprintf("dropped:      %d", result.Dropped),
+		fmt.Sprintf("write errors: %d", result.WriteErrors),
+	}, "\n")
+}
+
+// Run logs info events from each worker until Duration has elapsed or ctx is done.
+//
+// Latency is the time taken by each call to log, which is what a request handler sees added.
+// Dropped and write errors are counted when the Logger has Stats.
+func (bn *Bench) Run(ctx context.Context) (result Result, err error) {
+
+	if bn.Duration <= 0 {
+		err = errors.Errorf("duration must be positive")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bn.Duration)
+	defer cancel()
+
+	before := bn.Logger.State()
+	kv := bn.kv()
+
+	workers := make([]*worker, bn.Workers)
+	wg := sync.WaitGroup{}
+
+	start := time.Now()
+	for idx := range workers {
+		workers[idx] = &worker{rng: rand.New(rand.NewSource(int64(idx)))}
+
+		wg.Add(1)
+		go func(wkr *worker) {
+			defer wg.Done()
+			wkr.run(ctx, bn.Logger, kv, bn.interval())
+		}(workers[idx])
+	}
+	wg.Wait()
+	result.Elapsed = time.Since(start)
+
+	var latencies []time.Duration
+	for _, wkr := range workers {
+		result.Events += wkr.events
+		result.Max = max(result.Max, wkr.max)
+		latencies = append(latencies, wkr.latencies...)
+	}
+
+	result.Rate = float64(result.Events) / result.Elapsed.Seconds()
+	result.P50 = percentile(latencies, 50)
+	result.P99 = percentile(latencies, 99)
+
+	after := bn.Logger.State()
+	result.Dropped = after.Dropped - before.Dropped
+	result.WriteErrors = after.WriteErrors - before.WriteErrors
+
+	return
+}
+
+//
+// unexported
+//
+
+type worker struct {
+	rng       *rand.Rand
+	events    int64
+	max       time.Duration
+	latencies []time.Duration
+}
+
+func (wkr *worker) run(ctx context.Context, lgr *sabot.Sabot, kv []any, interval time.Duration) {
+
+	start := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		if interval > 0 {
+			wait := time.Until(start.Add(time.Duration(wkr.events) * interval))
+			if wait > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(wait):
+				}
+			}
+		}
+
+		began := time.Now()
+		lgr.Info(ctx, "bench", kv...)
+		wkr.record(time.Since(began))
+	}
+}
+
+// record keeps a uniform sample of latencies, as the full set would grow large.
+func (wkr *worker) record(latency time.Duration) {
+
+	wkr.events++
+	wkr.max = max(wkr.max, latency)
+
+	if len(wkr.latencies) < samples {
+		wkr.latencies = append(wkr.latencies, latency)
+		return
+	}
+
+	idx := wkr.rng.Int63n(wkr.events)
+	if idx < samples {
+		wkr.latencies[idx] = latency
+	}
+}
+
+func (bn *Bench) interval() time.Duration {
+
+	if bn.Rate <= 0 {
+		return 0
+	}
+
+	return time.Second * time.Duration(bn.Workers) / time.Duration(bn.Rate)
+}
+
+func (bn *Bench) kv() (kv []any) {
+
+	val := strings.Repeat("x", bn.ValueLen)
+	for idx := 0; idx < bn.Fields; idx++ {
+		kv = append(kv, fmt.Sprintf("field_%d", idx), val)
+	}
+
+	return
+}
+
+func percentile(latencies []time.Duration, pct int) time.Duration {
+
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	idx := (len(latencies)*pct + 99) / 100
+	return latencies[min(max(idx, 1), len(latencies))-1]
+}
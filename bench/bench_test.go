@@ -0,0 +1,119 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestBench(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Bench Suite")
+}
+
+// lockedBuffer is a bytes.Buffer safe for concurrent writes, as workers log at once.
+type lockedBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (lb *lockedBuffer) Write(data []byte) (int, error) {
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	return lb.buf.Write(data)
+}
+
+func (lb *lockedBuffer) String() string {
+
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+
+	return lb.buf.String()
+}
+
+type failWriter struct{}
+
+func (fw failWriter) Write(data []byte) (int, error) {
+
+	return 0, errors.New("disk full")
+}
+
+var _ = Describe("Bench", func() {
+
+	var (
+		cfg    *Config
+		lgr    *sabot.Sabot
+		buf    *lockedBuffer
+		result Result
+		err    error
+	)
+
+	BeforeEach(func() {
+		buf = &lockedBuffer{}
+		lgr = (&sabot.Config{}).New(buf)
+		cfg = &Config{Duration: 100 * time.Millisecond, Fields: 2, ValueLen: 3}
+	})
+
+	JustBeforeEach(func() {
+		result, err = cfg.New(lgr).Run(context.Background())
+	})
+
+	When("logging as fast as possible", func() {
+		It("should log events with fields, measuring latency", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Events).To(BeNumerically(">", 100))
+			Expect(int64(strings.Count(buf.String(), "\n"))).To(Equal(result.Events))
+			Expect(buf.String()).To(ContainSubstring(`"field_1":"xxx"`))
+
+			Expect(result.Elapsed).To(BeNumerically(">=", cfg.Duration))
+			Expect(result.Rate).To(BeNumerically(">", 0))
+			Expect(result.P50).To(BeNumerically(">", 0))
+			Expect(result.P50).To(BeNumerically("<=", result.P99))
+			Expect(result.P99).To(BeNumerically("<=", result.Max))
+		})
+	})
+
+	When("logging at a rate across workers", func() {
+		BeforeEach(func() {
+			cfg.Workers = 2
+			cfg.Rate = 200
+		})
+
+		It("should pace events", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Events).To(BeNumerically("~", 20, 4))
+		})
+	})
+
+	When("writes fail", func() {
+		BeforeEach(func() {
+			lgr = (&sabot.Config{}).New(failWriter{})
+		})
+
+		It("should count write errors", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.WriteErrors).To(BeNumerically(">", 0))
+		})
+	})
+
+	When("duration is not given", func() {
+		BeforeEach(func() {
+			cfg.Duration = 0
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError("duration must be positive"))
+		})
+	})
+})
@@ -0,0 +1,155 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+// Bind seals the fields of a ctx into a Bound logger.
+//
+// Fields are truncated and encoded once, up front, rather than with each event,
+// for hot loops within a single request where repeated ctx lookups and merges show up in profiles.
+// Fields added to ctx after binding are not seen by the Bound logger.
+func (sabot *Sabot) Bind(ctx context.Context) *Bound {
+
+	fields := Fields{}
+	merge(fields, sabot.ctxFields(ctx))
+	for _, key := range []string{"msg", "level", "ts"} {
+		delete(fields, key)
+	}
+	fields.unnest(sabot.Nested, sabot.Bytes)
+	fields.truncate(sabot.truncation())
+
+	bnd := &Bound{
+		sabot:  sabot,
+		ctx:    ctx,
+		fields: fields,
+	}
+
+	// keep the encoded fields sans braces for splicing, falling back to event-time encoding
+
+	data, err := json.Marshal(fields)
+	if err == nil && len(fields) > 0 {
+		bnd.encoded = data[1 : len(data)-1]
+	}
+
+	return bnd
+}
+
+// Bound is a logger with sealed ctx fields.
+type Bound struct {
+	sabot   *Sabot
+	ctx     context.Context
+	fields  Fields
+	encoded []byte
+}
+
+// Info logs info level events.
+func (bnd *Bound) Info(msg string, kv ...any) {
+
+	if !bnd.sabot.enabled(bnd.ctx, Info) {
+		return
+	}
+
+	bnd.log("info", msg, nil, kv)
+}
+
+// Warn logs warn level events.
+func (bnd *Bound) Warn(msg string, kv ...any) {
+
+	if !bnd.sabot.enabled(bnd.ctx, Warn) {
+		return
+	}
+
+	bnd.log("warn", msg, nil, kv)
+}
+
+// Fatal logs fatal level events and exits, as with Sabot.Fatal.
+func (bnd *Bound) Fatal(msg string, err error, kv ...any) {
+
+	if bnd.sabot.enabled(bnd.ctx, Fatal) {
+		bnd.log(Fatal.String(), msg, err, bnd.sabot.errorKv(bnd.ctx, err, kv))
+	}
+
+	bnd.sabot.exit(bnd.ctx)
+}
+
+// Debug logs debug level events.
+func (bnd *Bound) Debug(msg string, kv ...any) {
+
+	if !bnd.sabot.enabled(bnd.ctx, Debug) {
+		return
+	}
+
+	bnd.log("debug", msg, nil, kv)
+}
+
+// Trace logs trace level events.
+func (bnd *Bound) Trace(msg string, kv ...any) {
+
+	if !bnd.sabot.enabled(bnd.ctx, Trace) {
+		return
+	}
+
+	bnd.log("trace", msg, nil, kv)
+}
+
+// Error logs error level events.
+func (bnd *Bound) Error(msg string, err error, kv ...any) {
+
+	if !bnd.sabot.enabled(bnd.ctx, Error) || !bnd.sabot.first(bnd.ctx, msg, err) {
+		return
+	}
+
+	bnd.log("error", msg, err, bnd.sabot.errorKv(bnd.ctx, err, kv))
+}
+
+//
+// unexported
+//
+
+func (bnd *Bound) log(level, msg string, err error, kv []any) {
+
+	if !bnd.sabot.spend(bnd.ctx, level) {
+		return
+	}
+
+	// splice only when nothing downstream needs to see the sealed fields
+
+	_, structured := bnd.sabot.writerFor(level).(EventWriter)
+	if bnd.encoded == nil || structured || len(bnd.sabot.Hooks) > 0 || bnd.sabot.Encoder != nil || bnd.sabot.MaxClass != Unclassified || capturing(bnd.ctx) {
+		bnd.sabot.emit(bnd.ctx, bnd.fields, level, msg, err, kv)
+		return
+	}
+
+	// sealed fields take precedence over kv as would ctx fields
+
+	evt := bnd.sabot.newEvent(bnd.ctx, nil, level, msg, err, kv)
+	tenant, ok := bnd.sabot.admit(bnd.ctx, bnd.fields, evt)
+	if !ok {
+		return
+	}
+	for key := range bnd.fields {
+		evt.Delete(key)
+	}
+	evt.truncate(bnd.sabot.truncation())
+
+	fields := evt.Fields
+	evt.boilerplate(fields)
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		bnd.sabot.emit(bnd.ctx, bnd.fields, level, msg, evt.Err, kv)
+		return
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(bnd.encoded)+len(data)+1))
+	buf.WriteByte('{')
+	buf.Write(bnd.encoded)
+	buf.WriteByte(',')
+	buf.Write(data[1:])
+
+	bnd.sabot.Quota.used(tenant, buf.Len()+1)
+	bnd.sabot.write(level, buf.Bytes(), fields)
+}
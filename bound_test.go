@@ -0,0 +1,67 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bind", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		bnd *Bound
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, MaxLen: 20}
+
+		ctx := lgr.WithFields(context.Background(), "run_id", "123", "long", "abcdefghijklmnopqrstuvwxyz")
+		bnd = lgr.Bind(ctx)
+		_ = lgr.WithFields(ctx, "later", "unseen")
+	})
+
+	When("logging info with a bound logger", func() {
+		JustBeforeEach(func() {
+			bnd.Info("looping", "idx", 1)
+		})
+
+		It("should log sealed, truncated fields", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":         "info",
+				"msg":           "looping",
+				"ts":            "nowish",
+				"run_id":        "123",
+				"long":          "abcdefg--truncated--",
+				"long_orig_len": float64(26),
+				"idx":           float64(1),
+			}))
+		})
+	})
+
+	When("logging an error with a bound logger", func() {
+		JustBeforeEach(func() {
+			bnd.Error("failed", fmt.Errorf("oops"))
+		})
+
+		It("should log the error", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("error", "oops"))
+		})
+	})
+
+	When("logging debug with a bound logger and debug disabled", func() {
+		JustBeforeEach(func() {
+			bnd.Debug("looping")
+			bnd.Trace("looping")
+		})
+
+		It("should skip", func() {
+			Expect(delog(buf)).To(BeEmpty())
+		})
+	})
+})
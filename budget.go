@@ -0,0 +1,49 @@
+package sabot
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+const budgetNotice string = "log budget exhausted, suppressing further events below error"
+
+// WithBudget adds a cap on the number of events logged with ctx.
+//
+// Once max events have been logged, further events below error are suppressed
+// and a single notice is logged in their stead, protecting against loops that would
+// otherwise emit thousands of lines for one request.
+func (sabot *Sabot) WithBudget(ctx context.Context, max int) context.Context {
+
+	return context.WithValue(ctx, budgetKey{}, &budget{max: int64(max)})
+}
+
+//
+// unexported
+//
+
+type budgetKey struct{}
+
+type budget struct {
+	max     int64
+	count   atomic.Int64
+	noticed atomic.Bool
+}
+
+func (sabot *Sabot) spend(ctx context.Context, level string) bool {
+
+	bdg, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return true
+	}
+
+	if bdg.count.Add(1) <= bdg.max || levelOf(level) >= Error {
+		return true
+	}
+
+	if bdg.noticed.CompareAndSwap(false, true) {
+		sabot.emit(ctx, sabot.ctxFields(ctx), "info", budgetNotice, nil, []any{"budget", bdg.max})
+	}
+	sabot.Stats.drop()
+
+	return false
+}
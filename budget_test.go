@@ -0,0 +1,55 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithBudget", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, MinLevel: Debug}
+		ctx = lgr.WithBudget(context.Background(), 2)
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 5; i++ {
+			lgr.Debug(ctx, "looping")
+		}
+		lgr.Error(ctx, "failed", fmt.Errorf("oops"))
+	})
+
+	When("budget is exceeded", func() {
+		It("should log up to budget, one notice, and errors", func() {
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			Expect(lines).To(HaveLen(4))
+			Expect(lines[0]).To(ContainSubstring(`"msg":"looping"`))
+			Expect(lines[1]).To(ContainSubstring(`"msg":"looping"`))
+			Expect(lines[2]).To(ContainSubstring(`"msg":"log budget exhausted`))
+			Expect(lines[2]).To(ContainSubstring(`"budget":2`))
+			Expect(lines[3]).To(ContainSubstring(`"msg":"failed"`))
+		})
+	})
+
+	When("ctx has no budget", func() {
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should log everything", func() {
+			Expect(strings.Count(buf.String(), "\n")).To(Equal(6))
+		})
+	})
+})
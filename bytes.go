@@ -0,0 +1,42 @@
+package sabot
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"unicode/utf8"
+)
+
+// BytesMode is how []byte values are logged, the same by every encoder.
+type BytesMode string
+
+const (
+	// BytesBase64 logs bytes as standard base64, as json would.
+	BytesBase64 BytesMode = ""
+	// BytesHex logs bytes as lowercase hex.
+	BytesHex BytesMode = "hex"
+	// BytesText logs bytes as a string when valid utf-8, and as base64 otherwise.
+	BytesText BytesMode = "text"
+	// BytesLength logs only the length of bytes, as in "<42 bytes>", for when their content is of no use or not to be kept.
+	BytesLength BytesMode = "length"
+)
+
+//
+// unexported
+//
+
+func (mode BytesMode) render(data []byte) string {
+
+	switch mode {
+	case BytesHex:
+		return hex.EncodeToString(data)
+	case BytesText:
+		if utf8.Valid(data) {
+			return string(data)
+		}
+	case BytesLength:
+		return fmt.Sprintf("<%d bytes>", len(data))
+	}
+
+	return base64.StdEncoding.EncodeToString(data)
+}
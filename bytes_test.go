@@ -0,0 +1,63 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bytes", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = context.Background()
+	})
+
+	DescribeTable("logging bytes",
+		func(mode BytesMode, data []byte, expected string) {
+			lgr.Bytes = mode
+			lgr.Info(ctx, "bytes", "body", data, Group("req", "body", data))
+
+			fields := delog(buf)
+			Expect(fields["body"]).To(Equal(expected))
+			Expect(fields["req"]).To(Equal(map[string]any{"body": expected}))
+		},
+		Entry("base64 by default", BytesBase64, []byte("hi!"), "aGkh"),
+		Entry("hex", BytesHex, []byte("hi!"), "686921"),
+		Entry("text", BytesText, []byte("hi!"), "hi!"),
+		Entry("text when not utf-8", BytesText, []byte{0xff, 0xfe}, "//4="),
+		Entry("length", BytesLength, []byte("hi!"), "<3 bytes>"),
+	)
+
+	When("encoding as text", func() {
+		BeforeEach(func() {
+			lgr.Encoder = Logfmt{}
+			lgr.Bytes = BytesHex
+		})
+
+		It("should render bytes as json does", func() {
+			lgr.Info(ctx, "bytes", "body", []byte("hi!"))
+
+			Expect(buf.String()).To(ContainSubstring("body=686921"))
+		})
+	})
+
+	When("configured", func() {
+		It("should set the mode", func() {
+			Expect((&Config{Bytes: BytesLength}).New(nil).Bytes).To(Equal(BytesLength))
+		})
+
+		It("should validate the mode", func() {
+			Expect((&Config{Bytes: "binary"}).Validate()).To(MatchError("unknown bytes mode: binary"))
+		})
+	})
+})
@@ -0,0 +1,76 @@
+package sabot
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	callerKey string = "caller"
+	stackKey  string = "stack"
+	maxFrames int    = 32
+)
+
+//
+// unexported
+//
+
+// addCaller sets the caller field to the call site, as dir/file.go:line, when AddCaller.
+func (sabot *Sabot) addCaller(fields Fields) {
+
+	if !sabot.AddCaller {
+		return
+	}
+
+	_, site := caller(sabot.CallerSkip)
+	if site == "" {
+		return
+	}
+
+	fields[callerKey] = shortSite(site)
+}
+
+// shortSite trims a site to its file's directory and name, leaving the line.
+func shortSite(site string) string {
+
+	dir, file := filepath.Split(site)
+	dir = strings.TrimSuffix(dir, string(filepath.Separator))
+
+	return filepath.Base(dir) + "/" + file
+}
+
+// callStack formats the stack from the first frame outside of this package, tests aside,
+// as pkg/errors does with func and file:line on alternate lines.
+func callStack() string {
+
+	pcs := make([]uintptr, maxFrames)
+	count := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+
+	lines := []string{}
+	outside := false
+	for {
+		frame, more := frames.Next()
+
+		outside = outside || filepath.Dir(frame.File) != pkgDir || strings.HasSuffix(frame.File, "_test.go")
+		if outside && frame.Function != "runtime.goexit" {
+			lines = append(lines, frame.Function, fmt.Sprintf("\t%s:%d", frame.File, frame.Line))
+		}
+		if !more {
+			break
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// hasStack reports whether err, or any error it wraps, carries a pkg/errors stack.
+func hasStack(err error) bool {
+
+	var tracer interface{ StackTrace() errors.StackTrace }
+	return errors.As(err, &tracer)
+}
@@ -0,0 +1,174 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// domainError carries fields, as an app's errors might.
+type domainError struct {
+	code   string
+	fields Fields
+}
+
+func (de *domainError) Error() string {
+
+	return "domain failure: " + de.code
+}
+
+func (de *domainError) LogFields() Fields {
+
+	return de.fields
+}
+
+// logVia is a wrapper, as an app might have, to be skipped.
+func logVia(lgr *Sabot, msg string) {
+
+	lgr.Info(context.Background(), msg)
+}
+
+// next returns the site of the line after its caller's, shortened.
+func next() string {
+
+	_, file, line, _ := runtime.Caller(1)
+	return shortSite(fmt.Sprintf("%s:%d", file, line+1))
+}
+
+var _ = Describe("Caller", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = (&Config{AddCaller: true}).New(buf)
+	})
+
+	When("adding caller", func() {
+		It("should log the call site", func() {
+			site := next()
+			lgr.Info(context.Background(), "called")
+			Expect(delog(buf)["caller"]).To(Equal(site))
+			Expect(site).To(MatchRegexp(`^[^/]+/caller_test.go:\d+$`))
+		})
+
+		It("should be taken over by kv", func() {
+			lgr.Info(context.Background(), "called", "caller", "elsewhere")
+			Expect(delog(buf)["caller"]).To(Equal("elsewhere"))
+		})
+	})
+
+	When("logging via a wrapper", func() {
+		It("should log the wrapper's caller, with skip", func() {
+			lgr.CallerSkip = 1
+			site := next()
+			logVia(lgr, "wrapped")
+			Expect(delog(buf)["caller"]).To(Equal(site))
+		})
+	})
+
+	When("not adding caller", func() {
+		It("should not log it", func() {
+			lgr.AddCaller = false
+			lgr.Info(context.Background(), "called")
+			Expect(delog(buf)).ToNot(HaveKey("caller"))
+		})
+	})
+
+	When("capturing a stack for errors", func() {
+		BeforeEach(func() {
+			lgr.AddCaller = false
+			lgr.ErrorStack = true
+		})
+
+		It("should log one from the call site when the error has none", func() {
+			lgr.Error(context.Background(), "failed", fmt.Errorf("oops"))
+
+			stack, _ := delog(buf)["stack"].(string)
+			Expect(stack).To(HavePrefix("github.com/clarktrimble/sabot.init."))
+			Expect(stack).To(ContainSubstring("/caller_test.go:"))
+			Expect(stack).ToNot(ContainSubstring("/sabot.go:"))
+		})
+
+		It("should leave be an error with a stack", func() {
+			lgr.Error(context.Background(), "failed", errors.Errorf("oops"))
+			Expect(delog(buf)).ToNot(HaveKey("stack"))
+		})
+	})
+
+	When("logging the error chain", func() {
+		BeforeEach(func() {
+			lgr.AddCaller = false
+			lgr.ErrorChain = true
+		})
+
+		It("should list each message innermost first", func() {
+			err := errors.Wrapf(fmt.Errorf("connect: %w", errors.New("refused")), "failed to fetch")
+			lgr.Error(context.Background(), "failed", errors.WithStack(err))
+
+			Expect(delog(buf)).To(HaveKeyWithValue("error_chain", []any{"refused", "connect", "failed to fetch"}))
+		})
+
+		It("should not log one for an unwrapped error", func() {
+			lgr.Error(context.Background(), "failed", fmt.Errorf("oops"))
+			Expect(delog(buf)).ToNot(HaveKey("error_chain"))
+		})
+	})
+
+	When("logging joined errors", func() {
+		It("should list each, flattening nested joins", func() {
+			err := stderrors.Join(fmt.Errorf("disk full"), nil, stderrors.Join(fmt.Errorf("timeout"), fmt.Errorf("refused")))
+			lgr.Error(context.Background(), "failed", errors.Wrap(err, "cleanup"))
+
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("errors", []any{"disk full", "timeout", "refused"}))
+			Expect(logged["error"]).To(HavePrefix("disk full\ntimeout\nrefused\ncleanup"))
+		})
+
+		It("should not log them for an error joining none", func() {
+			lgr.Error(context.Background(), "failed", fmt.Errorf("oops"))
+			Expect(delog(buf)).ToNot(HaveKey("errors"))
+		})
+	})
+
+	When("logging a wrapped error", func() {
+		It("should log the type beneath any wrappers", func() {
+			err := errors.Wrap(fmt.Errorf("query: %w", &os.PathError{Op: "open", Path: "db", Err: os.ErrNotExist}), "failed")
+			lgr.Error(context.Background(), "failed", err)
+
+			Expect(delog(buf)).To(HaveKeyWithValue("error_type", "*fs.PathError"))
+		})
+	})
+
+	When("logging an error carrying fields", func() {
+		It("should merge them from across the chain, kv taking precedence", func() {
+			inner := &domainError{code: "E42", fields: Fields{"code": "E42", "request_id": "inner", "attempt": 1}}
+			outer := &domainError{code: "E7", fields: Fields{"code": "E7", "request_id": "outer"}}
+			err := stderrors.Join(fmt.Errorf("first: %w", inner), errors.WithStack(outer))
+
+			lgr.Error(context.Background(), "failed", errors.Wrap(err, "batch"), "attempt", 2)
+
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("code", "E7"))
+			Expect(logged).To(HaveKeyWithValue("request_id", "outer"))
+			Expect(logged).To(HaveKeyWithValue("attempt", 2.0))
+		})
+	})
+
+	When("skip is negative", func() {
+		It("should not validate", func() {
+			Expect((&Config{CallerSkip: -1}).Validate()).To(MatchError("caller skip is negative: -1"))
+		})
+	})
+})
@@ -0,0 +1,70 @@
+package sabot
+
+import (
+	"context"
+	"sync"
+)
+
+// maxCaptured is the number of events retained per capturing ctx, beyond which they are counted but not kept.
+const maxCaptured int = 10000
+
+// CaptureAll adds full capture to a given context, for debugging a single request.
+//
+// Events logged with the ctx bypass level checks and Sampler, and are retained, encoded as json,
+// for retrieval with Captured, as when included in a debug response.
+func (sabot *Sabot) CaptureAll(ctx context.Context) context.Context {
+
+	return context.WithValue(ctx, captureKey{}, &capture{})
+}
+
+// Captured returns the events retained for a ctx from CaptureAll, oldest first, and the count of those not retained.
+func (sabot *Sabot) Captured(ctx context.Context) (events [][]byte, missed int) {
+
+	cpt, ok := ctx.Value(captureKey{}).(*capture)
+	if !ok {
+		return
+	}
+
+	cpt.mu.Lock()
+	defer cpt.mu.Unlock()
+
+	return append([][]byte{}, cpt.events...), cpt.missed
+}
+
+//
+// unexported
+//
+
+type captureKey struct{}
+
+type capture struct {
+	events [][]byte
+	missed int
+	mu     sync.Mutex
+}
+
+func capturing(ctx context.Context) bool {
+
+	_, ok := ctx.Value(captureKey{}).(*capture)
+	return ok
+}
+
+// record retains an event for a capturing ctx, encoded as json regardless of Encoder.
+func record(ctx context.Context, evt *Event) {
+
+	cpt, ok := ctx.Value(captureKey{}).(*capture)
+	if !ok {
+		return
+	}
+
+	data, err := JSON{}.Encode(evt)
+
+	cpt.mu.Lock()
+	defer cpt.mu.Unlock()
+
+	if err != nil || len(cpt.events) >= maxCaptured {
+		cpt.missed++
+		return
+	}
+	cpt.events = append(cpt.events, data)
+}
@@ -0,0 +1,61 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CaptureAll", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, MinLevel: Warn}
+		lgr.Hooks = []Hook{(&Sampler{Default: 100}).Apply}
+		ctx = context.Background()
+	})
+
+	When("capturing a request", func() {
+		BeforeEach(func() {
+			ctx = lgr.CaptureAll(lgr.WithFields(ctx, "run_id", "abc"))
+		})
+
+		It("should bypass level checks and sampling, retaining events", func() {
+			lgr.Trace(ctx, "tracing")
+			lgr.Info(ctx, "one")
+			lgr.Info(ctx, "one")
+			lgr.Bind(ctx).Debug("bound")
+			lgr.Error(ctx, "failed", fmt.Errorf("oops"))
+
+			Expect(bytes.Count(buf.Bytes(), []byte("\n"))).To(Equal(5))
+
+			events, missed := lgr.Captured(ctx)
+			Expect(missed).To(BeZero())
+			Expect(events).To(HaveLen(5))
+			Expect(unjson(events[3])).To(HaveKeyWithValue("msg", "bound"))
+			Expect(unjson(events[3])).To(HaveKeyWithValue("run_id", "abc"))
+		})
+	})
+
+	When("not capturing", func() {
+		It("should check levels and sample as usual", func() {
+			lgr.Trace(ctx, "tracing")
+			lgr.Warn(ctx, "one")
+			lgr.Warn(ctx, "one")
+
+			Expect(bytes.Count(buf.Bytes(), []byte("\n"))).To(Equal(1))
+
+			events, _ := lgr.Captured(ctx)
+			Expect(events).To(BeEmpty())
+		})
+	})
+})
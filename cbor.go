@@ -0,0 +1,254 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// CBOR encodes events as a CBOR map, as with json, for embedded and constrained deployments
+// where json's size and float formatting are a problem.
+//
+// Times are encoded as epoch seconds, tagged as such, unless ts is formatted per TimeLayout.
+// When Deterministic, encoding follows the core deterministic rules of RFC 8949,
+// keys ordered by their encoding and floats in the shortest form keeping their value,
+// so that equal events encode to equal bytes, for hashing or signing say.
+type CBOR struct {
+	// Deterministic determines if events are encoded canonically, rather than with keys sorted and floats in full.
+	Deterministic bool
+}
+
+// Encode encodes an event.
+func (enc CBOR) Encode(evt *Event) (data []byte, err error) {
+
+	return enc.append(nil, evt.Merged()), nil
+}
+
+// Binary marks CBOR as binary, its events being written without a trailing newline.
+func (enc CBOR) Binary() {}
+
+//
+// unexported
+//
+
+// cbor major types.
+const (
+	cborUint   byte = 0
+	cborNegint byte = 1
+	cborBytes  byte = 2
+	cborText   byte = 3
+	cborArray  byte = 4
+	cborMap    byte = 5
+	cborTag    byte = 6
+
+	cborFalse   byte = 0xf4
+	cborTrue    byte = 0xf5
+	cborNull    byte = 0xf6
+	cborFloat16 byte = 0xf9
+	cborFloat32 byte = 0xfa
+	cborFloat64 byte = 0xfb
+
+	// cborEpoch tags epoch-based date/time.
+	cborEpoch uint64 = 1
+)
+
+// append appends the cbor of a value, objects becoming maps and slices arrays.
+func (enc CBOR) append(data []byte, val any) []byte {
+
+	switch val := val.(type) {
+	case nil:
+		return append(data, cborNull)
+	case string:
+		return append(cborHead(data, cborText, uint64(len(val))), val...)
+	case []byte:
+		return append(cborHead(data, cborBytes, uint64(len(val))), val...)
+	case time.Time:
+		data = cborHead(data, cborTag, cborEpoch)
+		if val.Nanosecond() == 0 {
+			return cborInt(data, val.Unix())
+		}
+		return enc.float(data, float64(val.UnixNano())/1e9)
+	case json.Number:
+		num, err := val.Int64()
+		if err == nil {
+			return cborInt(data, num)
+		}
+		flt, _ := val.Float64()
+		return enc.float(data, flt)
+	case json.RawMessage:
+		return enc.json(data, val)
+	case nested:
+		return enc.json(data, val)
+	case group:
+		return enc.fields(data, val)
+	case Fields:
+		return enc.fields(data, val)
+	case map[string]any:
+		return enc.fields(data, val)
+	case []string:
+		data = cborHead(data, cborArray, uint64(len(val)))
+		for _, str := range val {
+			data = enc.append(data, str)
+		}
+		return data
+	case []any:
+		data = cborHead(data, cborArray, uint64(len(val)))
+		for _, elem := range val {
+			data = enc.append(data, elem)
+		}
+		return data
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.String:
+		return enc.append(data, rv.String())
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(data, cborTrue)
+		}
+		return append(data, cborFalse)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cborInt(data, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cborHead(data, cborUint, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return enc.float(data, rv.Float())
+	}
+
+	// as json for anything else, as with kv
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return enc.append(data, fmt.Sprintf("%v", val))
+	}
+	return enc.json(data, raw)
+}
+
+func (enc CBOR) json(data, raw []byte) []byte {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var val any
+	err := decoder.Decode(&val)
+	if err != nil {
+		return enc.append(data, string(raw))
+	}
+
+	return enc.append(data, val)
+}
+
+// fields appends a map, keys sorted, or ordered by their encoding when deterministic.
+func (enc CBOR) fields(data []byte, fields map[string]any) []byte {
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if enc.Deterministic && len(keys[i]) != len(keys[j]) {
+			// text keys of differing length encode shorter first, as their head is smaller
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	data = cborHead(data, cborMap, uint64(len(keys)))
+	for _, key := range keys {
+		data = enc.append(data, key)
+		data = enc.append(data, fields[key])
+	}
+
+	return data
+}
+
+// float appends a float64, or when deterministic the shortest of float16, 32, or 64 keeping its value.
+func (enc CBOR) float(data []byte, flt float64) []byte {
+
+	if !enc.Deterministic {
+		return binary.BigEndian.AppendUint64(append(data, cborFloat64), math.Float64bits(flt))
+	}
+
+	if math.IsNaN(flt) {
+		return append(data, cborFloat16, 0x7e, 0x00)
+	}
+
+	f32 := float32(flt)
+	if float64(f32) != flt {
+		return binary.BigEndian.AppendUint64(append(data, cborFloat64), math.Float64bits(flt))
+	}
+
+	half, ok := float16(f32)
+	if ok {
+		return binary.BigEndian.AppendUint16(append(data, cborFloat16), half)
+	}
+
+	return binary.BigEndian.AppendUint32(append(data, cborFloat32), math.Float32bits(f32))
+}
+
+// float16 returns the half precision bits of a float32, when it can be had without loss.
+func float16(f32 float32) (half uint16, ok bool) {
+
+	bits := math.Float32bits(f32)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23&0xff) - 127
+	mant := bits & 0x7fffff
+
+	switch {
+	case bits&0x7fffffff == 0:
+		return sign, true
+	case exp == 128:
+		// infinities, nan being handled by the caller
+		return sign | 0x7c00, mant == 0
+	case exp >= -14 && exp <= 15:
+		if mant&0x1fff != 0 {
+			return 0, false
+		}
+		return sign | uint16(exp+15)<<10 | uint16(mant>>13), true
+	case exp >= -24 && exp < -14:
+		// subnormal, as multiples of 2^-24
+
+		full := mant | 1<<23
+		shift := uint(-exp - 1)
+		if full&(1<<shift-1) != 0 {
+			return 0, false
+		}
+		return sign | uint16(full>>shift), true
+	}
+
+	return 0, false
+}
+
+// cborHead appends the head of a data item, its argument in the fewest bytes.
+func cborHead(data []byte, major byte, arg uint64) []byte {
+
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(data, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(data, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(data, major|26), uint32(arg))
+	}
+
+	return binary.BigEndian.AppendUint64(append(data, major|27), arg)
+}
+
+func cborInt(data []byte, num int64) []byte {
+
+	if num < 0 {
+		return cborHead(data, cborNegint, uint64(-(num + 1)))
+	}
+
+	return cborHead(data, cborUint, uint64(num))
+}
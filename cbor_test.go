@@ -0,0 +1,125 @@
+package sabot
+
+import (
+	"encoding/hex"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CBOR", func() {
+
+	var (
+		evt *Event
+		enc CBOR
+	)
+
+	BeforeEach(func() {
+		evt = &Event{
+			Time:   time.Unix(1700000000, 0),
+			Level:  "info",
+			Msg:    "hi",
+			Fields: Fields{"x": 1.5},
+		}
+	})
+
+	encode := func() string {
+		data, err := enc.Encode(evt)
+		Expect(err).ToNot(HaveOccurred())
+		return hex.EncodeToString(data)
+	}
+
+	When("deterministic", func() {
+		BeforeEach(func() {
+			enc = CBOR{Deterministic: true}
+		})
+
+		It("should order keys by encoding and shorten floats", func() {
+			Expect(encode()).To(Equal("a4" +
+				"6178" + "f93e00" +
+				"627473" + "c11a6553f100" +
+				"636d7367" + "626869" +
+				"656c6576656c" + "64696e666f",
+			))
+		})
+
+		It("should encode equal events alike", func() {
+			evt.Fields = Fields{"b": 1, "a": []any{"x", 2.5}, "cc": group{"z": -1, "y": nil}}
+			first := encode()
+			for range 10 {
+				Expect(encode()).To(Equal(first))
+			}
+		})
+	})
+
+	When("not deterministic", func() {
+		BeforeEach(func() {
+			enc = CBOR{}
+		})
+
+		It("should sort keys and keep floats in full", func() {
+			Expect(encode()).To(Equal("a4" +
+				"656c6576656c" + "64696e666f" +
+				"636d7367" + "626869" +
+				"627473" + "c11a6553f100" +
+				"6178" + "fb3ff8000000000000",
+			))
+		})
+	})
+
+	When("encoding values", func() {
+		BeforeEach(func() {
+			enc = CBOR{}
+		})
+
+		It("should encode each by its major type", func() {
+			evt.Time = time.Unix(1, 500000000)
+			evt.Fields = Fields{
+				"n": -500,
+				"b": []byte{0xde, 0xad},
+				"t": []string{"a"},
+				"o": nested(`{"k":true}`),
+				"z": nil,
+			}
+			Expect(encode()).To(Equal("a8" +
+				"6162" + "42dead" +
+				"656c6576656c" + "64696e666f" +
+				"636d7367" + "626869" +
+				"616e" + "3901f3" +
+				"616f" + "a1616bf5" +
+				"6174" + "816161" +
+				"627473" + "c1fb3ff8000000000000" +
+				"617a" + "f6",
+			))
+		})
+	})
+
+	DescribeTable("shortening floats",
+		func(flt float64, expected string) {
+			data := CBOR{Deterministic: true}.float(nil, flt)
+			Expect(hex.EncodeToString(data)).To(Equal(expected))
+		},
+		Entry("zero", 0.0, "f90000"),
+		Entry("negative zero", math.Copysign(0, -1), "f98000"),
+		Entry("one", 1.0, "f93c00"),
+		Entry("largest half", 65504.0, "f97bff"),
+		Entry("smallest half", 5.960464477539063e-8, "f90001"),
+		Entry("smallest normal half", 0.00006103515625, "f90400"),
+		Entry("single", 100000.0, "fa47c35000"),
+		Entry("double", 1.1, "fb3ff199999999999a"),
+		Entry("infinity", math.Inf(1), "f97c00"),
+		Entry("negative infinity", math.Inf(-1), "f9fc00"),
+		Entry("nan", math.NaN(), "f97e00"),
+	)
+
+	When("configured", func() {
+		It("should pick the encoder, validating deterministic", func() {
+			Expect((&Config{Format: "cbor", Deterministic: true}).New(nil).Encoder).To(Equal(CBOR{Deterministic: true}))
+			Expect((&Config{Format: "json", Deterministic: true}).Validate()).To(
+				MatchError("deterministic not supported by format: json"),
+			)
+		})
+	})
+})
@@ -0,0 +1,124 @@
+package sabot
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Class is the data classification of a field, governing which sinks it may be written to.
+type Class int
+
+const (
+	// Unclassified fields are treated as public.
+	Unclassified Class = iota
+	// Public marks fields fit for any sink.
+	Public
+	// Internal marks fields fit for sinks within the organization.
+	Internal
+	// Confidential marks fields fit only for sinks on the host, such as a local file.
+	Confidential
+)
+
+var classNames = map[Class]string{
+	Unclassified: "",
+	Public:       "public",
+	Internal:     "internal",
+	Confidential: "confidential",
+}
+
+// ParseClass returns the class named, with empty taken as unclassified.
+func ParseClass(name string) (class Class, err error) {
+
+	for class, className := range classNames {
+		if className == name {
+			return class, nil
+		}
+	}
+
+	err = errors.Errorf("unknown class: %s", name)
+	return
+}
+
+// String returns the name of a class.
+func (class Class) String() string {
+
+	name, ok := classNames[class]
+	if !ok {
+		return "unknown"
+	}
+
+	return name
+}
+
+// MarshalText returns the name of a class, for config and json encoding.
+func (class Class) MarshalText() ([]byte, error) {
+
+	return []byte(class.String()), nil
+}
+
+// UnmarshalText sets a class from its name.
+func (class *Class) UnmarshalText(text []byte) (err error) {
+
+	*class, err = ParseClass(string(text))
+	return
+}
+
+// Classified is a Field tagged with a classification, overriding any configured for its key.
+func Classified(key string, val any, class Class) Field {
+
+	return Field{Key: key, Val: hinted{val: val, class: class}}
+}
+
+// Declassify deletes fields classified above max, leaving the event fit for a sink trusted with max.
+//
+// A zero max deletes nothing.
+func (evt *Event) Declassify(max Class) {
+
+	if max == Unclassified {
+		return
+	}
+
+	for key, class := range evt.Classes {
+		if class > max {
+			delete(evt.Fields, key)
+		}
+	}
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) classify(key string, class Class) {
+
+	if sabot.Classes == nil {
+		sabot.Classes = map[string]Class{}
+	}
+	sabot.Classes[key] = class
+}
+
+func (sabot *Sabot) classesFor(fields Fields) (classes map[string]Class) {
+
+	// call site classes take precedence over configured, as with hints
+
+	for key := range fields {
+		class, ok := sabot.Classes[key]
+		if ok {
+			if classes == nil {
+				classes = map[string]Class{}
+			}
+			classes[key] = class
+		}
+	}
+
+	for key, val := range fields {
+		hnt, ok := val.(hinted)
+		if ok && hnt.class != Unclassified {
+			if classes == nil {
+				classes = map[string]Class{}
+			}
+			classes[key] = hnt.class
+		}
+	}
+
+	return
+}
@@ -0,0 +1,92 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Class", func() {
+
+	var (
+		ew  *eventWriter
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ew = &eventWriter{}
+		lgr = (&Config{Internal: []string{"host"}, Confidential: []string{"email"}, Labels: []string{"ssn"}}).New(ew)
+		ctx = context.Background()
+	})
+
+	When("classifying via config and call site", func() {
+		It("should carry classes with the event", func() {
+			ctx = lgr.WithFields(ctx, Classified("ssn", "123-45-6789", Confidential))
+			lgr.Info(ctx, "classifying", "host", "db1", "email", "a@b.c", Classified("host_ip", "10.0.0.1", Internal), "other", 1)
+
+			Expect(ew.events).To(HaveLen(1))
+			evt := ew.events[0]
+			Expect(evt.Fields).To(HaveKeyWithValue("ssn", "123-45-6789"))
+			Expect(evt.Classes).To(Equal(map[string]Class{
+				"host":    Internal,
+				"email":   Confidential,
+				"host_ip": Internal,
+				"ssn":     Confidential,
+			}))
+			Expect(evt.Hints).To(Equal(map[string]Hint{"ssn": LabelHint}))
+		})
+	})
+
+	When("a sink is trusted with internal", func() {
+		It("should delete confidential fields", func() {
+			buf := &bytes.Buffer{}
+			lgr = (&Config{Confidential: []string{"email"}, MaxClass: Internal}).New(buf)
+
+			ctx = lgr.WithFields(ctx, Classified("ssn", "123-45-6789", Confidential))
+			lgr.Info(ctx, "classifying", "email", "a@b.c", Classified("host", "db1", Internal), "other", 1)
+
+			Expect(delog(buf)).To(Equal(Fields{
+				"ts":    "nowish",
+				"level": "info",
+				"msg":   "classifying",
+				"host":  "db1",
+				"other": 1.0,
+			}))
+		})
+
+		It("should delete them from bound fields too", func() {
+			buf := &bytes.Buffer{}
+			lgr = &Sabot{Writer: buf, MaxClass: Internal}
+
+			ctx = lgr.WithFields(ctx, Classified("ssn", "123-45-6789", Confidential), "run_id", "abc")
+			lgr.Bind(ctx).Info("bound")
+
+			logged := delog(buf)
+			Expect(logged).ToNot(HaveKey("ssn"))
+			Expect(logged).To(HaveKeyWithValue("run_id", "abc"))
+		})
+	})
+
+	When("a structured sink declassifies", func() {
+		It("should delete fields above its max", func() {
+			lgr.Info(ctx, "classifying", "host", "db1", "email", "a@b.c", "other", 1)
+
+			evt := ew.events[0]
+			evt.Declassify(Public)
+			Expect(evt.Fields).To(Equal(Fields{"other": 1}))
+		})
+	})
+
+	When("parsing config", func() {
+		It("should take class names", func() {
+			cfg := &Config{}
+			Expect(json.Unmarshal([]byte(`{"max_class": "internal"}`), cfg)).To(Succeed())
+			Expect(cfg.MaxClass).To(Equal(Internal))
+			Expect(json.Unmarshal([]byte(`{"max_class": "secret"}`), cfg)).To(MatchError("unknown class: secret"))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+package sabot
+
+import (
+	"io"
+)
+
+// Option modifies a Sabot, as when cloning.
+type Option func(*Sabot)
+
+// Clone returns an independent copy of Sabot, modified by mutators in order.
+//
+// Slices and maps are copied, so that changes to the clone don't leak back,
+// while hooks and writers, such as a Sampler, are shared.  Stats start afresh.
+func (sabot *Sabot) Clone(mutators ...Option) *Sabot {
+
+	clone := *sabot
+
+	clone.LabelKeys = append([]string(nil), sabot.LabelKeys...)
+	clone.Computed = append([]FieldFunc(nil), sabot.Computed...)
+	clone.Hooks = append([]Hook(nil), sabot.Hooks...)
+	clone.Extractors = append([]Extractor(nil), sabot.Extractors...)
+	clone.ExitHooks = append([]ExitHook(nil), sabot.ExitHooks...)
+	clone.Disabled = copyMap(sabot.Disabled)
+	clone.Routes = copyMap(sabot.Routes)
+	clone.fields = copyMap(sabot.fields)
+
+	// counts are the clone's own
+
+	if sabot.Stats != nil {
+		clone.Stats = &Stats{}
+	}
+	if sabot.Sites != nil {
+		clone.Sites = &SiteStats{}
+	}
+
+	for _, mutate := range mutators {
+		mutate(&clone)
+	}
+
+	return &clone
+}
+
+// WithWriter sets Writer.
+func WithWriter(writer io.Writer) Option {
+
+	return func(sabot *Sabot) {
+		sabot.Writer = writer
+	}
+}
+
+// WithMaxLen sets MaxLen.
+func WithMaxLen(max int) Option {
+
+	return func(sabot *Sabot) {
+		sabot.MaxLen = max
+	}
+}
+
+// WithDisabled disables levels.
+func WithDisabled(levels ...Level) Option {
+
+	return func(sabot *Sabot) {
+		if sabot.Disabled == nil {
+			sabot.Disabled = map[Level]bool{}
+		}
+		for _, lvl := range levels {
+			sabot.Disabled[lvl] = true
+		}
+	}
+}
+
+// WithHooks appends hooks.
+func WithHooks(hooks ...Hook) Option {
+
+	return func(sabot *Sabot) {
+		sabot.Hooks = append(sabot.Hooks, hooks...)
+	}
+}
+
+//
+// unexported
+//
+
+func copyMap[K comparable, V any](src map[K]V) map[K]V {
+
+	if src == nil {
+		return nil
+	}
+
+	dst := make(map[K]V, len(src))
+	for key, val := range src {
+		dst[key] = val
+	}
+
+	return dst
+}
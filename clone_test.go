@@ -0,0 +1,100 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clone", func() {
+
+	var (
+		buf   *bytes.Buffer
+		other *bytes.Buffer
+		lgr   *Sabot
+		clone *Sabot
+		ctx   context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		other = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer:   buf,
+			MaxLen:   99,
+			Disabled: map[Level]bool{Trace: true},
+		}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		clone = lgr.Clone(WithWriter(other), WithMaxLen(20), WithDisabled(Info))
+		clone.Info(ctx, "cloned")
+		clone.Error(ctx, "cloned", nil, "long", "abcdefghijklmnopqrstuvwxyz")
+	})
+
+	When("cloning with options", func() {
+		It("should apply them to the clone only", func() {
+			Expect(buf.Len()).To(Equal(0))
+			Expect(delog(other)["long"]).To(Equal("abcdefg--truncated--"))
+
+			Expect(lgr.MaxLen).To(Equal(99))
+			Expect(lgr.Disabled).To(Equal(map[Level]bool{Trace: true}))
+			Expect(clone.Disabled).To(Equal(map[Level]bool{Trace: true, Info: true}))
+		})
+	})
+
+	When("the clone gains a hook", func() {
+		It("should not add it to the original", func() {
+			lgr.Hooks = make([]Hook, 0, 4)
+			clone = lgr.Clone(WithHooks(func(ctx context.Context, evt *Event) bool { return false }))
+
+			Expect(clone.Hooks).To(HaveLen(1))
+			Expect(lgr.Hooks).To(BeEmpty())
+		})
+	})
+})
+
+var _ = Describe("With", func() {
+
+	var (
+		buf   *bytes.Buffer
+		lgr   *Sabot
+		child *Sabot
+		ctx   context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, Stats: &Stats{}}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		child = lgr.With("worker_id", 7, "queue", "default").With("queue", "priority")
+		child.Info(lgr.WithFields(ctx, "run_id", "123"), "working", "worker_id", 8)
+	})
+
+	When("logging via a child", func() {
+		It("should merge its fields, giving way to kv and ctx", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":     "info",
+				"msg":       "working",
+				"ts":        "nowish",
+				"worker_id": float64(8),
+				"queue":     "priority",
+				"run_id":    "123",
+			}))
+		})
+
+		It("should leave the parent alone and share its stats", func() {
+			buf.Reset()
+			lgr.Info(ctx, "parental")
+			Expect(delog(buf)).ToNot(HaveKey("queue"))
+
+			Expect(lgr.State().Written).To(Equal(int64(2)))
+		})
+	})
+})
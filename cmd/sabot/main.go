@@ -0,0 +1,777 @@
+// Package main is a command line utility for working with sabot ndjson output.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+	"github.com/clarktrimble/sabot/bench"
+	"github.com/clarktrimble/sabot/diff"
+	"github.com/clarktrimble/sabot/ingest"
+	"github.com/clarktrimble/sabot/replay"
+	"github.com/clarktrimble/sabot/sink"
+	"github.com/clarktrimble/sabot/stats"
+	"github.com/clarktrimble/sabot/viewer"
+)
+
+var (
+	version string
+
+	// errUsage is returned by commands given bad arguments, exiting 2 as with a bad flag.
+	errUsage = errors.New("bad arguments")
+)
+
+const usage = `usage: sabot <command> [flags]
+
+commands:
+  record     copy events from stdin to stdout, recording them to a file
+  replay     replay recorded events, paced per their timestamps
+  convert    convert events to another format: json, ecs, logfmt, gelf, pairs, or console
+  ingest     parse legacy plaintext logs into events via regex or grok patterns
+  reveal     decrypt fields encrypted by a field encrypter, for authorized investigation
+  anonymize  redact and hash field values, producing shareable logs
+  view       explore events interactively, following as they're written
+  diff       compare two event streams, reporting events and fields present in one but not the other
+  bench      measure throughput and latency of a logging config, sinks included
+  stats      summarize events by level, msg, and fingerprint, with field percentiles and a timeline
+  version    print version
+`
+
+type command func(ctx context.Context, args []string) error
+
+var commands = map[string]command{
+	"record":    record,
+	"replay":    replayCmd,
+	"convert":   convert,
+	"ingest":    ingestCmd,
+	"anonymize": anonymize,
+	"reveal":    reveal,
+	"view":      view,
+	"stats":     statsCmd,
+	"diff":      diffCmd,
+	"bench":     benchCmd,
+	"version": func(ctx context.Context, args []string) error {
+		fmt.Println(version)
+		return nil
+	},
+}
+
+func main() {
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	err := cmd(ctx, os.Args[2:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sabot %s: %v\n", os.Args[1], err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func record(ctx context.Context, args []string) (err error) {
+
+	flags := flag.NewFlagSet("record", flag.ExitOnError)
+	out := flags.String("out", "recorded.ndjson", "file to record events to, appending")
+	_ = flags.Parse(args)
+
+	file, err := os.OpenFile(*out, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open record file")
+	}
+	defer file.Close()
+
+	_, err = io.Copy(io.MultiWriter(os.Stdout, file), os.Stdin)
+	return errors.Wrapf(err, "failed to record")
+}
+
+func replayCmd(ctx context.Context, args []string) (err error) {
+
+	cfg := &replay.Config{}
+
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	in := flags.String("in", "recorded.ndjson", "file of recorded events")
+	out := flags.String("out", "", "file to replay events to, stdout when empty")
+	flags.Float64Var(&cfg.Speed, "speed", 1, "multiple of recorded pace, zero for as fast as possible")
+	_ = flags.Parse(args)
+
+	src, err := os.Open(*in)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open recorded events")
+	}
+	defer src.Close()
+
+	dst, closeDst, err := output(*out)
+	if err != nil {
+		return
+	}
+	defer closeDst()
+
+	result, err := cfg.New(dst).Replay(ctx, src)
+	fmt.Fprintf(os.Stderr, "replayed %d events, %d bytes, %d errors in %s\n",
+		result.Events, result.Bytes, result.Errors, result.Elapsed)
+
+	return
+}
+
+func convert(ctx context.Context, args []string) (err error) {
+
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	to := flags.String("to", "ecs", "format to convert to: json, ecs, logfmt, gelf, pairs, console, msgpack, or cbor")
+	host := flags.String("host", "", "host for gelf, defaulting to hostname")
+	keys := flags.String("keys", "", "dotted key handling for json and ecs: expand or flatten")
+	columns := flags.String("columns", "", "comma separated field keys whose values lead console lines, as key or key:width")
+	deterministic := flags.Bool("deterministic", false, "encode cbor deterministically, keys ordered by encoding and floats shortest")
+	compact := flags.Bool("compact", false, "hide console fields other than columns, noting their count")
+	theme := flags.String("theme", "", "console color theme: dark or light, dark when empty")
+	colors := flags.String("colors", "", "comma separated class=sgr overriding theme colors, as in warn=1;33,key=2")
+	color := flags.String("color", "", "console color: always, never, or auto when empty, honoring NO_COLOR, FORCE_COLOR, and CLICOLOR")
+	in := flags.String("in", "", "file of events, stdin when empty")
+	out := flags.String("out", "", "file to write converted events to, stdout when empty")
+	_ = flags.Parse(args)
+
+	encoder, ok := sabot.Encoders[*to]
+	if !ok {
+		return errors.Errorf("unknown format: %s", *to)
+	}
+	switch sabot.ColorMode(*color) {
+	case sabot.ColorAuto, sabot.ColorAlways, sabot.ColorNever:
+	default:
+		return errors.Errorf("unknown color mode: %s", *color)
+	}
+
+	switch *to {
+	case "gelf":
+		encoder = sabot.GELF{Host: *host}
+	case "json":
+		encoder = sabot.JSON{Keys: sabot.KeyMode(*keys)}
+	case "ecs":
+		encoder = sabot.ECS{Keys: sabot.KeyMode(*keys)}
+	case "cbor":
+		encoder = sabot.CBOR{Deterministic: *deterministic}
+	}
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	dst, closeDst, err := output(*out)
+	if err != nil {
+		return
+	}
+	defer closeDst()
+
+	if *to == "console" {
+		var cols []sabot.Column
+		cols, err = sabot.ParseColumns(split(*columns))
+		if err != nil {
+			return
+		}
+
+		var thm sabot.Theme
+		thm, err = sabot.NewTheme(*theme, pairs(*colors))
+		if err != nil {
+			return
+		}
+
+		encoder = sabot.Console{Color: sabot.ColorMode(*color).Enabled(dst), Theme: thm, Columns: cols, Compact: *compact}
+	}
+
+	writer := bufio.NewWriter(dst)
+	defer writer.Flush()
+
+	_, isBinary := encoder.(sabot.BinaryEncoder)
+
+	skipped := 0
+	for evt, dErr := range sabot.Decode(src) {
+
+		if isDecodeError(dErr) {
+			skipped++
+			continue
+		}
+		if dErr != nil {
+			return dErr
+		}
+
+		data, eErr := encoder.Encode(&evt)
+		if eErr != nil {
+			skipped++
+			continue
+		}
+
+		if !isBinary {
+			data = append(data, '\n')
+		}
+
+		_, err = writer.Write(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write")
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines not decodable as events\n", skipped)
+	}
+	return
+}
+
+func ingestCmd(ctx context.Context, args []string) (err error) {
+
+	cfg := &ingest.Config{}
+
+	flags := flag.NewFlagSet("ingest", flag.ExitOnError)
+	flags.Func("pattern", "regex or grok pattern, as in %{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} %{GREEDYDATA:msg}, repeatable and tried in order", func(val string) error {
+		cfg.Patterns = append(cfg.Patterns, val)
+		return nil
+	})
+	flags.StringVar(&cfg.TimeLayout, "time-layout", "", "go time layout of the ts capture, common layouts tried when empty")
+	flags.StringVar(&cfg.Level, "level", "", "level of events lacking a level capture, info when empty")
+	flags.BoolVar(&cfg.Multiline, "multiline", false, "append lines matching no pattern to the previous event's msg, as with stack traces")
+	in := flags.String("in", "", "file of plaintext logs, stdin when empty")
+	out := flags.String("out", "", "file to write events to, stdout when empty")
+	_ = flags.Parse(args)
+
+	psr, err := cfg.New()
+	if err != nil {
+		return
+	}
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	dst, closeDst, err := output(*out)
+	if err != nil {
+		return
+	}
+	defer closeDst()
+
+	writer := bufio.NewWriter(dst)
+	defer writer.Flush()
+
+	skipped := 0
+	for evt, pErr := range psr.Parse(src) {
+
+		if isDecodeError(pErr) {
+			skipped++
+			continue
+		}
+		if pErr != nil {
+			return pErr
+		}
+
+		data, eErr := sabot.JSON{}.Encode(&evt)
+		if eErr != nil {
+			skipped++
+			continue
+		}
+
+		_, err = writer.Write(append(data, '\n'))
+		if err != nil {
+			return errors.Wrapf(err, "failed to write")
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines matching no pattern\n", skipped)
+	}
+	return
+}
+
+func anonymize(ctx context.Context, args []string) (err error) {
+
+	rdt := &sabot.Redaction{}
+
+	flags := flag.NewFlagSet("anonymize", flag.ExitOnError)
+	keys := flags.String("redact", "", "comma separated field keys to redact")
+	hash := flags.String("hash", "", "comma separated field keys to hash")
+	flags.StringVar(&rdt.Salt, "salt", "", "salt for hashed values, keep it secret")
+	pseudo := flags.String("pseudonymize", "", "comma separated field keys to pseudonymize with a keyed hmac")
+	flags.StringVar(&rdt.PseudoKey, "pseudo-key", "", "key for pseudonymized values, keep it secret")
+	flags.StringVar(&rdt.PseudoKeyId, "pseudo-key-id", "", "id of key for pseudonymized values, prefixing each")
+	in := flags.String("in", "", "file of events, stdin when empty")
+	out := flags.String("out", "", "file to write anonymized events to, stdout when empty")
+	_ = flags.Parse(args)
+
+	rdt.Keys = split(*keys)
+	rdt.Hash = split(*hash)
+	rdt.Pseudonymize = split(*pseudo)
+
+	if len(rdt.Pseudonymize) > 0 && rdt.PseudoKey == "" {
+		return errors.Errorf("pseudonymize requires a pseudo key")
+	}
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	dst, closeDst, err := output(*out)
+	if err != nil {
+		return
+	}
+	defer closeDst()
+
+	writer := bufio.NewWriter(dst)
+	defer writer.Flush()
+
+	// drop undecodable lines rather than risk passing the unredacted along
+
+	skipped := 0
+	for evt, dErr := range sabot.Decode(src) {
+
+		if isDecodeError(dErr) {
+			skipped++
+			continue
+		}
+		if dErr != nil {
+			return dErr
+		}
+		rdt.Apply(ctx, &evt)
+
+		data, eErr := sabot.JSON{}.Encode(&evt)
+		if eErr != nil {
+			skipped++
+			continue
+		}
+
+		_, err = writer.Write(append(data, '\n'))
+		if err != nil {
+			return errors.Wrapf(err, "failed to write")
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "dropped %d lines not decodable as events\n", skipped)
+	}
+	return
+}
+
+func reveal(ctx context.Context, args []string) (err error) {
+
+	flags := flag.NewFlagSet("reveal", flag.ExitOnError)
+	keys := flags.String("keys", "", "comma separated id=hex of keys encrypting data keys, keep them secret")
+	in := flags.String("in", "", "file of events, stdin when empty")
+	out := flags.String("out", "", "file to write revealed events to, stdout when empty")
+	_ = flags.Parse(args)
+
+	provider := sink.StaticKeys{}
+	for id, hexKey := range pairs(*keys) {
+		provider[id], err = hex.DecodeString(hexKey)
+		if err != nil {
+			return errors.Wrapf(err, "failed to decode key: %s", id)
+		}
+	}
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	dst, closeDst, err := output(*out)
+	if err != nil {
+		return
+	}
+	defer closeDst()
+
+	writer := bufio.NewWriter(dst)
+	defer writer.Flush()
+
+	skipped, undecrypted := 0, 0
+	for evt, dErr := range sabot.Decode(src) {
+
+		if isDecodeError(dErr) {
+			skipped++
+			continue
+		}
+		if dErr != nil {
+			return dErr
+		}
+
+		// pass along as-is events that can't be decrypted, noting them
+
+		rErr := sink.DecryptFields(&evt, provider)
+		if rErr != nil {
+			undecrypted++
+		}
+
+		data, eErr := sabot.JSON{}.Encode(&evt)
+		if eErr != nil {
+			skipped++
+			continue
+		}
+
+		_, err = writer.Write(append(data, '\n'))
+		if err != nil {
+			return errors.Wrapf(err, "failed to write")
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines not decodable as events\n", skipped)
+	}
+	if undecrypted > 0 {
+		fmt.Fprintf(os.Stderr, "left %d events encrypted, lacking their key\n", undecrypted)
+	}
+	return
+}
+
+func view(ctx context.Context, args []string) (err error) {
+
+	flags := flag.NewFlagSet("view", flag.ExitOnError)
+	in := flags.String("in", "", "file of events to view")
+	follow := flags.Bool("follow", true, "follow events as they're written to the file")
+	_ = flags.Parse(args)
+
+	// stdin is for keys, so events come from a file
+
+	if *in == "" {
+		return errors.Errorf("a file of events is required via -in")
+	}
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	height, width, err := termSize()
+	if err != nil {
+		return
+	}
+
+	restore, err := rawMode()
+	if err != nil {
+		return
+	}
+	defer restore()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	viewer.New(width, height, *follow).Run(ctx, viewer.Tail(ctx, src, *follow), os.Stdin, os.Stdout)
+	return
+}
+
+func statsCmd(ctx context.Context, args []string) (err error) {
+
+	cfg := &stats.Config{}
+
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	fields := flags.String("fields", "", "comma separated numeric field keys to summarize with p50 and p95, as in latency_ms")
+	flags.IntVar(&cfg.Top, "top", 10, "number of most frequent msgs and fingerprints shown, all when zero")
+	flags.IntVar(&cfg.Width, "width", 60, "number of buckets in the timeline sparkline, none when zero")
+	in := flags.String("in", "", "file of events, stdin when empty")
+	_ = flags.Parse(args)
+
+	cfg.Fields = split(*fields)
+
+	src, closeSrc, err := input(*in)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	summary := cfg.New()
+
+	skipped := 0
+	for evt, dErr := range sabot.Decode(src) {
+
+		if isDecodeError(dErr) {
+			skipped++
+			continue
+		}
+		if dErr != nil {
+			return dErr
+		}
+
+		summary.Add(evt)
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines not decodable as events\n", skipped)
+	}
+
+	err = summary.Write(os.Stdout)
+	return errors.Wrapf(err, "failed to write summary")
+}
+
+func diffCmd(ctx context.Context, args []string) (err error) {
+
+	cfg := &diff.Config{}
+
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	keys := flags.String("keys", "run_id,seq", "comma separated field keys events are aligned by, along with msg")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: sabot diff [flags] a.log b.log")
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return errors.Wrapf(errUsage, "expected two files, got %d", flags.NArg())
+	}
+	cfg.Keys = split(*keys)
+
+	streams := make([][]sabot.Event, 2)
+	for idx, path := range flags.Args() {
+		streams[idx], err = readEvents(path)
+		if err != nil {
+			return
+		}
+	}
+
+	result := cfg.New().Diff(streams[0], streams[1])
+
+	err = result.Write(os.Stdout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write result")
+	}
+
+	// exit non-zero as diff does when there are differences
+
+	if !result.Same() {
+		return errors.Errorf("streams differ")
+	}
+	return
+}
+
+func benchCmd(ctx context.Context, args []string) (err error) {
+
+	cfg := &bench.Config{}
+
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	config := flags.String("config", "", "json file of sabot config as for the service, defaults when empty")
+	file := flags.String("file", "", "json file of file sink config as for the service, writing to it rather than -out")
+	out := flags.String("out", "", "file to write events to, discarding them when empty")
+	flags.DurationVar(&cfg.Duration, "duration", 5*time.Second, "how long events are logged for")
+	flags.IntVar(&cfg.Workers, "workers", 1, "number of goroutines logging concurrently")
+	flags.IntVar(&cfg.Fields, "fields", 8, "number of kv fields logged with each event")
+	flags.IntVar(&cfg.ValueLen, "value-len", 32, "length of each field's value")
+	flags.IntVar(&cfg.Rate, "rate", 0, "target events per second across workers, zero for as fast as possible")
+	_ = flags.Parse(args)
+
+	lgrCfg := &sabot.Config{}
+	err = readJson(*config, lgrCfg)
+	if err != nil {
+		return
+	}
+	err = lgrCfg.Validate()
+	if err != nil {
+		return
+	}
+
+	var writer io.Writer = io.Discard
+	switch {
+	case *file != "":
+		fileCfg := &sink.FileConfig{}
+		err = readJson(*file, fileCfg)
+		if err != nil {
+			return
+		}
+
+		var fl *sink.File
+		fl, err = fileCfg.New()
+		if err != nil {
+			return
+		}
+		defer fl.Close()
+		writer = fl
+	case *out != "":
+		var closeDst func()
+		writer, closeDst, err = output(*out)
+		if err != nil {
+			return
+		}
+		defer closeDst()
+	}
+
+	result, err := cfg.New(lgrCfg.New(writer)).Run(ctx)
+	if err != nil {
+		return
+	}
+
+	fmt.Println(result)
+	return
+}
+
+// readJson unmarshals a json file into obj, leaving it be when path is empty.
+func readJson(path string, obj any) (err error) {
+
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", path)
+	}
+
+	err = json.Unmarshal(data, obj)
+	return errors.Wrapf(err, "failed to unmarshal %s", path)
+}
+
+// readEvents reads all the events in a file, noting any lines skipped.
+func readEvents(path string) (events []sabot.Event, err error) {
+
+	src, closeSrc, err := input(path)
+	if err != nil {
+		return
+	}
+	defer closeSrc()
+
+	skipped := 0
+	for evt, dErr := range sabot.Decode(src) {
+
+		if isDecodeError(dErr) {
+			skipped++
+			continue
+		}
+		if dErr != nil {
+			return nil, dErr
+		}
+
+		events = append(events, evt)
+	}
+
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "skipped %d lines of %s not decodable as events\n", skipped, path)
+	}
+	return
+}
+
+// termSize gets the size of the terminal on stdin via stty.
+func termSize() (rows, cols int, err error) {
+
+	out, err := stty("size")
+	if err != nil {
+		return
+	}
+
+	_, err = fmt.Sscan(out, &rows, &cols)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to parse terminal size: %s", out)
+	}
+	return
+}
+
+// rawMode puts the terminal on stdin in raw mode via stty, returning a func restoring it.
+func rawMode() (restore func(), err error) {
+
+	saved, err := stty("-g")
+	if err != nil {
+		return
+	}
+
+	_, err = stty("raw", "-echo")
+	if err != nil {
+		return
+	}
+
+	return func() { _, _ = stty(strings.TrimSpace(saved)) }, nil
+}
+
+func stty(args ...string) (out string, err error) {
+
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+
+	data, err := cmd.Output()
+	if err != nil {
+		err = errors.Wrapf(err, "failed to run stty %s, is stdin a terminal", strings.Join(args, " "))
+	}
+
+	return string(data), err
+}
+
+func split(csv string) (items []string) {
+
+	for _, item := range strings.Split(csv, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return
+}
+
+func pairs(csv string) (kv map[string]string) {
+
+	kv = map[string]string{}
+	for _, item := range split(csv) {
+		key, val, _ := strings.Cut(item, "=")
+		kv[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+
+	return
+}
+
+func isDecodeError(err error) bool {
+
+	var decErr *sabot.DecodeError
+	return errors.As(err, &decErr)
+}
+
+func input(path string) (reader io.Reader, closer func(), err error) {
+
+	if path == "" {
+		return os.Stdin, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to open input file")
+		return
+	}
+
+	return file, func() { _ = file.Close() }, nil
+}
+
+func output(path string) (writer io.Writer, closer func(), err error) {
+
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to open output file")
+		return
+	}
+
+	return file, func() { _ = file.Close() }, nil
+}
@@ -0,0 +1,30 @@
+package sabot
+
+import (
+	"context"
+)
+
+// FieldFunc computes a field at log time.
+type FieldFunc func(ctx context.Context) (key string, val any)
+
+//
+// unexported
+//
+
+func (sabot *Sabot) compute(ctx context.Context) (fields Fields) {
+
+	fields = Fields{}
+	for _, fn := range sabot.Computed {
+
+		key, val := fn(ctx)
+		if key == "" {
+			continue
+		}
+
+		// ignoring err, marshalUnknown provides it's own logerror value
+
+		fields[key], _ = marshalUnknown(val)
+	}
+
+	return
+}
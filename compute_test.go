@@ -0,0 +1,61 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Computed", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+		kv  []any
+	)
+
+	BeforeEach(func() {
+		depth := 3
+
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer: buf,
+			Computed: []FieldFunc{
+				func(ctx context.Context) (string, any) { return "queue_depth", depth },
+				func(ctx context.Context) (string, any) { return "", "skipped" },
+				func(ctx context.Context) (string, any) { return "flags", []string{"beta"} },
+			},
+		}
+		ctx = context.Background()
+		kv = nil
+	})
+
+	JustBeforeEach(func() {
+		lgr.Info(ctx, "computing", kv...)
+	})
+
+	When("computing fields", func() {
+		It("should include them", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":       "info",
+				"msg":         "computing",
+				"ts":          "nowish",
+				"queue_depth": float64(3),
+				"flags":       `["beta"]`,
+			}))
+		})
+	})
+
+	When("kv duplicates a computed key", func() {
+		BeforeEach(func() {
+			kv = []any{"queue_depth", 5}
+		})
+
+		It("should prefer kv", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("queue_depth", float64(5)))
+		})
+	})
+})
@@ -0,0 +1,227 @@
+package sabot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const consoleTime string = "15:04:05.000"
+
+// Console encodes events for reading in a terminal, optionally in color.
+//
+// A line begins with time, level, and msg, followed by the values of any Columns,
+// then remaining fields as logfmt sorted by key, with any error's trace and stack on lines of their own.
+type Console struct {
+	// Color determines if levels and keys are set apart with ansi colors.
+	Color bool
+	// Theme is the colors used, dark when nil.
+	Theme Theme
+	// Columns are fields whose values are shown up front, in order, as for access log style lines.
+	Columns []Column
+	// Compact determines if fields other than Columns are hidden, leaving a count in their stead.
+	Compact bool
+}
+
+// Column is a field shown up front by Console.
+type Column struct {
+	// Key is the field's key.
+	Key string
+	// Width is the width the value is padded or cut to, zero for as-is.
+	Width int
+}
+
+// ParseColumns parses column specs given as key or key:width, as when configured.
+func ParseColumns(specs []string) (columns []Column, err error) {
+
+	for _, spec := range specs {
+
+		key, width, ok := strings.Cut(spec, ":")
+		column := Column{Key: key}
+		if ok {
+			column.Width, err = strconv.Atoi(width)
+			if err != nil || column.Width < 0 {
+				return nil, errors.Errorf("invalid column width: %s", spec)
+			}
+		}
+		if column.Key == "" {
+			return nil, errors.Errorf("column key is empty: %s", spec)
+		}
+
+		columns = append(columns, column)
+	}
+
+	return
+}
+
+// Encode encodes an event.
+func (enc Console) Encode(evt *Event) (data []byte, err error) {
+
+	buf := &bytes.Buffer{}
+
+	buf.WriteString(evt.Time.Format(consoleTime))
+	buf.WriteByte(' ')
+	enc.paint(buf, enc.theme().levelClass(evt.Level), strings.ToUpper(padLevel(evt.Level)))
+	buf.WriteByte(' ')
+	buf.WriteString(evt.Msg)
+
+	inColumn := make(map[string]bool, len(enc.Columns)+2)
+	inColumn["error"] = true
+	inColumn[stackKey] = true
+
+	for _, column := range enc.Columns {
+		inColumn[column.Key] = true
+
+		val := "-"
+		if fv, ok := evt.Fields[column.Key]; ok {
+			val = stringify(fv)
+		}
+
+		buf.WriteByte(' ')
+		enc.paint(buf, "column", fitWidth(val, column.Width))
+	}
+
+	keys := make([]string, 0, len(evt.Fields))
+	for key := range evt.Fields {
+		if !inColumn[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if enc.Compact && len(keys) > 0 {
+		buf.WriteByte(' ')
+		enc.paint(buf, "count", fmt.Sprintf("(+%d)", len(keys)))
+		keys = nil
+	}
+
+	for _, key := range keys {
+		buf.WriteByte(' ')
+		enc.paint(buf, "key", logfmtKey(key)+"=")
+		writeValue(buf, stringify(evt.Fields[key]))
+	}
+
+	for _, key := range []string{"error", stackKey} {
+		trace, ok := evt.Fields[key]
+		if ok {
+			buf.WriteString("\n  ")
+			enc.paint(buf, "stack", strings.ReplaceAll(stringify(trace), "\n", "\n  "))
+		}
+	}
+
+	data = buf.Bytes()
+	return
+}
+
+// ColorMode is whether the console encoder colors its output.
+type ColorMode string
+
+const (
+	// ColorAuto colors for a terminal, honoring the FORCE_COLOR, NO_COLOR, and CLICOLOR conventions.
+	ColorAuto ColorMode = ""
+	// ColorAlways colors regardless.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors.
+	ColorNever ColorMode = "never"
+)
+
+// Enabled reports whether output to writer is colored.
+//
+// When auto, FORCE_COLOR or CLICOLOR_FORCE set to other than "0" or "false" force color on,
+// then NO_COLOR set to anything or CLICOLOR set to "0" force it off, and otherwise it's on for a terminal.
+func (mode ColorMode) Enabled(writer io.Writer) bool {
+
+	switch mode {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+
+	switch {
+	case envOn("FORCE_COLOR"), envOn("CLICOLOR_FORCE"):
+		return true
+	case os.Getenv("NO_COLOR") != "", os.Getenv("CLICOLOR") == "0":
+		return false
+	}
+
+	return isTerminal(writer)
+}
+
+//
+// unexported
+//
+
+func (enc Console) paint(buf *bytes.Buffer, class, str string) {
+
+	if !enc.Color {
+		buf.WriteString(str)
+		return
+	}
+
+	enc.theme().paint(buf, class, str)
+}
+
+func (enc Console) theme() Theme {
+
+	if enc.Theme == nil {
+		return defaultTheme
+	}
+
+	return enc.Theme
+}
+
+// fitWidth pads or cuts a value to width, by rune, marking a cut with an ellipsis.
+func fitWidth(val string, width int) string {
+
+	if width == 0 {
+		return val
+	}
+
+	runes := []rune(val)
+	switch {
+	case len(runes) > width:
+		return string(runes[:width-1]) + "…"
+	case len(runes) < width:
+		return val + strings.Repeat(" ", width-len(runes))
+	}
+
+	return val
+}
+
+func padLevel(level string) string {
+
+	if len(level) >= 5 {
+		return level
+	}
+
+	return level + strings.Repeat(" ", 5-len(level))
+}
+
+func envOn(name string) bool {
+
+	val, ok := os.LookupEnv(name)
+	return ok && val != "0" && val != "false"
+}
+
+// isTerminal reports whether writer is a character device, such as a terminal, rather than a file or pipe.
+func isTerminal(writer io.Writer) bool {
+
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,57 @@
+package debuglog
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const defaultHeader = "X-Debug-Capture"
+
+// Capture is middleware turning on full capture, via CaptureAll, for requests bearing Header.
+//
+// Captured events are retrieved by handlers downstream with the logger's Captured.
+// The header must carry Token, so that callers at large can't flood the logs, and nothing is captured without one.
+type Capture struct {
+	// Logger is the logger capturing.
+	Logger *sabot.Sabot
+	// Header is the request header triggering capture, defaulting to X-Debug-Capture.
+	Header string
+	// Token is the value the header must carry, capture being denied when empty.
+	Token string
+	// Next is the handler wrapped.
+	Next http.Handler
+}
+
+// ServeHTTP calls Next, with a capturing ctx when triggered.
+func (cpt *Capture) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+
+	if cpt.triggered(request) {
+		ctx := cpt.Logger.CaptureAll(request.Context())
+		request = request.WithContext(ctx)
+	}
+
+	cpt.Next.ServeHTTP(writer, request)
+}
+
+//
+// unexported
+//
+
+func (cpt *Capture) triggered(request *http.Request) bool {
+
+	header := cpt.Header
+	if header == "" {
+		header = defaultHeader
+	}
+
+	// deny when no token is configured, lest any caller turn on capture
+
+	val := request.Header.Get(header)
+	if val == "" || cpt.Token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(val), []byte(cpt.Token)) == 1
+}
@@ -0,0 +1,80 @@
+package debuglog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+var _ = Describe("Capture", func() {
+
+	var (
+		lgr      *sabot.Sabot
+		cpt      *Capture
+		captured [][]byte
+	)
+
+	BeforeEach(func() {
+		lgr = &sabot.Sabot{Writer: &bytes.Buffer{}, MinLevel: sabot.Warn}
+		captured = nil
+
+		cpt = &Capture{
+			Logger: lgr,
+			Token:  "sekret",
+			Next: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				lgr.Debug(request.Context(), "handling")
+				captured, _ = lgr.Captured(request.Context())
+			}),
+		}
+	})
+
+	serve := func(header, val string) {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			request.Header.Set(header, val)
+		}
+		cpt.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	When("the request carries the token", func() {
+		It("should capture", func() {
+			serve("X-Debug-Capture", "sekret")
+			Expect(captured).To(HaveLen(1))
+		})
+	})
+
+	When("the request carries another token", func() {
+		It("should not capture", func() {
+			serve("X-Debug-Capture", "guess")
+			Expect(captured).To(BeEmpty())
+		})
+	})
+
+	When("the request lacks the header", func() {
+		It("should not capture", func() {
+			serve("", "")
+			Expect(captured).To(BeEmpty())
+		})
+	})
+
+	When("a custom header is configured", func() {
+		It("should capture with the token", func() {
+			cpt.Header = "X-Trace-All"
+			serve("X-Trace-All", "sekret")
+			Expect(captured).To(HaveLen(1))
+		})
+	})
+
+	When("no token is configured", func() {
+		It("should not capture", func() {
+			cpt.Token = ""
+			serve("X-Debug-Capture", "1")
+			Expect(captured).To(BeEmpty())
+		})
+	})
+})
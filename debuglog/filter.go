@@ -0,0 +1,126 @@
+package debuglog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Filter selects events.
+type Filter struct {
+	// Level, when not empty, selects events at or above the given level, built-in or registered.
+	Level string
+	// Since, when not zero, selects events at or after.
+	Since time.Time
+	// Fields, when not empty, selects events having each key with the given value.
+	Fields map[string]string
+}
+
+// ParseFilter parses a Filter from query values such as "level=error&since=5m&field=run_id:123".
+func ParseFilter(values url.Values) (flt Filter, err error) {
+
+	flt.Level = values.Get("level")
+	if flt.Level != "" {
+		_, err = sabot.ParseLevel(flt.Level)
+		if err != nil {
+			return
+		}
+	}
+
+	since := values.Get("since")
+	if since != "" {
+		var dur time.Duration
+		dur, err = time.ParseDuration(since)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to parse since")
+			return
+		}
+		flt.Since = time.Now().Add(-dur)
+	}
+
+	for _, field := range values["field"] {
+		key, val, ok := strings.Cut(field, ":")
+		if !ok {
+			err = errors.Errorf("field not of the form key:value: %s", field)
+			return
+		}
+
+		if flt.Fields == nil {
+			flt.Fields = map[string]string{}
+		}
+		flt.Fields[key] = val
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+type entry struct {
+	data  []byte
+	level string
+	ts    time.Time
+}
+
+func newEntry(data []byte) (ent entry) {
+
+	// copy data as writers are not to retain and parse what's needed for filtering
+
+	ent.data = append([]byte{}, data...)
+
+	head := struct {
+		Level string `json:"level"`
+		Ts    any    `json:"ts"`
+	}{}
+	_ = json.Unmarshal(data, &head)
+
+	ent.level = head.Level
+	ent.ts, _ = sabot.ParseTs(head.Ts)
+	return
+}
+
+func (flt Filter) match(ent entry) bool {
+
+	if !flt.Since.IsZero() && ent.ts.Before(flt.Since) {
+		return false
+	}
+
+	if flt.Level != "" && rank(ent.level) < rank(flt.Level) {
+		return false
+	}
+
+	if len(flt.Fields) == 0 {
+		return true
+	}
+
+	fields := map[string]any{}
+	_ = json.Unmarshal(ent.data, &fields)
+
+	for key, val := range flt.Fields {
+		if fmt.Sprintf("%v", fields[key]) != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+func rank(level string) sabot.Level {
+
+	// unknown levels are taken as info
+
+	lvl, err := sabot.ParseLevel(level)
+	if err != nil {
+		return sabot.Info
+	}
+
+	return lvl
+}
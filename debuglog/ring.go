@@ -0,0 +1,95 @@
+// Package debuglog implements in-process sinks, and middleware for capturing requests, for looking at events over http.
+package debuglog
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Config is the configurable fields of Ring.
+type Config struct {
+	Size int `json:"size" desc:"number of most recent events retained"`
+}
+
+// New creates a Ring from Config.
+func (cfg *Config) New() *Ring {
+
+	return &Ring{
+		entries: make([]entry, 0, cfg.Size),
+		size:    cfg.Size,
+	}
+}
+
+// Ring is an io.Writer sink retaining the most recent events in memory.
+//
+// It is an http.Handler as well, serving retained events as ndjson,
+// a flight recorder of sorts for services without central logging.
+type Ring struct {
+	entries []entry
+	size    int
+	next    int
+	mu      sync.Mutex
+}
+
+// Write retains a single event, displacing the oldest when full.
+func (ring *Ring) Write(data []byte) (n int, err error) {
+
+	if ring.size < 1 {
+		return len(data), nil
+	}
+
+	ent := newEntry(data)
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if len(ring.entries) < ring.size {
+		ring.entries = append(ring.entries, ent)
+	} else {
+		ring.entries[ring.next] = ent
+	}
+	ring.next = (ring.next + 1) % ring.size
+
+	return len(data), nil
+}
+
+// Events returns retained events matching flt, oldest first.
+func (ring *Ring) Events(flt Filter) (events [][]byte) {
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	events = [][]byte{}
+
+	start := 0
+	if len(ring.entries) == ring.size {
+		start = ring.next
+	}
+
+	for i := range ring.entries {
+		ent := ring.entries[(start+i)%len(ring.entries)]
+		if flt.match(ent) {
+			events = append(events, ent.data)
+		}
+	}
+
+	return
+}
+
+// ServeHTTP writes retained events as ndjson, filtered by query params such as "?level=error&since=5m".
+func (ring *Ring) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+
+	flt, err := ParseFilter(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/x-ndjson")
+	for _, data := range ring.Events(flt) {
+		_, err = writer.Write(data)
+		if err != nil {
+			return
+		}
+	}
+}
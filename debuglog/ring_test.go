@@ -0,0 +1,96 @@
+package debuglog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestDebugLog(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "DebugLog Suite")
+}
+
+var _ = Describe("Ring", func() {
+
+	var (
+		ring *Ring
+		lgr  *sabot.Sabot
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		ring = (&Config{Size: 2}).New()
+		lgr = &sabot.Sabot{Writer: ring}
+		ctx = context.Background()
+	})
+
+	Describe("retaining events", func() {
+
+		When("more events than size are written", func() {
+			BeforeEach(func() {
+				lgr.Info(ctx, "one")
+				lgr.Error(ctx, "two", errors.New("oops"))
+				lgr.Info(ctx, "three")
+			})
+
+			It("should keep the most recent, oldest first", func() {
+				events := ring.Events(Filter{})
+				Expect(events).To(HaveLen(2))
+				Expect(string(events[0])).To(ContainSubstring(`"msg":"two"`))
+				Expect(string(events[1])).To(ContainSubstring(`"msg":"three"`))
+			})
+		})
+	})
+
+	Describe("serving events", func() {
+		var (
+			query string
+			rec   *httptest.ResponseRecorder
+		)
+
+		BeforeEach(func() {
+			lgr.Info(ctx, "one")
+			lgr.Error(ctx, "two", errors.New("oops"))
+		})
+
+		JustBeforeEach(func() {
+			rec = httptest.NewRecorder()
+			ring.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs"+query, nil))
+		})
+
+		When("filtering by level and since", func() {
+			BeforeEach(func() {
+				query = "?level=error&since=5m"
+			})
+
+			It("should write matching events as ndjson", func() {
+				Expect(rec.Code).To(Equal(http.StatusOK))
+				Expect(rec.Header().Get("Content-Type")).To(Equal("application/x-ndjson"))
+
+				lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+				Expect(lines).To(HaveLen(1))
+				Expect(lines[0]).To(ContainSubstring(`"msg":"two"`))
+			})
+		})
+
+		When("filtering by unknown level", func() {
+			BeforeEach(func() {
+				query = "?level=loud"
+			})
+
+			It("should return bad request", func() {
+				Expect(rec.Code).To(Equal(http.StatusBadRequest))
+				Expect(rec.Body.String()).To(ContainSubstring("unknown level: loud"))
+			})
+		})
+	})
+})
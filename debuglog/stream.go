@@ -0,0 +1,117 @@
+package debuglog
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const defaultBuffer = 99
+
+// Stream is an io.Writer sink relaying events live to connected clients.
+//
+// It is an http.Handler as well, serving events as server-sent events,
+// so that "curl -N /debug/logs/stream?level=error" can stand in for ssh and tail.
+// Events are dropped for clients not keeping up.
+type Stream struct {
+	// Buffer is the number of events queued for each client, defaulting to 99.
+	Buffer int
+
+	subs map[chan entry]struct{}
+	mu   sync.Mutex
+}
+
+// Write relays a single event to each connected client.
+func (stream *Stream) Write(data []byte) (n int, err error) {
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if len(stream.subs) == 0 {
+		return len(data), nil
+	}
+
+	ent := newEntry(data)
+	for sub := range stream.subs {
+		select {
+		case sub <- ent:
+		default:
+		}
+	}
+
+	return len(data), nil
+}
+
+// ServeHTTP streams events matching query params, such as "?level=error&field=run_id:123",
+// until the client disconnects.
+func (stream *Stream) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+
+	flt, err := ParseFilter(request.URL.Query())
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	sub := stream.subscribe()
+	defer stream.unsubscribe(sub)
+
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case ent := <-sub:
+			if !flt.match(ent) {
+				continue
+			}
+
+			_, err = fmt.Fprintf(writer, "data: %s\n\n", bytes.TrimSpace(ent.data))
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+//
+// unexported
+//
+
+func (stream *Stream) subscribe() (sub chan entry) {
+
+	size := stream.Buffer
+	if size < 1 {
+		size = defaultBuffer
+	}
+	sub = make(chan entry, size)
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if stream.subs == nil {
+		stream.subs = map[chan entry]struct{}{}
+	}
+	stream.subs[sub] = struct{}{}
+
+	return
+}
+
+func (stream *Stream) unsubscribe(sub chan entry) {
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	delete(stream.subs, sub)
+}
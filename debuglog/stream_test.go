@@ -0,0 +1,76 @@
+package debuglog
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+var _ = Describe("Stream", func() {
+
+	var (
+		stream *Stream
+		lgr    *sabot.Sabot
+		srv    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		stream = &Stream{}
+		lgr = &sabot.Sabot{Writer: stream}
+
+		srv = httptest.NewServer(stream)
+		DeferCleanup(srv.Close)
+	})
+
+	Describe("streaming events", func() {
+		var (
+			query string
+			resp  *http.Response
+		)
+
+		JustBeforeEach(func() {
+			var err error
+			resp, err = http.Get(srv.URL + query) //nolint: noctx
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+		})
+
+		When("filtering by level and field", func() {
+			BeforeEach(func() {
+				query = "?level=error&field=run_id:123"
+			})
+
+			It("should relay only matching events", func() {
+				Expect(resp.StatusCode).To(Equal(http.StatusOK))
+				Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+				ctx := lgr.WithFields(context.Background(), "run_id", "123")
+				lgr.Info(ctx, "one")
+				lgr.Error(lgr.WithFields(context.Background(), "run_id", "456"), "two", errors.New("oops"))
+				lgr.Error(ctx, "three", errors.New("oops"))
+
+				line, err := bufio.NewReader(resp.Body).ReadString('\n')
+				Expect(err).ToNot(HaveOccurred())
+				Expect(line).To(HavePrefix("data: {"))
+				Expect(line).To(ContainSubstring(`"msg":"three"`))
+			})
+		})
+
+		When("field filter is malformed", func() {
+			BeforeEach(func() {
+				query = "?field=run_id"
+			})
+
+			It("should return bad request", func() {
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
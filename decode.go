@@ -0,0 +1,124 @@
+package sabot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/pkg/errors"
+)
+
+const maxLine = 16 * 1024 * 1024
+
+// DecodeError is a line that could not be decoded as an event.
+type DecodeError struct {
+	Line int
+	Err  error
+}
+
+// Error implements error.
+func (de *DecodeError) Error() string {
+
+	return fmt.Sprintf("line %d: %v", de.Line, de.Err)
+}
+
+// Unwrap returns the underlying error.
+func (de *DecodeError) Unwrap() error {
+
+	return de.Err
+}
+
+// Scanner reads events from ndjson, skipping blank lines.
+type Scanner struct {
+	// Upgrades are applied to each decoded event, as with Migrate.
+	Upgrades []Upgrade
+
+	scanner *bufio.Scanner
+	line    []byte
+	count   int
+}
+
+// NewScanner creates a Scanner reading from src.
+func NewScanner(src io.Reader) *Scanner {
+
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+	return &Scanner{scanner: scanner}
+}
+
+// Scan advances to the next line, returning false at the end of src or on a read error.
+func (scn *Scanner) Scan() bool {
+
+	for scn.scanner.Scan() {
+		scn.count++
+
+		scn.line = bytes.TrimSpace(scn.scanner.Bytes())
+		if len(scn.line) > 0 {
+			return true
+		}
+	}
+
+	scn.line = nil
+	return false
+}
+
+// Bytes returns the current line, valid until the next call to Scan.
+func (scn *Scanner) Bytes() []byte {
+
+	return scn.line
+}
+
+// Event decodes the current line, returning a DecodeError when it's not an event.
+func (scn *Scanner) Event() (evt *Event, err error) {
+
+	evt, err = DecodeEvent(scn.line)
+	if err == nil {
+		err = Migrate(evt, scn.Upgrades)
+	}
+	if err != nil {
+		err = &DecodeError{Line: scn.count, Err: err}
+	}
+
+	return
+}
+
+// Err returns the first read error, if any.
+func (scn *Scanner) Err() error {
+
+	return errors.Wrapf(scn.scanner.Err(), "failed to read events")
+}
+
+// Decode yields events read from ndjson, applying any upgrades.
+//
+// Lines not decodable as events yield a DecodeError and decoding continues,
+// while a read error is yielded last.
+func Decode(src io.Reader, upgrades ...Upgrade) iter.Seq2[Event, error] {
+
+	return func(yield func(Event, error) bool) {
+
+		scn := NewScanner(src)
+		scn.Upgrades = upgrades
+		for scn.Scan() {
+
+			evt, err := scn.Event()
+			if err != nil {
+				if !yield(Event{}, err) {
+					return
+				}
+				continue
+			}
+
+			if !yield(*evt, nil) {
+				return
+			}
+		}
+
+		err := scn.Err()
+		if err != nil {
+			yield(Event{}, err)
+		}
+	}
+}
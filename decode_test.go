@@ -0,0 +1,67 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Decode", func() {
+
+	var (
+		src    *bytes.Buffer
+		events []Event
+		errs   []error
+	)
+
+	BeforeEach(func() {
+		src = &bytes.Buffer{}
+		lgr := &Sabot{Writer: src}
+
+		lgr.Info(context.Background(), "first", "idx", 1)
+		src.WriteString("\nnot json\n")
+		lgr.Error(context.Background(), "second", errors.New("oops"))
+
+		events = nil
+		errs = nil
+	})
+
+	JustBeforeEach(func() {
+		for evt, err := range Decode(src) {
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			events = append(events, evt)
+		}
+	})
+
+	When("decoding a mix of events and junk", func() {
+		It("should yield events and decode errors in order", func() {
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Msg).To(Equal("first"))
+			Expect(events[0].Fields).To(Equal(Fields{"idx": float64(1)}))
+			Expect(events[1].Level).To(Equal("error"))
+			Expect(events[1].Err).To(MatchError("oops"))
+
+			Expect(errs).To(HaveLen(1))
+			var decErr *DecodeError
+			Expect(errors.As(errs[0], &decErr)).To(BeTrue())
+			Expect(decErr.Line).To(Equal(3))
+		})
+	})
+
+	When("scanning raw lines", func() {
+		It("should skip blank lines", func() {
+			scn := NewScanner(strings.NewReader("\n  \n{\"msg\":\"x\"}\n"))
+			Expect(scn.Scan()).To(BeTrue())
+			Expect(string(scn.Bytes())).To(Equal(`{"msg":"x"}`))
+			Expect(scn.Scan()).To(BeFalse())
+			Expect(scn.Err()).ToNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,85 @@
+package sabot
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const dedupSummary string = "repeated errors suppressed"
+
+// WithErrorDedup adds error de-duplication to a given context.
+//
+// Errors logged with the ctx are logged only on first occurrence of the same msg and error,
+// repeats are counted for a summary logged with DedupSummary.
+func (sabot *Sabot) WithErrorDedup(ctx context.Context) context.Context {
+
+	return context.WithValue(ctx, dedupKey{}, &dedup{counts: map[string]int{}})
+}
+
+// DedupSummary logs the count of suppressed repeat errors, if any, for a ctx from WithErrorDedup.
+func (sabot *Sabot) DedupSummary(ctx context.Context) {
+
+	ddp, ok := ctx.Value(dedupKey{}).(*dedup)
+	if !ok {
+		return
+	}
+
+	repeats := ddp.repeats()
+	if len(repeats) == 0 {
+		return
+	}
+
+	sabot.log(ctx, "info", dedupSummary, nil, []any{"repeats", repeats})
+}
+
+//
+// unexported
+//
+
+type dedupKey struct{}
+
+type dedup struct {
+	counts map[string]int
+	order  []string
+	mu     sync.Mutex
+}
+
+func (sabot *Sabot) first(ctx context.Context, msg string, err error) bool {
+
+	ddp, ok := ctx.Value(dedupKey{}).(*dedup)
+	if !ok {
+		return true
+	}
+
+	return ddp.see(fmt.Sprintf("%s: %v", msg, err))
+}
+
+func (ddp *dedup) see(print string) bool {
+
+	ddp.mu.Lock()
+	defer ddp.mu.Unlock()
+
+	ddp.counts[print]++
+	if ddp.counts[print] == 1 {
+		ddp.order = append(ddp.order, print)
+		return true
+	}
+
+	return false
+}
+
+func (ddp *dedup) repeats() (repeats map[string]int) {
+
+	ddp.mu.Lock()
+	defer ddp.mu.Unlock()
+
+	repeats = map[string]int{}
+	for _, print := range ddp.order {
+		if ddp.counts[print] > 1 {
+			repeats[print] = ddp.counts[print] - 1
+		}
+	}
+
+	return
+}
@@ -0,0 +1,55 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WithErrorDedup", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = lgr.WithErrorDedup(context.Background())
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 3; i++ {
+			lgr.Error(ctx, "failed to fetch", fmt.Errorf("timeout"))
+		}
+		lgr.Error(ctx, "failed to fetch", fmt.Errorf("refused"))
+		lgr.DedupSummary(ctx)
+	})
+
+	When("the same error is logged repeatedly", func() {
+		It("should log distinct errors once and summarize repeats", func() {
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(ContainSubstring(`"error":"timeout"`))
+			Expect(lines[1]).To(ContainSubstring(`"error":"refused"`))
+			Expect(lines[2]).To(ContainSubstring(`"msg":"repeated errors suppressed"`))
+			Expect(lines[2]).To(ContainSubstring(`"repeats":"{\"failed to fetch: timeout\":2}"`))
+		})
+	})
+
+	When("ctx has no dedup", func() {
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should log every error and no summary", func() {
+			Expect(strings.Count(buf.String(), "\n")).To(Equal(4))
+		})
+	})
+})
@@ -0,0 +1,175 @@
+// Package diff implements comparison of two sabot event streams,
+// as when validating that a migration's new stream carries what the old one did.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Config is the configurable fields of Differ.
+type Config struct {
+	Keys []string `json:"keys" desc:"field keys events are aligned by, such as run_id and seq"`
+}
+
+// New creates a Differ from Config.
+func (cfg *Config) New() *Differ {
+
+	return &Differ{
+		Keys: cfg.Keys,
+	}
+}
+
+// Differ compares event streams.
+//
+// Events are aligned by the values of Keys and msg, pairing in order of appearance where more than one share them.
+type Differ struct {
+	// Keys are the fields events are aligned by.
+	Keys []string
+}
+
+// Result is the differences between two streams, A and B.
+type Result struct {
+	// Matched is the count of events aligned between streams.
+	Matched int `json:"matched"`
+	// OnlyA are events in A without a counterpart in B.
+	OnlyA []sabot.Event `json:"only_a"`
+	// OnlyB are events in B without a counterpart in A.
+	OnlyB []sabot.Event `json:"only_b"`
+	// Fields are the aligned events whose fields differ.
+	Fields []FieldDiff `json:"fields"`
+}
+
+// FieldDiff is the fields present in one of a pair of aligned events but not the other.
+type FieldDiff struct {
+	// Align is the key values and msg by which the events were aligned.
+	Align string `json:"align"`
+	// OnlyA are the keys of fields in the event from A only.
+	OnlyA []string `json:"only_a"`
+	// OnlyB are the keys of fields in the event from B only.
+	OnlyB []string `json:"only_b"`
+}
+
+// Same reports whether no differences were found.
+func (result Result) Same() bool {
+
+	return len(result.OnlyA) == 0 && len(result.OnlyB) == 0 && len(result.Fields) == 0
+}
+
+// Diff compares streams a and b.
+func (dfr *Differ) Diff(a, b []sabot.Event) (result Result) {
+
+	pending := map[string][]sabot.Event{}
+	for _, evt := range b {
+		align := dfr.align(evt)
+		pending[align] = append(pending[align], evt)
+	}
+
+	for _, evt := range a {
+		align := dfr.align(evt)
+
+		matches := pending[align]
+		if len(matches) == 0 {
+			result.OnlyA = append(result.OnlyA, evt)
+			continue
+		}
+		pending[align] = matches[1:]
+		result.Matched++
+
+		onlyA, onlyB := fieldDiff(evt.Fields, matches[0].Fields)
+		if len(onlyA) > 0 || len(onlyB) > 0 {
+			result.Fields = append(result.Fields, FieldDiff{Align: align, OnlyA: onlyA, OnlyB: onlyB})
+		}
+	}
+
+	// keep b's order among those left unmatched
+
+	for _, evt := range b {
+		align := dfr.align(evt)
+		if len(pending[align]) > 0 {
+			result.OnlyB = append(result.OnlyB, pending[align][0])
+			pending[align] = pending[align][1:]
+		}
+	}
+
+	return
+}
+
+// Write writes the result as text, in the manner of diff with "<" for A and ">" for B.
+func (result Result) Write(writer io.Writer) (err error) {
+
+	bld := &strings.Builder{}
+
+	for _, evt := range result.OnlyA {
+		fmt.Fprintf(bld, "< %s\n", line(evt))
+	}
+	for _, evt := range result.OnlyB {
+		fmt.Fprintf(bld, "> %s\n", line(evt))
+	}
+	for _, fd := range result.Fields {
+		fmt.Fprintf(bld, "~ %s", fd.Align)
+		for _, key := range fd.OnlyA {
+			fmt.Fprintf(bld, " <%s", key)
+		}
+		for _, key := range fd.OnlyB {
+			fmt.Fprintf(bld, " >%s", key)
+		}
+		bld.WriteByte('\n')
+	}
+
+	fmt.Fprintf(bld, "matched %d, only in a %d, only in b %d, fields differing %d\n",
+		result.Matched, len(result.OnlyA), len(result.OnlyB), len(result.Fields))
+
+	_, err = io.WriteString(writer, bld.String())
+	return
+}
+
+//
+// unexported
+//
+
+func (dfr *Differ) align(evt sabot.Event) string {
+
+	parts := make([]string, 0, len(dfr.Keys)+1)
+	for _, key := range dfr.Keys {
+		val, ok := evt.Fields[key]
+		if !ok {
+			val = "-"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%v", key, val))
+	}
+
+	return strings.Join(append(parts, fmt.Sprintf("msg=%q", evt.Msg)), " ")
+}
+
+func fieldDiff(a, b sabot.Fields) (onlyA, onlyB []string) {
+
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			onlyA = append(onlyA, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			onlyB = append(onlyB, key)
+		}
+	}
+
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return
+}
+
+func line(evt sabot.Event) string {
+
+	data, err := sabot.JSON{}.Encode(&evt)
+	if err != nil {
+		return evt.Msg
+	}
+
+	return string(data)
+}
@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestDiff(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Diff Suite")
+}
+
+var _ = Describe("Differ", func() {
+
+	var (
+		a, b   []sabot.Event
+		result Result
+	)
+
+	JustBeforeEach(func() {
+		result = (&Config{Keys: []string{"run_id"}}).New().Diff(a, b)
+	})
+
+	When("streams are alike", func() {
+		BeforeEach(func() {
+			a = []sabot.Event{
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "n": 1.0}},
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "n": 2.0}},
+			}
+			b = []sabot.Event{
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "n": 1.0}},
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "n": 2.0}},
+			}
+		})
+
+		It("should match all", func() {
+			Expect(result.Matched).To(Equal(2))
+			Expect(result.Same()).To(BeTrue())
+		})
+	})
+
+	When("streams differ", func() {
+		BeforeEach(func() {
+			a = []sabot.Event{
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "email": "bob@example.com"}},
+				{Msg: "done", Fields: sabot.Fields{"run_id": "1"}},
+				{Msg: "working", Fields: sabot.Fields{"run_id": "2"}},
+			}
+			b = []sabot.Event{
+				{Msg: "working", Fields: sabot.Fields{"run_id": "2"}},
+				{Msg: "working", Fields: sabot.Fields{"run_id": "1", "user_id": "bob"}},
+				{Msg: "retrying", Fields: sabot.Fields{"run_id": "1"}},
+			}
+		})
+
+		It("should report events and fields in one only", func() {
+			Expect(result.Matched).To(Equal(2))
+			Expect(result.OnlyA).To(Equal(a[1:2]))
+			Expect(result.OnlyB).To(Equal(b[2:3]))
+			Expect(result.Fields).To(Equal([]FieldDiff{
+				{Align: `run_id=1 msg="working"`, OnlyA: []string{"email"}, OnlyB: []string{"user_id"}},
+			}))
+			Expect(result.Same()).To(BeFalse())
+
+			buf := &bytes.Buffer{}
+			Expect(result.Write(buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`< {"level":"","msg":"done","run_id":"1","ts":"0001-01-01T00:00:00Z"}
+> {"level":"","msg":"retrying","run_id":"1","ts":"0001-01-01T00:00:00Z"}
+~ run_id=1 msg="working" <email >user_id
+matched 2, only in a 1, only in b 1, fields differing 1
+`))
+		})
+	})
+})
@@ -0,0 +1,131 @@
+package sabot
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// Dual runs two loggers side by side, de-risking a migration of format or sink across a fleet.
+//
+// Events are bucketed by the BucketKey field found in ctx, run_id by default,
+// so that the events of a given request go to the same place.
+// Events without it are bucketed at random.
+type Dual struct {
+	// Old is the logger being migrated from.
+	Old *Sabot
+	// New is the logger being migrated to.
+	New *Sabot
+	// Percent is the share of events, zero to one hundred, written to New rather than Old.
+	Percent int
+	// Both determines if Old is written to regardless, with New seeing Percent in addition.
+	Both bool
+	// BucketKey is the ctx field bucketing is based on.
+	BucketKey string
+}
+
+// Info logs info level events.
+func (dual *Dual) Info(ctx context.Context, msg string, kv ...any) {
+
+	for _, lgr := range dual.pick(ctx) {
+		lgr.Info(ctx, msg, kv...)
+	}
+}
+
+// Warn logs warn level events.
+func (dual *Dual) Warn(ctx context.Context, msg string, kv ...any) {
+
+	for _, lgr := range dual.pick(ctx) {
+		lgr.Warn(ctx, msg, kv...)
+	}
+}
+
+// Debug logs debug level events.
+func (dual *Dual) Debug(ctx context.Context, msg string, kv ...any) {
+
+	for _, lgr := range dual.pick(ctx) {
+		lgr.Debug(ctx, msg, kv...)
+	}
+}
+
+// Trace logs trace level events.
+func (dual *Dual) Trace(ctx context.Context, msg string, kv ...any) {
+
+	for _, lgr := range dual.pick(ctx) {
+		lgr.Trace(ctx, msg, kv...)
+	}
+}
+
+// Error logs error level events.
+func (dual *Dual) Error(ctx context.Context, msg string, err error, kv ...any) {
+
+	for _, lgr := range dual.pick(ctx) {
+		lgr.Error(ctx, msg, err, kv...)
+	}
+}
+
+// Fatal logs fatal level events and exits with the code of the last logger picked,
+// running the exit hooks of both Old and New, as either may hold buffered events.
+func (dual *Dual) Fatal(ctx context.Context, msg string, err error, kv ...any) {
+
+	picked := dual.pick(ctx)
+	for _, lgr := range picked {
+		lgr.logFatal(ctx, msg, err, kv)
+	}
+
+	dual.Old.runExitHooks(ctx)
+	if dual.New != dual.Old {
+		dual.New.runExitHooks(ctx)
+	}
+
+	osExit(picked[len(picked)-1].exitCode())
+}
+
+// WithFields adds log fields to a given context.
+func (dual *Dual) WithFields(ctx context.Context, kv ...any) context.Context {
+
+	return withFields(ctx, kv)
+}
+
+// GetFields gets log fields from a given context.
+func (dual *Dual) GetFields(ctx context.Context) Fields {
+
+	return getFields(ctx)
+}
+
+//
+// unexported
+//
+
+func (dual *Dual) pick(ctx context.Context) []*Sabot {
+
+	toNew := dual.bucket(ctx) < dual.Percent
+
+	switch {
+	case toNew && dual.Both:
+		return []*Sabot{dual.Old, dual.New}
+	case toNew:
+		return []*Sabot{dual.New}
+	default:
+		return []*Sabot{dual.Old}
+	}
+}
+
+func (dual *Dual) bucket(ctx context.Context) int {
+
+	key := dual.BucketKey
+	if key == "" {
+		key = runIdKey
+	}
+
+	val, ok := getFields(ctx)[key]
+	if !ok {
+		return rand.Intn(100) //nolint: gosec
+	}
+
+	hash := fnv.New32a()
+	_, _ = fmt.Fprintf(hash, "%v", val)
+
+	return int(hash.Sum32() % 100)
+}
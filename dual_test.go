@@ -0,0 +1,123 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dual", func() {
+
+	var (
+		oldBuf *bytes.Buffer
+		newBuf *bytes.Buffer
+		dual   *Dual
+	)
+
+	BeforeEach(func() {
+		oldBuf = &bytes.Buffer{}
+		newBuf = &bytes.Buffer{}
+		dual = &Dual{
+			Old: &Sabot{Writer: oldBuf},
+			New: &Sabot{Writer: newBuf},
+		}
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 100; i++ {
+			ctx := dual.WithFields(context.Background(), "run_id", fmt.Sprintf("run-%d", i))
+			dual.Info(ctx, "one")
+			dual.Error(ctx, "two", fmt.Errorf("oops"))
+		}
+	})
+
+	When("cutover is at zero", func() {
+		It("should write only to old", func() {
+			Expect(lines(oldBuf)).To(Equal(200))
+			Expect(lines(newBuf)).To(Equal(0))
+		})
+	})
+
+	When("cutover is at one hundred", func() {
+		BeforeEach(func() {
+			dual.Percent = 100
+		})
+
+		It("should write only to new", func() {
+			Expect(lines(oldBuf)).To(Equal(0))
+			Expect(lines(newBuf)).To(Equal(200))
+		})
+	})
+
+	When("cutover is partial", func() {
+		BeforeEach(func() {
+			dual.Percent = 50
+		})
+
+		It("should split by run, keeping a run's events together", func() {
+			Expect(lines(oldBuf) + lines(newBuf)).To(Equal(200))
+			Expect(lines(newBuf)).To(BeNumerically("~", 100, 40))
+			Expect(lines(newBuf) % 2).To(Equal(0))
+		})
+
+		When("writing both", func() {
+			BeforeEach(func() {
+				dual.Both = true
+			})
+
+			It("should write all to old and some to new", func() {
+				Expect(lines(oldBuf)).To(Equal(200))
+				Expect(lines(newBuf)).To(BeNumerically("~", 100, 40))
+			})
+		})
+	})
+
+	When("logging fatal with both", func() {
+		var (
+			exited []int
+			ran    []string
+		)
+
+		BeforeEach(func() {
+			dual.Both = true
+			dual.Percent = 100
+			dual.New.ExitCode = 3
+
+			exited, ran = nil, nil
+			osExit = func(code int) {
+				exited = append(exited, code)
+			}
+			DeferCleanup(func() {
+				osExit = os.Exit
+			})
+
+			dual.Old.OnExit(func(ctx context.Context) error {
+				ran = append(ran, "old")
+				return nil
+			})
+			dual.New.OnExit(func(ctx context.Context) error {
+				ran = append(ran, "new")
+				return nil
+			})
+		})
+
+		It("should run the hooks of each and exit once", func() {
+			dual.Fatal(context.Background(), "cannot continue", fmt.Errorf("oops"))
+
+			Expect(ran).To(Equal([]string{"old", "new"}))
+			Expect(exited).To(Equal([]int{3}))
+			Expect(oldBuf.String()).To(ContainSubstring(`"level":"fatal"`))
+			Expect(newBuf.String()).To(ContainSubstring(`"level":"fatal"`))
+		})
+	})
+})
+
+func lines(buf *bytes.Buffer) int {
+
+	return strings.Count(buf.String(), "\n")
+}
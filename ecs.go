@@ -0,0 +1,45 @@
+package sabot
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+const ecsVersion string = "8.11"
+
+// ECS encodes events as Elastic Common Schema json.
+//
+// Boilerplate becomes @timestamp, log.level, and message, while an error field
+// becomes error.message and error.stack_trace.  Other fields are as-is, unless Keys says otherwise.
+type ECS struct {
+	// Keys is how dotted keys are treated, as-is when empty.
+	Keys KeyMode
+}
+
+// Encode encodes an event.
+func (enc ECS) Encode(evt *Event) (data []byte, err error) {
+
+	fields := make(Fields, len(evt.Fields)+4)
+	for key, val := range evt.Fields {
+		fields[key] = val
+	}
+
+	fields["@timestamp"] = evt.ts()
+	fields["log.level"] = evt.Level
+	fields["message"] = evt.Msg
+	fields["ecs.version"] = ecsVersion
+
+	trace, ok := fields["error"].(string)
+	if ok {
+		delete(fields, "error")
+		fields["error.message"] = firstLine(trace)
+		if trace != fields["error.message"] {
+			fields["error.stack_trace"] = trace
+		}
+	}
+
+	data, err = json.Marshal(enc.Keys.apply(fields))
+	err = errors.Wrapf(err, "failed to marshal ecs event")
+	return
+}
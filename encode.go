@@ -0,0 +1,177 @@
+package sabot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Encoder encodes events for writing, sans trailing newline.
+type Encoder interface {
+	Encode(evt *Event) (data []byte, err error)
+}
+
+// BinaryEncoder is implemented by encoders of self-delimiting binary formats,
+// whose events are written as encoded rather than as newline terminated lines.
+type BinaryEncoder interface {
+	Encoder
+	Binary()
+}
+
+// Encoders are the available encoders by format name.
+var Encoders = map[string]Encoder{
+	"json":    JSON{},
+	"ecs":     ECS{},
+	"logfmt":  Logfmt{},
+	"gelf":    GELF{},
+	"pairs":   Pairs{},
+	"console": Console{Color: true},
+	"msgpack": Msgpack{},
+	"cbor":    CBOR{},
+}
+
+// JSON encodes events as a flat json object, Sabot's default.
+type JSON struct {
+	// Keys is how dotted keys are treated, as-is when empty.
+	Keys KeyMode
+}
+
+// Encode encodes an event.
+func (enc JSON) Encode(evt *Event) (data []byte, err error) {
+
+	data, err = json.Marshal(enc.Keys.apply(evt.Merged()))
+	err = errors.Wrapf(err, "failed to marshal event")
+	return
+}
+
+// DecodeEvent decodes an event from json as encoded by Sabot by default.
+//
+// The error field, when present, is kept in Fields and also set as Err.
+// A numeric ts is taken as epoch milliseconds, as encoded per TimeUnixMilli.
+func DecodeEvent(data []byte) (evt *Event, err error) {
+
+	fields := Fields{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to unmarshal event")
+		return
+	}
+
+	evt = &Event{Fields: fields}
+
+	var ok bool
+	evt.Msg, ok = fields["msg"].(string)
+	if !ok {
+		err = errors.Errorf("event has no msg")
+		return
+	}
+
+	evt.Level, _ = fields["level"].(string)
+
+	evt.Time, err = ParseTs(fields["ts"])
+	if err != nil {
+		return
+	}
+
+	for _, key := range []string{"msg", "level", "ts"} {
+		delete(fields, key)
+	}
+
+	msg, ok := fields["error"].(string)
+	if ok {
+		evt.Err = errors.New(msg)
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) encode(evt *Event) (data []byte, err error) {
+
+	if sabot.Encoder != nil {
+		return sabot.Encoder.Encode(evt)
+	}
+
+	// skip the copy in Merged as the event goes no further
+
+	fields := evt.Fields
+	evt.boilerplate(fields)
+
+	return json.Marshal(fields)
+}
+
+func (cfg *Config) encoder(writer io.Writer) Encoder {
+
+	format := cfg.Format
+
+	// auto is console for a terminal, where a person is reading, and json otherwise
+
+	if format == "auto" {
+		format = "json"
+		if isTerminal(writer) {
+			format = "console"
+		}
+	}
+
+	switch format {
+	case "console":
+		// columns and theme are checked by Validate
+		columns, _ := ParseColumns(cfg.Columns)
+		enc := Console{Color: cfg.Color.Enabled(writer), Columns: columns, Compact: cfg.Compact}
+		if cfg.Theme != "" || len(cfg.ThemeColors) > 0 {
+			enc.Theme, _ = NewTheme(cfg.Theme, cfg.ThemeColors)
+		}
+		return enc
+	case "", "json":
+		if cfg.Keys == KeysAsIs {
+			return nil
+		}
+		return JSON{Keys: cfg.Keys}
+	case "ecs":
+		return ECS{Keys: cfg.Keys}
+	case "cbor":
+		return CBOR{Deterministic: cfg.Deterministic}
+	case "gelf":
+		return GELF{Host: hostname()}
+	}
+
+	return Encoders[format]
+}
+
+func stringify(val any) string {
+
+	switch val := val.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case []byte:
+		// as bytes are rendered when logged, should they be set by a hook afterwards
+		return BytesBase64.render(val)
+	case json.RawMessage:
+		return string(val)
+	case nested:
+		return string(val)
+	case group, []string:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+func firstLine(str string) string {
+
+	line, _, _ := strings.Cut(str, "\n")
+	return line
+}
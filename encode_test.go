@@ -0,0 +1,324 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encoders", func() {
+
+	var (
+		evt  *Event
+		data []byte
+		err  error
+	)
+
+	BeforeEach(func() {
+		evt = &Event{
+			Time:  time.Date(2023, 11, 25, 21, 20, 54, 758434441, time.UTC),
+			Level: "error",
+			Msg:   "failed to, you know ..",
+			Fields: Fields{
+				"run_id": "123123123",
+				"error":  "oops\nmain.main\n\tmain.go:38",
+				"count":  3,
+				"id":     "abc",
+			},
+		}
+	})
+
+	Describe("encoding as ecs", func() {
+		JustBeforeEach(func() {
+			data, err = ECS{}.Encode(evt)
+		})
+
+		It("should rename boilerplate and error", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unjson(data)).To(Equal(map[string]any{
+				"@timestamp":        "2023-11-25T21:20:54.758434441Z",
+				"log.level":         "error",
+				"message":           "failed to, you know ..",
+				"ecs.version":       "8.11",
+				"error.message":     "oops",
+				"error.stack_trace": "oops\nmain.main\n\tmain.go:38",
+				"run_id":            "123123123",
+				"count":             float64(3),
+				"id":                "abc",
+			}))
+		})
+	})
+
+	Describe("encoding as logfmt", func() {
+		JustBeforeEach(func() {
+			data, err = Logfmt{}.Encode(evt)
+		})
+
+		It("should write boilerplate then sorted pairs, quoting as needed", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(
+				`ts=2023-11-25T21:20:54.758434441Z level=error msg="failed to, you know .." ` +
+					`count=3 error="oops\nmain.main\n\tmain.go:38" id=abc run_id=123123123`,
+			))
+		})
+
+		When("bytes are set by a hook", func() {
+			BeforeEach(func() {
+				evt.Set("raw", []byte("hi\n"))
+			})
+
+			It("should write them as base64", func() {
+				Expect(string(data)).To(ContainSubstring(" raw=aGkK "))
+			})
+		})
+	})
+
+	Describe("encoding as console", func() {
+		var (
+			enc Console
+		)
+
+		BeforeEach(func() {
+			enc = Console{}
+		})
+
+		JustBeforeEach(func() {
+			data, err = enc.Encode(evt)
+		})
+
+		It("should write time, level, msg, and sorted pairs, with the error trace below", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(
+				"21:20:54.758 ERROR failed to, you know .. count=3 id=abc run_id=123123123\n" +
+					"  oops\n  main.main\n  \tmain.go:38",
+			))
+		})
+
+		When("columns are set", func() {
+			BeforeEach(func() {
+				columns, err := ParseColumns([]string{"id:5", "run_id:6", "missing"})
+				Expect(err).ToNot(HaveOccurred())
+				enc.Columns = columns
+			})
+
+			It("should lead with column values, padded or cut to width", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(HavePrefix("21:20:54.758 ERROR failed to, you know .. abc   12312… - count=3\n"))
+			})
+
+			When("compact", func() {
+				BeforeEach(func() {
+					enc.Compact = true
+				})
+
+				It("should hide the rest, noting their count", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(string(data)).To(HavePrefix("21:20:54.758 ERROR failed to, you know .. abc   12312… - (+1)\n"))
+				})
+			})
+		})
+
+		When("themed", func() {
+			BeforeEach(func() {
+				theme, err := NewTheme("light", map[string]string{"error": "1;91"})
+				Expect(err).ToNot(HaveOccurred())
+				enc.Color = true
+				enc.Theme = theme
+			})
+
+			It("should paint with the theme's colors", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(HavePrefix("21:20:54.758 \x1b[1;91mERROR\x1b[0m failed to, you know .. \x1b[90mcount=\x1b[0m3"))
+			})
+
+			It("should fall back to the built-in level below", func() {
+				Expect(enc.Theme.levelClass("notice")).To(Equal("info"))
+				Expect(enc.Theme.levelClass("audit")).To(Equal("audit"))
+			})
+		})
+
+		When("theme is invalid", func() {
+			It("should error", func() {
+				_, err := NewTheme("solarized", nil)
+				Expect(err).To(MatchError("unknown theme: solarized"))
+
+				cfg := &Config{ThemeColors: map[string]string{"warn": "yellow"}}
+				Expect(cfg.Validate()).To(MatchError("invalid color for warn: yellow"))
+			})
+		})
+
+		When("column specs are invalid", func() {
+			It("should error", func() {
+				_, err := ParseColumns([]string{"status:wide"})
+				Expect(err).To(MatchError("invalid column width: status:wide"))
+
+				Expect((&Config{Columns: []string{":3"}}).Validate()).To(MatchError("column key is empty: :3"))
+			})
+		})
+
+		When("in color", func() {
+			BeforeEach(func() {
+				enc.Color = true
+			})
+
+			It("should paint the level and keys", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(data)).To(HavePrefix("21:20:54.758 \x1b[31mERROR\x1b[0m failed to, you know .. \x1b[2mcount=\x1b[0m3"))
+			})
+		})
+	})
+
+	Describe("deciding on color", func() {
+		var (
+			buf *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			for _, name := range []string{"FORCE_COLOR", "CLICOLOR_FORCE", "NO_COLOR", "CLICOLOR"} {
+				GinkgoT().Setenv(name, "")
+				Expect(os.Unsetenv(name)).To(Succeed())
+			}
+		})
+
+		It("should follow explicit modes regardless of env", func() {
+			GinkgoT().Setenv("NO_COLOR", "1")
+			Expect(ColorAlways.Enabled(buf)).To(BeTrue())
+
+			GinkgoT().Setenv("FORCE_COLOR", "1")
+			Expect(ColorNever.Enabled(buf)).To(BeFalse())
+		})
+
+		It("should color only for a terminal when auto", func() {
+			Expect(ColorAuto.Enabled(buf)).To(BeFalse())
+		})
+
+		It("should honor force color when auto", func() {
+			GinkgoT().Setenv("NO_COLOR", "1")
+			GinkgoT().Setenv("FORCE_COLOR", "1")
+			Expect(ColorAuto.Enabled(buf)).To(BeTrue())
+
+			GinkgoT().Setenv("FORCE_COLOR", "0")
+			Expect(ColorAuto.Enabled(buf)).To(BeFalse())
+		})
+
+		It("should honor clicolor force when auto", func() {
+			GinkgoT().Setenv("CLICOLOR_FORCE", "1")
+			Expect(ColorAuto.Enabled(buf)).To(BeTrue())
+		})
+
+		It("should be configurable", func() {
+			enc := (&Config{Format: "console", Color: ColorAlways}).New(buf).Encoder
+			Expect(enc).To(Equal(Console{Color: true}))
+
+			Expect((&Config{Color: "sometimes"}).Validate()).To(MatchError("unknown color mode: sometimes"))
+		})
+	})
+
+	Describe("picking a format automatically", func() {
+		It("should pick json when not writing to a terminal", func() {
+			reader, writer, err := os.Pipe()
+			Expect(err).ToNot(HaveOccurred())
+			defer reader.Close()
+			defer writer.Close()
+
+			Expect((&Config{Format: "auto"}).New(writer).Encoder).To(BeNil())
+			Expect((&Config{Format: "auto"}).New(&bytes.Buffer{}).Encoder).To(BeNil())
+			Expect((&Config{Format: "auto"}).Validate()).To(Succeed())
+		})
+	})
+
+	Describe("encoding as gelf", func() {
+		JustBeforeEach(func() {
+			data, err = GELF{Host: "testo"}.Encode(evt)
+		})
+
+		It("should write gelf fields", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(unjson(data)).To(Equal(map[string]any{
+				"version":       "1.1",
+				"host":          "testo",
+				"short_message": "failed to, you know ..",
+				"full_message":  "oops\nmain.main\n\tmain.go:38",
+				"timestamp":     1700947254.7584345,
+				"level":         float64(3),
+				"_level_name":   "error",
+				"_run_id":       "123123123",
+				"_count":        float64(3),
+				"_id_":          "abc",
+			}))
+		})
+
+		It("should resolve the host once when configured", func() {
+			host, _ := os.Hostname()
+			Expect((&Config{Format: "gelf"}).New(nil).Encoder).To(Equal(GELF{Host: host}))
+		})
+	})
+
+	Describe("decoding json", func() {
+		var (
+			decoded *Event
+		)
+
+		JustBeforeEach(func() {
+			data, err = JSON{}.Encode(evt)
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded, err = DecodeEvent(data)
+		})
+
+		It("should round trip", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(decoded.Time).To(Equal(evt.Time))
+			Expect(decoded.Level).To(Equal("error"))
+			Expect(decoded.Msg).To(Equal(evt.Msg))
+			Expect(decoded.Err).To(MatchError(evt.Fields["error"]))
+			Expect(decoded.Fields).To(HaveKeyWithValue("count", float64(3)))
+			Expect(decoded.Fields).ToNot(HaveKey("msg"))
+		})
+
+		When("msg is missing", func() {
+			It("should fail", func() {
+				_, err := DecodeEvent([]byte(`{"level":"info"}`))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("logging with format from config", func() {
+		var (
+			buf *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+		})
+
+		JustBeforeEach(func() {
+			lgr := (&Config{Format: "logfmt"}).New(buf)
+			lgr.Error(context.Background(), "failed", fmt.Errorf("oops"), "count", 3)
+		})
+
+		It("should write logfmt", func() {
+			Expect(buf.String()).To(MatchRegexp(`^ts=\S+ level=error msg=failed count=3 error=oops error_type=\*errors.errorString\n$`))
+		})
+
+		It("should default to json otherwise", func() {
+			Expect((&Config{}).New(os.Stderr).Encoder).To(BeNil())
+		})
+	})
+})
+
+func unjson(data []byte) (obj map[string]any) {
+
+	err := json.Unmarshal(data, &obj)
+	Expect(err).ToNot(HaveOccurred())
+
+	return
+}
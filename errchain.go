@@ -0,0 +1,155 @@
+package sabot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errorChainKey string = "error_chain"
+	errorsKey     string = "errors"
+	errorTypeKey  string = "error_type"
+)
+
+// LogFielder is implemented by errors carrying structured fields, such as request ids and codes,
+// which are merged into the event when the error, or any in its chain, is logged.
+//
+// Fields of outer errors take precedence over those they wrap, and kv over both.
+type LogFielder interface {
+	LogFields() Fields
+}
+
+//
+// unexported
+//
+
+// errorFields returns kv of the fields contributed by LogFielders in err's chain, joins included.
+func errorFields(err error) (kv []any) {
+
+	fields := Fields{}
+	collectFields(err, fields)
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		kv = append(kv, key, fields[key])
+	}
+
+	return
+}
+
+func collectFields(err error, fields Fields) {
+
+	// wrapped first, so that outer errors overwrite
+
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		collectFields(wrapper.Unwrap(), fields)
+	case multiError:
+		for _, joined := range wrapper.Unwrap() {
+			collectFields(joined, fields)
+		}
+	}
+
+	fielder, ok := err.(LogFielder)
+	if ok {
+		for key, val := range fielder.LogFields() {
+			fields[key] = val
+		}
+	}
+}
+
+// errorChain returns the message added by each error in err's Unwrap chain, innermost first,
+// or nil when err wraps nothing.
+//
+// A wrapper's message is its own, sans the ": "-joined message of what it wraps,
+// and wrappers adding no message, as with errors.WithStack, are skipped.
+func errorChain(err error) (chain []string) {
+
+	for err != nil {
+		msg := err.Error()
+
+		next := errors.Unwrap(err)
+		if next != nil {
+			inner := next.Error()
+			if msg == inner {
+				err = next
+				continue
+			}
+			msg = strings.TrimSuffix(msg, ": "+inner)
+		}
+
+		chain = append(chain, msg)
+		err = next
+	}
+
+	if len(chain) < 2 {
+		return nil
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return
+}
+
+// wrapperTypes are errors adding only context or a stack, which say little of what failed.
+var wrapperTypes = map[string]bool{
+	"*errors.withStack":   true,
+	"*errors.withMessage": true,
+	"*fmt.wrapError":      true,
+	"*fmt.wrapErrors":     true,
+	"*sabot.annotated":    true,
+}
+
+// errorType returns the concrete type of the outermost error in err's Unwrap chain that is not a mere wrapper,
+// as in "*pq.Error".
+func errorType(err error) (typ string) {
+
+	for err != nil {
+		typ = fmt.Sprintf("%T", err)
+		if !wrapperTypes[typ] {
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return
+}
+
+// multiError is implemented by errors joining others, as from errors.Join.
+type multiError interface {
+	Unwrap() []error
+}
+
+// joinedErrors returns the messages of the errors joined by err or any error it wraps,
+// with nested joins flattened, or nil when there are none.
+func joinedErrors(err error) (msgs []string) {
+
+	var multi multiError
+	if !errors.As(err, &multi) {
+		return nil
+	}
+
+	for _, joined := range multi.Unwrap() {
+		if joined == nil {
+			continue
+		}
+
+		nested := joinedErrors(joined)
+		if nested != nil {
+			msgs = append(msgs, nested...)
+			continue
+		}
+		msgs = append(msgs, joined.Error())
+	}
+
+	return
+}
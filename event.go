@@ -0,0 +1,157 @@
+package sabot
+
+import (
+	"context"
+	"time"
+)
+
+// Event is a single log event as seen by hooks and structured sinks.
+type Event struct {
+	// Time is when the event was logged.
+	Time time.Time
+	// Level is the level of the event.
+	Level string
+	// Msg is the message of the event.
+	Msg string
+	// Err is the error of an error level event.
+	Err error
+	// Fields are the computed, kv, and ctx fields of the event, sans ts, level, and msg.
+	Fields Fields
+	// Hints are how fields are best indexed by key, from config and call sites.
+	Hints map[string]Hint
+	// Classes are the classifications of fields by key, from config and call sites, unclassified fields absent.
+	Classes map[string]Class
+
+	order  []string
+	layout string
+}
+
+// Hook is called with each event before it is written.
+//
+// A hook may modify the event, prefering Set for field values, and returns false to drop it.
+type Hook func(ctx context.Context, evt *Event) (keep bool)
+
+// EventWriter is implemented by sinks wanting structured events rather than encoded bytes.
+//
+// When Writer implements it, WriteEvent is used in place of Write and encoding is skipped,
+// so that a sink can pick out labels, keys, and the like without re-parsing json.
+// Events are not reused by Sabot and may be retained.
+type EventWriter interface {
+	WriteEvent(evt *Event) error
+}
+
+// Get returns the value of a field.
+func (evt *Event) Get(key string) (val any, ok bool) {
+
+	val, ok = evt.Fields[key]
+	return
+}
+
+// Set sets the value of a field, marshalling objects as is done for kv.
+func (evt *Event) Set(key string, val any) {
+
+	var err error
+	evt.Fields[key], err = marshalUnknown(val)
+	if err != nil {
+		for ek, ev := range logErrorFields(err, []any{key, val}) {
+			evt.Fields[ek] = ev
+		}
+	}
+}
+
+// Delete removes a field.
+func (evt *Event) Delete(key string) {
+
+	delete(evt.Fields, key)
+}
+
+// Merged returns a copy of the event's fields along with ts, level, and msg, as encoded.
+func (evt *Event) Merged() Fields {
+
+	fields := make(Fields, len(evt.Fields)+3)
+	for key, val := range evt.Fields {
+		fields[key] = val
+	}
+	evt.boilerplate(fields)
+
+	return fields
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) newEvent(ctx context.Context, ctxFields Fields, level, msg string, err error, kv []any) *Event {
+
+	now := time.Now().UTC()
+	traceLog(ctx, level, msg)
+
+	if sabot.WarnMisuse {
+		sabot.checkMisuse(ctx, kv)
+	}
+
+	fields := sabot.compute(ctx)
+	merge(fields, sabot.fields)
+	sabot.addCaller(fields)
+
+	// silently overwrite computed, with, and caller from kv, kv from ctx, and ctx from boilerplate when duplicate key
+
+	for key, val := range newFields(kv) {
+		fields[key] = val
+	}
+
+	for key, val := range ctxFields {
+		fields[key] = val
+	}
+
+	for _, key := range []string{"msg", "level", "ts"} {
+		delete(fields, key)
+	}
+	sabot.stamp(fields)
+	fields.unnest(sabot.Nested, sabot.Bytes)
+
+	// classes first, as hints unwrap their values
+
+	classes := sabot.classesFor(fields)
+
+	return &Event{
+		Time:    now,
+		Level:   level,
+		Msg:     msg,
+		Err:     err,
+		Fields:  fields,
+		Hints:   sabot.hintsFor(fields),
+		Classes: classes,
+		order:   kvKeys(kv),
+		layout:  sabot.TimeLayout,
+	}
+}
+
+func (sabot *Sabot) hook(ctx context.Context, evt *Event) bool {
+
+	for _, hook := range sabot.Hooks {
+		if !hook(ctx, evt) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (evt *Event) truncate(trc truncation) {
+
+	evt.Fields.truncate(trc)
+
+	msg, ok := trc.truncate(evt.Msg)
+	if ok {
+		evt.Fields["msg"+origLenSuffix] = len(evt.Msg)
+		evt.Msg = msg
+	}
+}
+
+func (evt *Event) boilerplate(fields Fields) {
+
+	fields["msg"] = evt.Msg
+	fields["level"] = evt.Level
+	fields["ts"] = evt.ts()
+}
@@ -0,0 +1,87 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hooks", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+		err error
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer: buf,
+			Hooks: []Hook{
+				func(ctx context.Context, evt *Event) bool {
+					return evt.Msg != "dropped"
+				},
+				func(ctx context.Context, evt *Event) bool {
+					if evt.Err != nil {
+						evt.Set("error_msg", evt.Err.Error())
+					}
+					evt.Set("hooked", []int{1})
+					evt.Delete("secret")
+
+					_, ok := evt.Get("app_id")
+					evt.Set("had_app_id", ok)
+					return true
+				},
+			},
+		}
+		ctx = lgr.WithFields(context.Background(), "app_id", "testo", "secret", "shh")
+	})
+
+	When("an error is logged", func() {
+		BeforeEach(func() {
+			err = fmt.Errorf("oops")
+		})
+
+		JustBeforeEach(func() {
+			lgr.Error(ctx, "failed", err)
+		})
+
+		It("should write the modified event", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":      "error",
+				"msg":        "failed",
+				"ts":         "nowish",
+				"app_id":     "testo",
+				"error":      "oops",
+				"error_type": "*errors.errorString",
+				"error_msg":  "oops",
+				"hooked":     "[1]",
+				"had_app_id": "true",
+			}))
+		})
+	})
+
+	When("a hook drops the event", func() {
+		JustBeforeEach(func() {
+			lgr.Info(ctx, "dropped")
+		})
+
+		It("should write nothing", func() {
+			Expect(delog(buf)).To(BeEmpty())
+		})
+	})
+
+	When("merging an event", func() {
+		It("should include boilerplate", func() {
+			evt := &Event{Level: "info", Msg: "merged", Fields: Fields{"foo": "bar"}}
+			Expect(evt.Merged()).To(HaveKeyWithValue("msg", "merged"))
+			Expect(evt.Merged()).To(HaveKeyWithValue("foo", "bar"))
+			Expect(evt.Fields).To(HaveLen(1))
+		})
+	})
+})
@@ -40,7 +40,7 @@ func main() {
 
 	// log stuff, yay
 
-	lgr.Info(ctx, "logloglog starting", "config", cfg)
+	lgr.Startup(ctx, cfg)
 	lgr.Error(ctx, "failed to, you know ..", errors.Errorf("oops"))
 }
 
@@ -50,9 +50,11 @@ func main() {
 $ bin/logloglog 2>&1 | jq --slurp
 [
   {
-    "config": "{\"version\":\"config.11.8a5e577\",\"logger\":{\"max_len\":99}}",
+    "build": "{\"go_version\":\"go1.21.4\",\"path\":\"github.com/clarktrimble/sabot\",\"version\":\"(devel)\"}",
+    "config": "{\"version\":\"config.11.8a5e577\",\"logger\":{\"max_len\":99,\"label_keys\":null,\"form--truncated--",
+    "host": "{\"arch\":\"amd64\",\"hostname\":\"tartu\",\"num_cpu\":8,\"os\":\"linux\",\"pid\":21376}",
     "level": "info",
-    "msg": "logloglog starting",
+    "msg": "starting",
     "run_id": "123123123",
     "ts": "2023-11-25T21:20:54.758434441Z"
   },
@@ -0,0 +1,31 @@
+package sabot
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// Task starts a runtime/trace task, returning a ctx carrying it and kv fields along with a func ending it.
+//
+// Events logged with the returned ctx are annotated in the task when tracing is active,
+// so that "go tool trace" shows the same messages found in the log.
+func (sabot *Sabot) Task(ctx context.Context, name string, kv ...any) (context.Context, func()) {
+
+	ctx, task := trace.NewTask(ctx, name)
+	ctx = sabot.WithFields(ctx, kv...)
+
+	return ctx, task.End
+}
+
+//
+// unexported
+//
+
+func traceLog(ctx context.Context, level, msg string) {
+
+	if !trace.IsEnabled() {
+		return
+	}
+
+	trace.Log(ctx, level, msg)
+}
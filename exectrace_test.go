@@ -0,0 +1,45 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"runtime/trace"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Task", func() {
+
+	var (
+		buf   *bytes.Buffer
+		trbuf *bytes.Buffer
+		lgr   *Sabot
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		trbuf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+
+		Expect(trace.Start(trbuf)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		ctx, end := lgr.Task(context.Background(), "work", "task_id", "abc")
+		lgr.Info(ctx, "working")
+		end()
+
+		trace.Stop()
+	})
+
+	It("should log with task fields and annotate the trace", func() {
+		Expect(delog(buf)).To(Equal(Fields{
+			"level":   "info",
+			"msg":     "working",
+			"ts":      "nowish",
+			"task_id": "abc",
+		}))
+		Expect(trbuf.String()).To(ContainSubstring("working"))
+	})
+})
@@ -0,0 +1,108 @@
+package sabot
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// Extractor reads fields placed in a ctx by something other than Sabot.
+//
+// Extracted fields are logged as are those from WithFields, which take precedence.
+type Extractor func(ctx context.Context) Fields
+
+// ValueExtractor returns an Extractor for a map or key-value slice stored under key in a ctx.
+//
+// Values of type Fields, map[string]any, map[string]string, map[string][]string,
+// and []any of key-value pairs are supported, others are ignored.
+// Named map types are supported by kind, grpc's metadata.MD say, its values joined with commas.
+func ValueExtractor(key any) Extractor {
+
+	return func(ctx context.Context) (fields Fields) {
+
+		fields = Fields{}
+		switch val := ctx.Value(key).(type) {
+		case Fields:
+			merge(fields, val)
+		case map[string]any:
+			merge(fields, newFieldsFromMap(val))
+		case map[string]string:
+			for key, str := range val {
+				fields[key] = str
+			}
+		case map[string][]string:
+			for key, strs := range val {
+				fields[key] = strings.Join(strs, ",")
+			}
+		case []any:
+			merge(fields, newFields(val))
+		default:
+			merge(fields, stringMapFields(reflect.ValueOf(val)))
+		}
+
+		return
+	}
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) ctxFields(ctx context.Context) Fields {
+
+	if len(sabot.Extractors) == 0 {
+		return sabot.GetFields(ctx)
+	}
+
+	fields := Fields{}
+	for _, extract := range sabot.Extractors {
+		merge(fields, extract(ctx))
+	}
+	merge(fields, sabot.GetFields(ctx))
+
+	return fields
+}
+
+// stringMapFields returns fields for a map keyed by string kind with values of string kind or slices of them.
+func stringMapFields(rv reflect.Value) (fields Fields) {
+
+	fields = Fields{}
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return
+	}
+
+	elem := rv.Type().Elem()
+	switch {
+	case elem.Kind() == reflect.String:
+		for iter := rv.MapRange(); iter.Next(); {
+			fields[iter.Key().String()] = iter.Value().String()
+		}
+	case elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String:
+		for iter := rv.MapRange(); iter.Next(); {
+			strs := make([]string, iter.Value().Len())
+			for idx := range strs {
+				strs[idx] = iter.Value().Index(idx).String()
+			}
+			fields[iter.Key().String()] = strings.Join(strs, ",")
+		}
+	}
+
+	return
+}
+
+func merge(dst, src Fields) {
+
+	for key, val := range src {
+		dst[key] = val
+	}
+}
+
+func newFieldsFromMap(src map[string]any) Fields {
+
+	kv := make([]any, 0, len(src)*2)
+	for key, val := range src {
+		kv = append(kv, key, val)
+	}
+
+	return newFields(kv)
+}
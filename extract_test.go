@@ -0,0 +1,83 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extractors", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer: buf,
+			Extractors: []Extractor{
+				ValueExtractor(otherKey{}),
+				ValueExtractor(mdKey{}),
+			},
+		}
+
+		ctx = context.WithValue(context.Background(), otherKey{}, map[string]any{"trace_id": "t1", "app_id": "other"})
+		ctx = context.WithValue(ctx, mdKey{}, map[string][]string{"x-tenant": {"acme", "beta"}})
+		ctx = lgr.WithFields(ctx, "app_id", "testo")
+	})
+
+	JustBeforeEach(func() {
+		lgr.Info(ctx, "extracted")
+	})
+
+	When("ctx has fields from elsewhere", func() {
+		It("should log them with sabot fields taking precedence", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":    "info",
+				"msg":      "extracted",
+				"ts":       "nowish",
+				"trace_id": "t1",
+				"x-tenant": "acme,beta",
+				"app_id":   "testo",
+			}))
+		})
+
+		It("should seal them when bound", func() {
+			buf.Reset()
+			lgr.Bind(ctx).Info("bound")
+			Expect(delog(buf)).To(HaveKeyWithValue("trace_id", "t1"))
+		})
+	})
+
+	When("ctx has a kv slice", func() {
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), otherKey{}, []any{"trace_id", "t2"})
+		})
+
+		It("should log it", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("trace_id", "t2"))
+		})
+	})
+
+	When("ctx has a named map, as with grpc metadata", func() {
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), mdKey{}, metadata{"x-tenant": {"acme"}})
+		})
+
+		It("should log it", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("x-tenant", "acme"))
+		})
+	})
+})
+
+type otherKey struct{}
+
+type mdKey struct{}
+
+// metadata is shaped as grpc's metadata.MD.
+type metadata map[string][]string
@@ -0,0 +1,68 @@
+package sabot
+
+import (
+	"context"
+	"os"
+)
+
+// ExitHook is run by Fatal before exiting, to flush writers, close files, and the like.
+type ExitHook func(ctx context.Context) error
+
+// osExit is swapped out in tests.
+var osExit = os.Exit
+
+// Fatal logs a fatal level event, runs exit hooks in order, and exits with ExitCode.
+//
+// Hook errors are logged at error level, with the remaining hooks run regardless.
+// Fatal exits even when the fatal level is disabled.
+func (sabot *Sabot) Fatal(ctx context.Context, msg string, err error, kv ...any) {
+
+	sabot.logFatal(ctx, msg, err, kv)
+	sabot.exit(ctx)
+}
+
+// OnExit registers a hook to be run by Fatal.
+//
+// Register hooks during startup, before logging.
+func (sabot *Sabot) OnExit(hook ExitHook) {
+
+	sabot.ExitHooks = append(sabot.ExitHooks, hook)
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) logFatal(ctx context.Context, msg string, err error, kv []any) {
+
+	if !sabot.enabled(ctx, Fatal) {
+		return
+	}
+
+	sabot.log(ctx, Fatal.String(), msg, err, sabot.errorKv(ctx, err, kv))
+}
+
+func (sabot *Sabot) exit(ctx context.Context) {
+
+	sabot.runExitHooks(ctx)
+	osExit(sabot.exitCode())
+}
+
+func (sabot *Sabot) runExitHooks(ctx context.Context) {
+
+	for _, hook := range sabot.ExitHooks {
+		err := hook(ctx)
+		if err != nil {
+			sabot.Error(ctx, "exit hook failed", err)
+		}
+	}
+}
+
+func (sabot *Sabot) exitCode() int {
+
+	if sabot.ExitCode == 0 {
+		return 1
+	}
+
+	return sabot.ExitCode
+}
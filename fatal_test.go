@@ -0,0 +1,93 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fatal", func() {
+
+	var (
+		buf    *bytes.Buffer
+		lgr    *Sabot
+		ctx    context.Context
+		code   int
+		exited int
+		ran    []string
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = context.Background()
+
+		code = 0
+		exited = 0
+		ran = nil
+
+		osExit = func(c int) {
+			code = c
+			exited++
+		}
+		DeferCleanup(func() {
+			osExit = os.Exit
+		})
+
+		lgr.OnExit(func(ctx context.Context) error {
+			ran = append(ran, "flush")
+			return nil
+		})
+		lgr.OnExit(func(ctx context.Context) error {
+			ran = append(ran, "close")
+			return fmt.Errorf("already closed")
+		})
+	})
+
+	When("logging fatal", func() {
+		It("should log, run hooks in order, and exit", func() {
+			lgr.Fatal(ctx, "cannot continue", fmt.Errorf("oops"), "stage", "startup")
+
+			events := []Event{}
+			for evt, err := range Decode(buf) {
+				Expect(err).ToNot(HaveOccurred())
+				events = append(events, evt)
+			}
+			Expect(events).To(HaveLen(2))
+			Expect(events[0].Level).To(Equal("fatal"))
+			Expect(events[0].Msg).To(Equal("cannot continue"))
+			Expect(events[0].Fields).To(HaveKeyWithValue("stage", "startup"))
+			Expect(events[0].Err).To(MatchError("oops"))
+			Expect(events[1].Msg).To(Equal("exit hook failed"))
+
+			Expect(ran).To(Equal([]string{"flush", "close"}))
+			Expect(exited).To(Equal(1))
+			Expect(code).To(Equal(1))
+		})
+	})
+
+	When("exit code is set and fatal is disabled", func() {
+		It("should exit with the code regardless", func() {
+			lgr.ExitCode = 3
+			lgr.Disabled = map[Level]bool{Fatal: true}
+			lgr.Bind(ctx).Fatal("cannot continue", nil)
+
+			Expect(code).To(Equal(3))
+			Expect(ran).To(Equal([]string{"flush", "close"}))
+		})
+	})
+
+	When("fatal via dual", func() {
+		It("should log to both and exit once", func() {
+			dual := &Dual{Old: &Sabot{Writer: buf}, New: lgr, Percent: 100, Both: true}
+			dual.Fatal(ctx, "cannot continue", nil)
+
+			Expect(exited).To(Equal(1))
+			Expect(bytes.Count(buf.Bytes(), []byte(`"level":"fatal"`))).To(Equal(2))
+		})
+	})
+})
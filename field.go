@@ -0,0 +1,170 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Field is a key-value pair taking a single place among kv.
+//
+// For example, lgr.Info(ctx, msg, "foo", "bar", sabot.NoTruncate("body", body)).
+type Field struct {
+	Key   string
+	Val   any
+	typed bool
+}
+
+// String is a Field of a string, logged without inspecting its type.
+func String(key, val string) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Int is a Field of an int, logged without inspecting its type.
+func Int(key string, val int) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Int64 is a Field of an int64, logged without inspecting its type.
+func Int64(key string, val int64) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Float64 is a Field of a float64, logged without inspecting its type.
+func Float64(key string, val float64) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Time is a Field of a time, logged without inspecting its type.
+func Time(key string, val time.Time) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Duration is a Field of a duration, logged without inspecting its type.
+func Duration(key string, val time.Duration) Field {
+
+	return Field{Key: key, Val: val, typed: true}
+}
+
+// Err is a Field of an error, formatted as when logged with Error, stack and all.
+func Err(key string, err error) Field {
+
+	return Field{Key: key, Val: fmt.Sprintf("%+v", err), typed: true}
+}
+
+// Any is a Field of any value, marshalled as when given among kv.
+func Any(key string, val any) Field {
+
+	return Field{Key: key, Val: val}
+}
+
+// NoTruncate is a Field whose value is logged in full, regardless of MaxLen.
+func NoTruncate(key string, val any) Field {
+
+	// marshal now, so that json is wrapped as well as strings
+
+	marshalled, err := marshalUnknown(val)
+	if err != nil {
+		return Field{Key: key, Val: val}
+	}
+
+	return Field{Key: key, Val: whole{val: marshalled}}
+}
+
+// RawJSON is a Field of bytes already json, such as a pre-rendered api payload,
+// embedded as-is rather than re-encoded as an escaped string.
+//
+// Data is compacted onto a single line, and logged as a string should it not be valid json.
+func RawJSON(key string, data []byte) Field {
+
+	buf := &bytes.Buffer{}
+	err := json.Compact(buf, data)
+	if err != nil {
+		return Field{Key: key, Val: string(data), typed: true}
+	}
+
+	return Field{Key: key, Val: json.RawMessage(buf.Bytes()), typed: true}
+}
+
+// Group is a Field whose value is kv as a nested object, keeping related fields together
+// and apart from like keys of other subsystems.
+//
+// For example, sabot.Group("http", "method", "GET", "status", 200) is logged as {"http":{"method":"GET","status":200}}.
+// Text encoders write a group's value as json and GELF, lacking nested objects, as dotted keys.
+func Group(name string, kv ...any) Field {
+
+	return Field{Key: name, Val: group(newFields(kv))}
+}
+
+// Lazy is a value computed only when its event is logged, sparing the cost when the level is disabled.
+//
+// For example, lgr.Debug(ctx, msg, "dump", sabot.Lazy(func() any { return expensive() })).
+// A plain func() any is taken as Lazy too.
+type Lazy func() any
+
+// LogValuer is implemented by types controlling their own log representation,
+// so that internal types can be redacted or summarized once rather than at each call site.
+//
+// LogValue is called in place of marshalling, with its result marshalled as is done for kv.
+type LogValuer interface {
+	LogValue() any
+}
+
+//
+// unexported
+//
+
+// maxLogValues is the number of LogValue calls followed, guarding against values returning themselves.
+const maxLogValues int = 100
+
+// evaluate calls a lazy value's func, recovering from any panic so that logging carries on.
+func (lazy Lazy) evaluate() (val any, err error) {
+
+	defer func() {
+		rcv := recover()
+		if rcv != nil {
+			val, err = logErrorKey, errors.Errorf("lazy value panicked: %v", rcv)
+		}
+	}()
+
+	return marshalUnknown(lazy())
+}
+
+// resolve calls LogValue until a value is not a LogValuer, recovering from any panic as with Lazy.
+func resolve(lv LogValuer) (val any, err error) {
+
+	defer func() {
+		rcv := recover()
+		if rcv != nil {
+			val, err = logErrorKey, errors.Errorf("log value panicked: %v", rcv)
+		}
+	}()
+
+	for range maxLogValues {
+		val = lv.LogValue()
+
+		next, ok := val.(LogValuer)
+		if !ok {
+			return marshalUnknown(val)
+		}
+		lv = next
+	}
+
+	return logErrorKey, errors.Errorf("log value exceeded %d calls: %T", maxLogValues, lv)
+}
+
+// whole is a marshalled value exempt from truncation, unwrapped when fields are truncated.
+type whole struct {
+	val any
+}
+
+// group is a nested object of fields, its values marshalled when grouped.
+type group Fields
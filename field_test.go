@@ -0,0 +1,300 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// account is an internal type, summarizing itself when logged.
+type account struct {
+	Id     int
+	Secret string
+}
+
+func (acct account) LogValue() any {
+
+	return map[string]any{"id": acct.Id}
+}
+
+// money marshals itself, its unexported fields otherwise dumped as {}.
+type money struct {
+	cents    int
+	currency string
+}
+
+func (mny money) MarshalJSON() ([]byte, error) {
+
+	return []byte(fmt.Sprintf(`{"amount": %d.%02d, "currency": %q}`, mny.cents/100, mny.cents%100, mny.currency)), nil
+}
+
+// region is a Stringer.
+type region struct {
+	name string
+}
+
+func (rgn *region) String() string {
+
+	return "region:" + rgn.name
+}
+
+// looping is a LogValuer returning itself.
+type looping struct{}
+
+func (lp looping) LogValue() any {
+
+	return lp
+}
+
+var _ = Describe("Field", func() {
+
+	var (
+		buf  *bytes.Buffer
+		lgr  *Sabot
+		ctx  context.Context
+		long string
+		kv   []any
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, MaxLen: 40}
+		ctx = context.Background()
+		long = strings.Repeat("x", 99)
+	})
+
+	JustBeforeEach(func() {
+		lgr.Info(ctx, "fielding", kv...)
+	})
+
+	When("mixing fields and pairs", func() {
+		BeforeEach(func() {
+			kv = []any{"short", long, NoTruncate("payload", long), "count", 3}
+		})
+
+		It("should truncate all but the exempt field", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level":          "info",
+				"msg":            "fielding",
+				"ts":             "nowish",
+				"short":          "xxxxxxxxxxxxxxxxxxxxxxxxxxx--truncated--",
+				"short_orig_len": float64(99),
+				"payload":        long,
+				"count":          float64(3),
+			}))
+		})
+	})
+
+	When("exempting a struct", func() {
+		BeforeEach(func() {
+			kv = []any{NoTruncate("payload", []string{long})}
+		})
+
+		It("should log its json in full", func() {
+			Expect(delog(buf)["payload"]).To(Equal(`["` + long + `"]`))
+		})
+	})
+
+	When("logging raw json", func() {
+		BeforeEach(func() {
+			kv = []any{RawJSON("payload", []byte("{\n  \"id\": 7,\n  \"tags\": [\"a\"]\n}"))}
+		})
+
+		It("should embed it compacted, rather than as a string", func() {
+			Expect(buf.String()).To(ContainSubstring(`"payload":{"id":7,"tags":["a"]}`))
+			Expect(delog(buf)["payload"]).To(Equal(map[string]any{"id": float64(7), "tags": []any{"a"}}))
+		})
+	})
+
+	When("logging raw json that isn't", func() {
+		BeforeEach(func() {
+			kv = []any{RawJSON("payload", []byte(`{"id":`))}
+		})
+
+		It("should log it as a string", func() {
+			Expect(delog(buf)["payload"]).To(Equal(`{"id":`))
+		})
+	})
+
+	When("grouping", func() {
+		BeforeEach(func() {
+			ctx = lgr.WithFields(ctx, Group("req", "body", long))
+			kv = []any{Group("http", "method", "GET", "status", 200, Group("peer", "ip", "10.0.0.1"))}
+		})
+
+		It("should nest, truncating within a copy", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level": "info",
+				"msg":   "fielding",
+				"ts":    "nowish",
+				"http": map[string]any{
+					"method": "GET",
+					"status": float64(200),
+					"peer":   map[string]any{"ip": "10.0.0.1"},
+				},
+				"req": map[string]any{
+					"body":          "xxxxxxxxxxxxxxxxxxxxxxxxxxx--truncated--",
+					"body_orig_len": float64(99),
+				},
+			}))
+			Expect(lgr.GetFields(ctx)["req"]).To(Equal(group{"body": long}))
+		})
+	})
+
+	When("grouping for text and gelf", func() {
+		It("should write json and dotted keys", func() {
+			evt := &Event{Msg: "grouped", Fields: Fields{"http": group{"status": 200}}}
+
+			data, err := Logfmt{}.Encode(evt)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(HaveSuffix(`http="{\"status\":200}"`))
+
+			data, err = GELF{Host: "testo"}.Encode(evt)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"_http.status":200`))
+		})
+	})
+
+	When("fields are typed", func() {
+		BeforeEach(func() {
+			kv = []any{
+				String("name", "bob"),
+				Int("count", 3),
+				Int64("big", 1<<40),
+				Float64("ratio", 0.5),
+				Time("at", time.Date(2026, 10, 15, 1, 2, 3, 0, time.UTC)),
+				Duration("took", time.Second),
+				Err("cause", errors.New("oops")),
+				Any("tags", []string{"a"}),
+			}
+		})
+
+		It("should log them as pairs would be", func() {
+			Expect(delog(buf)).To(Equal(Fields{
+				"level": "info",
+				"msg":   "fielding",
+				"ts":    "nowish",
+				"name":  "bob",
+				"count": float64(3),
+				"big":   float64(1 << 40),
+				"ratio": 0.5,
+				"at":    "2026-10-15T01:02:03Z",
+				"took":  float64(time.Second),
+				"cause": "oops",
+				"tags":  `["a"]`,
+			}))
+		})
+	})
+
+	When("values are lazy", func() {
+		var (
+			calls int
+		)
+
+		BeforeEach(func() {
+			calls = 0
+			count := func() any {
+				calls++
+				return []int{calls}
+			}
+			kv = []any{"lazy", Lazy(count), "plain", count}
+		})
+
+		It("should evaluate them when logged", func() {
+			Expect(calls).To(Equal(2))
+			Expect(delog(buf)["lazy"]).To(Equal("[1]"))
+			Expect(delog(buf)["plain"]).To(Equal("[2]"))
+		})
+
+		It("should not evaluate them when the level is disabled", func() {
+			lgr.Debug(ctx, "skipped", kv...)
+			Expect(calls).To(Equal(2))
+		})
+	})
+
+	When("a lazy value panics", func() {
+		BeforeEach(func() {
+			kv = []any{"lazy", Lazy(func() any { panic("oops") })}
+		})
+
+		It("should log an error", func() {
+			Expect(delog(buf)["logerror"]).To(HavePrefix("lazy value panicked: oops"))
+		})
+	})
+
+	When("values log themselves", func() {
+		BeforeEach(func() {
+			kv = []any{"account", account{Id: 7, Secret: "shh"}, "ptr", &account{Id: 8}}
+		})
+
+		It("should marshal their log value", func() {
+			Expect(delog(buf)["account"]).To(Equal(`{"id":7}`))
+			Expect(delog(buf)["ptr"]).To(Equal(`{"id":8}`))
+		})
+	})
+
+	When("values are json marshalers and stringers", func() {
+		BeforeEach(func() {
+			lgr.MaxLen = 0
+			var none *region
+			kv = []any{"price", money{cents: 1999, currency: "USD"}, "region", &region{name: "west"}, "none", none}
+		})
+
+		It("should log their json and string", func() {
+			logged := delog(buf)
+			Expect(logged["price"]).To(Equal(`{"amount":19.99,"currency":"USD"}`))
+			Expect(logged["region"]).To(Equal("region:west"))
+			Expect(logged["none"]).To(Equal("<nil>"))
+		})
+
+		When("nested", func() {
+			BeforeEach(func() {
+				lgr.Nested = true
+			})
+
+			It("should embed their json", func() {
+				Expect(delog(buf)["price"]).To(Equal(map[string]any{"amount": 19.99, "currency": "USD"}))
+			})
+		})
+	})
+
+	When("a json marshaler is over-long", func() {
+		BeforeEach(func() {
+			kv = []any{"price", money{cents: 1999, currency: long}}
+		})
+
+		It("should truncate it as a string", func() {
+			logged := delog(buf)
+			Expect(logged["price"]).To(HavePrefix(`{"amount":19.99,`))
+			Expect(logged["price"]).To(ContainSubstring("--truncated--"))
+			Expect(logged).To(HaveKey("price_orig_len"))
+		})
+	})
+
+	When("a log value returns itself", func() {
+		BeforeEach(func() {
+			kv = []any{"loop", looping{}}
+		})
+
+		It("should log an error", func() {
+			Expect(delog(buf)["logerror"]).To(HavePrefix("log value exceeded 100"))
+		})
+	})
+
+	When("a field follows a dangling key", func() {
+		BeforeEach(func() {
+			lgr.MaxLen = 0
+			kv = []any{Field{Key: "foo", Val: "bar"}, "dangling"}
+		})
+
+		It("should log an error", func() {
+			Expect(delog(buf)["logerror"]).To(Equal("cannot create fields from odd count"))
+		})
+	})
+})
@@ -0,0 +1,47 @@
+package sabot
+
+import (
+	"context"
+)
+
+const flagsKey string = "flags"
+
+// FlagProvider provides the feature flags in effect for a ctx.
+type FlagProvider interface {
+	Flags(ctx context.Context) map[string]any
+}
+
+// RegisterFlags adds a computed "flags" field with the allowed flags from provider.
+//
+// Only flags named in allow are logged, keeping flag sprawl and anything sensitive out.
+// Call before logging, as Computed is not safe to modify concurrently.
+func (sabot *Sabot) RegisterFlags(provider FlagProvider, allow ...string) {
+
+	sabot.Computed = append(sabot.Computed, flagField(provider, allow))
+}
+
+//
+// unexported
+//
+
+func flagField(provider FlagProvider, allow []string) FieldFunc {
+
+	return func(ctx context.Context) (string, any) {
+
+		flags := provider.Flags(ctx)
+
+		allowed := map[string]any{}
+		for _, name := range allow {
+			val, ok := flags[name]
+			if ok {
+				allowed[name] = val
+			}
+		}
+
+		if len(allowed) == 0 {
+			return "", nil
+		}
+
+		return flagsKey, allowed
+	}
+}
@@ -0,0 +1,54 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RegisterFlags", func() {
+
+	var (
+		buf      *bytes.Buffer
+		lgr      *Sabot
+		provider mapProvider
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+	})
+
+	JustBeforeEach(func() {
+		lgr.RegisterFlags(provider, "new_checkout", "dark_mode")
+		lgr.Info(context.Background(), "flagged")
+	})
+
+	When("provider has allowed flags", func() {
+		BeforeEach(func() {
+			provider = mapProvider{"new_checkout": true, "secret_thing": "x"}
+		})
+
+		It("should log only allowed flags", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("flags", `{"new_checkout":true}`))
+		})
+	})
+
+	When("provider has no allowed flags", func() {
+		BeforeEach(func() {
+			provider = mapProvider{"secret_thing": "x"}
+		})
+
+		It("should not log flags", func() {
+			Expect(delog(buf)).ToNot(HaveKey("flags"))
+		})
+	})
+})
+
+type mapProvider map[string]any
+
+func (mp mapProvider) Flags(ctx context.Context) map[string]any {
+	return mp
+}
@@ -0,0 +1,92 @@
+package sabot
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const gelfVersion string = "1.1"
+
+var gelfInvalid = regexp.MustCompile(`[^\w.\-]`)
+
+// hostname is os.Hostname, looked up once.
+var hostname = sync.OnceValue(func() string {
+
+	host, _ := os.Hostname()
+	return host
+})
+
+// GELF encodes events as Graylog Extended Log Format json.
+//
+// Fields become additional underscore prefixed fields and an error field becomes full_message.
+type GELF struct {
+	// Host is the host field, defaulting to os.Hostname.
+	Host string
+}
+
+// Encode encodes an event.
+func (enc GELF) Encode(evt *Event) (data []byte, err error) {
+
+	host := enc.Host
+	if host == "" {
+		host = hostname()
+	}
+
+	level := syslogLevel(levelOf(evt.Level))
+
+	// gelf additional fields are flat, so groups become dotted keys
+
+	fields := make(Fields, len(evt.Fields)+6)
+	for key, val := range KeysFlatten.apply(evt.Fields) {
+		if key == "id" {
+			// _id is reserved
+			key = "id_"
+		}
+		fields["_"+gelfInvalid.ReplaceAllString(key, "_")] = val
+	}
+
+	fields["version"] = gelfVersion
+	fields["host"] = host
+	fields["short_message"] = evt.Msg
+	fields["timestamp"] = float64(evt.Time.UnixNano()) / 1e9
+	fields["level"] = level
+	fields["_level_name"] = evt.Level
+
+	trace, ok := evt.Fields["error"].(string)
+	if ok {
+		delete(fields, "_error")
+		fields["full_message"] = trace
+	}
+
+	data, err = json.Marshal(fields)
+	err = errors.Wrapf(err, "failed to marshal gelf event")
+	return
+}
+
+//
+// unexported
+//
+
+func syslogLevel(lvl Level) int {
+
+	// critical, error, warning, notice, info, and debug severities, custom levels falling between
+
+	switch {
+	case lvl == Fatal:
+		return 2
+	case lvl >= Error:
+		return 3
+	case lvl >= Warn:
+		return 4
+	case lvl > Info:
+		return 5
+	case lvl == Info:
+		return 6
+	default:
+		return 7
+	}
+}
@@ -0,0 +1,128 @@
+package sabot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Setting describes a configurable field, as found via json and desc tags.
+type Setting struct {
+	// Key is the dotted json path of the field.
+	Key string `json:"key"`
+	// Env is the environment variable name for the field.
+	Env string `json:"env"`
+	// Flag is the command line flag name for the field.
+	Flag string `json:"flag"`
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+	// Desc is the field's description.
+	Desc string `json:"desc"`
+	// Secret is true for fields redacted when logged.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Settings walks a config struct, such as Config or a sink config,
+// describing each of its fields with prefix prepended to keys.
+func Settings(prefix string, cfg any) (settings []Setting) {
+
+	var path []string
+	if prefix != "" {
+		path = []string{prefix}
+	}
+
+	return settingsFor(reflect.TypeOf(cfg), path)
+}
+
+// WriteHelp writes settings as aligned text or json, per format.
+func WriteHelp(writer io.Writer, format string, settings []Setting) (err error) {
+
+	switch format {
+	case "json":
+		err = json.NewEncoder(writer).Encode(settings)
+		return errors.Wrapf(err, "failed to encode settings")
+	case "", "text":
+	default:
+		return errors.Errorf("unknown help format: %s", format)
+	}
+
+	tw := tabwriter.NewWriter(writer, 0, 4, 2, ' ', 0)
+	for _, setting := range settings {
+		_, _ = fmt.Fprintf(tw, "-%s\t%s\t%s\t%s\n", setting.Flag, setting.Env, setting.Type, setting.Desc)
+	}
+
+	return errors.Wrapf(tw.Flush(), "failed to write settings")
+}
+
+//
+// unexported
+//
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func settingsFor(typ reflect.Type, path []string) (settings []Setting) {
+
+	if typ == nil {
+		return
+	}
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fieldPath := append(append([]string{}, path...), name)
+
+		// nested config structs are described field by field
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct && fieldType != reflect.TypeOf(time.Time{}) {
+			settings = append(settings, settingsFor(fieldType, fieldPath)...)
+			continue
+		}
+
+		settings = append(settings, Setting{
+			Key:    strings.Join(fieldPath, "."),
+			Env:    strings.ToUpper(strings.Join(fieldPath, "_")),
+			Flag:   strings.ReplaceAll(strings.Join(fieldPath, "-"), "_", "-"),
+			Type:   typeName(field.Type),
+			Desc:   field.Tag.Get("desc"),
+			Secret: field.Tag.Get(secretTag) == "true",
+		})
+	}
+
+	return
+}
+
+func typeName(typ reflect.Type) string {
+
+	if typ == durationType {
+		return "duration"
+	}
+
+	return typ.String()
+}
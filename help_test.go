@@ -0,0 +1,67 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Help", func() {
+
+	var (
+		settings []Setting
+	)
+
+	BeforeEach(func() {
+		settings = Settings("logger", &Config{})
+	})
+
+	When("describing config", func() {
+		It("should find each field via tags", func() {
+			Expect(settings).To(ContainElements(
+				Setting{
+					Key:  "logger.max_len",
+					Env:  "LOGGER_MAX_LEN",
+					Flag: "logger-max-len",
+					Type: "int",
+					Desc: "maximum length that will be logged for any field, zero for unlimited",
+				},
+				Setting{
+					Key:    "logger.redact.salt",
+					Env:    "LOGGER_REDACT_SALT",
+					Flag:   "logger-redact-salt",
+					Type:   "string",
+					Desc:   "salt for hashed values",
+					Secret: true,
+				},
+			))
+		})
+	})
+
+	When("writing text help", func() {
+		It("should align a line per setting", func() {
+			buf := &bytes.Buffer{}
+			Expect(WriteHelp(buf, "text", settings)).To(Succeed())
+			Expect(buf.String()).To(MatchRegexp(`(?m)^-logger-format +LOGGER_FORMAT +string +output format`))
+		})
+	})
+
+	When("writing json help", func() {
+		It("should round trip", func() {
+			buf := &bytes.Buffer{}
+			Expect(WriteHelp(buf, "json", settings)).To(Succeed())
+
+			decoded := []Setting{}
+			Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(settings))
+		})
+	})
+
+	When("help format is unknown", func() {
+		It("should error", func() {
+			Expect(WriteHelp(&bytes.Buffer{}, "yaml", settings)).To(MatchError("unknown help format: yaml"))
+		})
+	})
+})
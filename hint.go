@@ -0,0 +1,100 @@
+package sabot
+
+import (
+	"encoding/json"
+)
+
+// Hint tells structured sinks how a field is best indexed.
+type Hint int
+
+const (
+	// Unhinted fields are left to the sink.
+	Unhinted Hint = iota
+	// LabelHint marks low-cardinality fields, such as service or region, suited to labels and partitioning.
+	LabelHint
+	// PayloadHint marks high-cardinality fields, such as ids or bodies, best left unindexed.
+	PayloadHint
+)
+
+// AsLabel is a Field hinted as a low-cardinality label.
+func AsLabel(key string, val any) Field {
+
+	return Field{Key: key, Val: hinted{val: val, hint: LabelHint}}
+}
+
+// AsPayload is a Field hinted as a high-cardinality payload.
+func AsPayload(key string, val any) Field {
+
+	return Field{Key: key, Val: hinted{val: val, hint: PayloadHint}}
+}
+
+// Labels returns the event's fields hinted as labels.
+func (evt *Event) Labels() Fields {
+
+	labels := Fields{}
+	for key, hint := range evt.Hints {
+		val, ok := evt.Fields[key]
+		if ok && hint == LabelHint {
+			labels[key] = val
+		}
+	}
+
+	return labels
+}
+
+//
+// unexported
+//
+
+// hinted carries a call site hint and class with a value until the event is built.
+type hinted struct {
+	val   any
+	hint  Hint
+	class Class
+}
+
+// MarshalJSON marshals the value alone, as when bound fields are pre-encoded.
+func (hnt hinted) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(hnt.val)
+}
+
+func (sabot *Sabot) hint(key string, hint Hint) {
+
+	if sabot.Hints == nil {
+		sabot.Hints = map[string]Hint{}
+	}
+	sabot.Hints[key] = hint
+}
+
+func (sabot *Sabot) hintsFor(fields Fields) (hints map[string]Hint) {
+
+	// call site hints take precedence over configured
+
+	for key := range fields {
+		hint, ok := sabot.Hints[key]
+		if ok {
+			if hints == nil {
+				hints = map[string]Hint{}
+			}
+			hints[key] = hint
+		}
+	}
+
+	for key, val := range fields {
+		hnt, ok := val.(hinted)
+		if !ok {
+			continue
+		}
+		fields[key] = hnt.val
+
+		if hnt.hint != Unhinted {
+			if hints == nil {
+				hints = map[string]Hint{}
+			}
+			hints[key] = hnt.hint
+		}
+	}
+
+	return
+}
@@ -0,0 +1,54 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hint", func() {
+
+	var (
+		ew  *eventWriter
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ew = &eventWriter{}
+		lgr = (&Config{Labels: []string{"service", "region"}, Payloads: []string{"body"}}).New(ew)
+		ctx = context.Background()
+	})
+
+	When("hinting via config and call site", func() {
+		It("should carry hints with the event", func() {
+			ctx = lgr.WithFields(ctx, AsLabel("tenant", "acme"))
+			lgr.Info(ctx, "hinting", "service", "billing", "body", "{}", AsPayload("region", "per-request"), "other", 1)
+
+			Expect(ew.events).To(HaveLen(1))
+			evt := ew.events[0]
+			Expect(evt.Fields).To(HaveKeyWithValue("tenant", "acme"))
+			Expect(evt.Hints).To(Equal(map[string]Hint{
+				"service": LabelHint,
+				"region":  PayloadHint,
+				"body":    PayloadHint,
+				"tenant":  LabelHint,
+			}))
+			Expect(evt.Labels()).To(Equal(Fields{"service": "billing", "tenant": "acme"}))
+		})
+	})
+
+	When("binding hinted ctx fields", func() {
+		It("should encode the value alone", func() {
+			buf := &bytes.Buffer{}
+			lgr = &Sabot{Writer: buf}
+
+			ctx = lgr.WithFields(ctx, AsLabel("tenant", "acme"))
+			lgr.Bind(ctx).Info("bound")
+
+			Expect(delog(buf)).To(HaveKeyWithValue("tenant", "acme"))
+		})
+	})
+})
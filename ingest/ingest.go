@@ -0,0 +1,317 @@
+// Package ingest implements parsing of legacy plaintext logs into sabot events via regex or grok patterns,
+// easing migration of services that still emit unstructured lines.
+package ingest
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const maxLine = 16 * 1024 * 1024
+
+// Captures named ts, level, and msg become the event's boilerplate, with the rest becoming fields.
+const (
+	tsKey    string = "ts"
+	levelKey string = "level"
+	msgKey   string = "msg"
+)
+
+// Grok are the patterns available by name in grok syntax, as %{NAME:key}.
+var Grok = map[string]string{
+	"TIMESTAMP_ISO8601": `\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d+)?(?:Z|[+-]\d{2}:?\d{2})?`,
+	"LOGLEVEL":          `(?i:trace|debug|info|notice|warn|warning|err|error|crit|critical|fatal|panic)`,
+	"INT":               `[+-]?\d+`,
+	"NUMBER":            `[+-]?(?:\d+(?:\.\d*)?|\.\d+)`,
+	"WORD":              `\w+`,
+	"NOTSPACE":          `\S+`,
+	"SPACE":             `\s*`,
+	"DATA":              `.*?`,
+	"GREEDYDATA":        `.*`,
+	"QUOTEDSTRING":      `"(?:[^"\\]|\\.)*"`,
+	"IP":                `(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f:]*:[0-9A-Fa-f:]+`,
+	"UUID":              `[0-9A-Fa-f]{8}-(?:[0-9A-Fa-f]{4}-){3}[0-9A-Fa-f]{12}`,
+}
+
+// grokRef matches a grok reference, as in %{NUMBER:latency_ms:int}.
+var grokRef = regexp.MustCompile(`%\{(\w+)(?::([\w.]+))?(?::(int|float))?\}`)
+
+// levelAliases are level names found in legacy logs and their sabot equivalents.
+var levelAliases = map[string]string{
+	"warning":  "warn",
+	"err":      "error",
+	"notice":   "info",
+	"crit":     "error",
+	"critical": "error",
+	"panic":    "fatal",
+}
+
+// Config is the configurable fields of Parser.
+type Config struct {
+	Patterns   []string `json:"patterns" desc:"regex or grok patterns tried in order, named captures ts, level, and msg becoming boilerplate"`
+	TimeLayout string   `json:"time_layout" desc:"go time layout of the ts capture, common layouts tried when empty"`
+	Level      string   `json:"level" desc:"level of events lacking a level capture, info when empty"`
+	Multiline  bool     `json:"multiline" desc:"append lines matching no pattern to the previous event's msg, as with stack traces"`
+}
+
+// New creates a Parser from Config, returning an error when a pattern does not compile.
+func (cfg *Config) New() (psr *Parser, err error) {
+
+	if len(cfg.Patterns) == 0 {
+		err = errors.Errorf("no patterns given")
+		return
+	}
+
+	psr = &Parser{
+		TimeLayout: cfg.TimeLayout,
+		Level:      cfg.Level,
+		Multiline:  cfg.Multiline,
+	}
+
+	for _, pattern := range cfg.Patterns {
+		var ptn *Pattern
+		ptn, err = Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		psr.Patterns = append(psr.Patterns, ptn)
+	}
+
+	return
+}
+
+// Pattern is a compiled regex or grok pattern.
+type Pattern struct {
+	re    *regexp.Regexp
+	types map[string]string
+}
+
+// Compile compiles a pattern, expanding grok references such as %{NUMBER:latency_ms:int} into named captures.
+//
+// A reference's type, int or float, converts the captured value, which is otherwise a string.
+func Compile(pattern string) (ptn *Pattern, err error) {
+
+	ptn = &Pattern{types: map[string]string{}}
+
+	var refErr error
+	expanded := grokRef.ReplaceAllStringFunc(pattern, func(ref string) string {
+
+		parts := grokRef.FindStringSubmatch(ref)
+		name, key, typ := parts[1], parts[2], parts[3]
+
+		sub, ok := Grok[name]
+		if !ok {
+			refErr = errors.Errorf("unknown grok pattern: %s", name)
+			return ref
+		}
+		if key == "" {
+			return "(?:" + sub + ")"
+		}
+		if typ != "" {
+			ptn.types[key] = typ
+		}
+
+		// go names captures sans dots, so dotted keys are mapped back when matched
+
+		return "(?P<" + strings.ReplaceAll(key, ".", "__") + ">" + sub + ")"
+	})
+	if refErr != nil {
+		return nil, refErr
+	}
+
+	ptn.re, err = regexp.Compile(expanded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compile pattern: %s", pattern)
+	}
+
+	return
+}
+
+// Parser parses lines into events via the first of its Patterns to match.
+type Parser struct {
+	// Patterns are tried in order.
+	Patterns []*Pattern
+	// TimeLayout is the layout of the ts capture, common layouts tried when empty.
+	TimeLayout string
+	// Level is the level of events lacking a level capture, info when empty.
+	Level string
+	// Multiline determines if unmatched lines are appended to the previous event's msg.
+	Multiline bool
+}
+
+// Line parses a single line, returning false when no pattern matches.
+//
+// Events lacking a parsable ts capture are left with a zero Time.
+func (psr *Parser) Line(line string) (evt sabot.Event, ok bool) {
+
+	for _, ptn := range psr.Patterns {
+
+		match := ptn.re.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		evt = sabot.Event{Level: psr.level(""), Fields: sabot.Fields{}}
+		for idx, name := range ptn.re.SubexpNames() {
+			if name == "" || match[idx] == "" {
+				continue
+			}
+			key := strings.ReplaceAll(name, "__", ".")
+
+			switch key {
+			case tsKey:
+				evt.Time = psr.parseTime(match[idx])
+			case levelKey:
+				evt.Level = psr.level(match[idx])
+			case msgKey:
+				evt.Msg = match[idx]
+			default:
+				evt.Fields[key] = ptn.convert(key, match[idx])
+			}
+		}
+
+		if evt.Msg == "" {
+			// keep the whole line when there's no msg capture
+			evt.Msg = line
+		}
+
+		return evt, true
+	}
+
+	return
+}
+
+// Parse yields events parsed from the lines of src.
+//
+// Lines matching no pattern yield a sabot.DecodeError and parsing continues,
+// unless Multiline, when they're appended to the previous event's msg.
+// A read error is yielded last.
+func (psr *Parser) Parse(src io.Reader) iter.Seq2[sabot.Event, error] {
+
+	return func(yield func(sabot.Event, error) bool) {
+
+		scanner := bufio.NewScanner(src)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLine)
+
+		var pending *sabot.Event
+		count := 0
+		for scanner.Scan() {
+			count++
+
+			line := strings.TrimRight(scanner.Text(), "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+
+			evt, ok := psr.Line(line)
+			if !ok && psr.Multiline && pending != nil {
+				pending.Msg += "\n" + line
+				continue
+			}
+
+			if pending != nil {
+				if !yield(*pending, nil) {
+					return
+				}
+				pending = nil
+			}
+
+			if !ok {
+				err := &sabot.DecodeError{Line: count, Err: errors.Errorf("no pattern matches")}
+				if !yield(sabot.Event{}, err) {
+					return
+				}
+				continue
+			}
+
+			pending = &evt
+		}
+
+		if pending != nil && !yield(*pending, nil) {
+			return
+		}
+
+		err := scanner.Err()
+		if err != nil {
+			yield(sabot.Event{}, errors.Wrapf(err, "failed to read lines"))
+		}
+	}
+}
+
+//
+// unexported
+//
+
+// layouts are tried in order when no TimeLayout is given.
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05,999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.Stamp,
+	"02/Jan/2006:15:04:05 -0700",
+}
+
+func (psr *Parser) parseTime(val string) time.Time {
+
+	tryLayouts := layouts
+	if psr.TimeLayout != "" {
+		tryLayouts = []string{psr.TimeLayout}
+	}
+
+	for _, layout := range tryLayouts {
+		ts, err := time.Parse(layout, val)
+		if err == nil {
+			return ts.UTC()
+		}
+	}
+
+	return time.Time{}
+}
+
+func (psr *Parser) level(val string) string {
+
+	val = strings.ToLower(val)
+	if val == "" {
+		val = strings.ToLower(psr.Level)
+	}
+	if val == "" {
+		return "info"
+	}
+
+	alias, ok := levelAliases[val]
+	if ok {
+		return alias
+	}
+
+	return val
+}
+
+func (ptn *Pattern) convert(key, val string) any {
+
+	switch ptn.types[key] {
+	case "int":
+		num, err := strconv.ParseInt(val, 10, 64)
+		if err == nil {
+			return num
+		}
+	case "float":
+		num, err := strconv.ParseFloat(val, 64)
+		if err == nil {
+			return num
+		}
+	}
+
+	return val
+}
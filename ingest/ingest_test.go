@@ -0,0 +1,117 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestIngest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ingest Suite")
+}
+
+var _ = Describe("Parser", func() {
+
+	var (
+		cfg    *Config
+		psr    *Parser
+		src    string
+		events []sabot.Event
+		errs   []error
+		err    error
+	)
+
+	BeforeEach(func() {
+		cfg = &Config{Patterns: []string{
+			`%{TIMESTAMP_ISO8601:ts} %{LOGLEVEL:level} \[%{WORD:http.method}\] %{DATA:msg} took %{NUMBER:elapsed_ms:float}ms`,
+			`^(?P<level>[A-Z]+): (?P<msg>.*)$`,
+		}}
+		src = strings.Join([]string{
+			"2026-10-15 01:02:03,456 WARNING [GET] slow request took 340.5ms",
+			"  at handler.go:12",
+			"",
+			"ERROR: out of disk",
+			"what is this",
+		}, "\n")
+	})
+
+	JustBeforeEach(func() {
+		events, errs = nil, nil
+
+		psr, err = cfg.New()
+		if err != nil {
+			return
+		}
+
+		for evt, pErr := range psr.Parse(strings.NewReader(src)) {
+			if pErr != nil {
+				errs = append(errs, pErr)
+				continue
+			}
+			events = append(events, evt)
+		}
+	})
+
+	When("lines match grok and regex patterns", func() {
+		It("should parse boilerplate and fields, erroring on the unmatched", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(Equal([]sabot.Event{
+				{
+					Time:   time.Date(2026, 10, 15, 1, 2, 3, 456000000, time.UTC),
+					Level:  "warn",
+					Msg:    "slow request",
+					Fields: sabot.Fields{"http.method": "GET", "elapsed_ms": 340.5},
+				},
+				{Level: "error", Msg: "out of disk", Fields: sabot.Fields{}},
+			}))
+
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0]).To(MatchError("line 2: no pattern matches"))
+			Expect(errs[1]).To(MatchError("line 5: no pattern matches"))
+		})
+	})
+
+	When("multiline", func() {
+		BeforeEach(func() {
+			cfg.Multiline = true
+			cfg.Level = "debug"
+			cfg.Patterns = append(cfg.Patterns, `^what`)
+		})
+
+		It("should append unmatched lines to the previous msg", func() {
+			Expect(errs).To(BeEmpty())
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Msg).To(Equal("slow request\n  at handler.go:12"))
+			Expect(events[2]).To(Equal(sabot.Event{Level: "debug", Msg: "what is this", Fields: sabot.Fields{}}))
+		})
+	})
+
+	When("a grok pattern is unknown", func() {
+		BeforeEach(func() {
+			cfg.Patterns = []string{`%{NOPE:msg}`}
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError("unknown grok pattern: NOPE"))
+		})
+	})
+
+	When("a time layout is given", func() {
+		BeforeEach(func() {
+			cfg.TimeLayout = "Jan 2 2006 15:04"
+			cfg.Patterns = []string{`^(?P<ts>\w+ \d+ \d+ \d+:\d+) (?P<msg>.*)`}
+			src = "Oct 15 2026 01:02 started"
+		})
+
+		It("should parse ts with it", func() {
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Time).To(Equal(time.Date(2026, 10, 15, 1, 2, 0, 0, time.UTC)))
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package sabot
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyMode is how dotted keys, such as http.status, are treated by json encoders.
+type KeyMode string
+
+const (
+	// KeysAsIs leaves keys as logged.
+	KeysAsIs KeyMode = ""
+	// KeysExpand expands dotted keys into nested objects.
+	KeysExpand KeyMode = "expand"
+	// KeysFlatten flattens nested objects into dotted keys.
+	KeysFlatten KeyMode = "flatten"
+)
+
+//
+// unexported
+//
+
+func (mode KeyMode) apply(fields Fields) Fields {
+
+	switch mode {
+	case KeysExpand:
+		return expand(fields)
+	case KeysFlatten:
+		flat := Fields{}
+		flatten(flat, "", fields)
+		return flat
+	}
+
+	return fields
+}
+
+func expand(fields Fields) Fields {
+
+	// prefixes sort first, so that conflicts are settled the same way each time
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	expanded := Fields{}
+	for _, key := range keys {
+		if !place(expanded, strings.Split(key, "."), fields[key]) {
+			// keep as-is when a part is empty or already holds a value
+			expanded[key] = fields[key]
+		}
+	}
+
+	return expanded
+}
+
+func place(node map[string]any, parts []string, val any) bool {
+
+	for _, part := range parts[:len(parts)-1] {
+		if part == "" {
+			return false
+		}
+
+		child, ok := node[part]
+		if !ok {
+			child = map[string]any{}
+			node[part] = child
+		}
+
+		node, ok = child.(map[string]any)
+		if !ok {
+			return false
+		}
+	}
+
+	leaf := parts[len(parts)-1]
+	_, taken := node[leaf]
+	if leaf == "" || taken {
+		return false
+	}
+
+	node[leaf] = val
+	return true
+}
+
+func flatten(flat Fields, prefix string, node map[string]any) {
+
+	for key, val := range node {
+
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch val := val.(type) {
+		case map[string]any:
+			flatten(flat, key, val)
+		case Fields:
+			flatten(flat, key, val)
+		case group:
+			flatten(flat, key, val)
+		default:
+			flat[key] = val
+		}
+	}
+}
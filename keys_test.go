@@ -0,0 +1,73 @@
+package sabot
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Keys", func() {
+
+	var (
+		evt *Event
+	)
+
+	BeforeEach(func() {
+		evt = &Event{
+			Time:  time.Date(2023, 11, 25, 21, 20, 54, 0, time.UTC),
+			Level: "info",
+			Msg:   "served",
+			Fields: Fields{
+				"http.status": 200,
+				"http.method": "GET",
+				"user":        "u1",
+				"user.id":     "conflicted",
+				"nested":      map[string]any{"a": map[string]any{"b": 1}},
+			},
+		}
+	})
+
+	When("expanding with the json encoder", func() {
+		It("should nest dotted keys, leaving conflicts dotted", func() {
+			data, err := JSON{Keys: KeysExpand}.Encode(evt)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(unjson(data)).To(Equal(map[string]any{
+				"ts":      "2023-11-25T21:20:54Z",
+				"level":   "info",
+				"msg":     "served",
+				"http":    map[string]any{"status": float64(200), "method": "GET"},
+				"user":    "u1",
+				"user.id": "conflicted",
+				"nested":  map[string]any{"a": map[string]any{"b": float64(1)}},
+			}))
+		})
+	})
+
+	When("flattening with the ecs encoder", func() {
+		It("should dot nested keys", func() {
+			data, err := ECS{Keys: KeysFlatten}.Encode(evt)
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := unjson(data)
+			Expect(decoded).To(HaveKeyWithValue("nested.a.b", float64(1)))
+			Expect(decoded).To(HaveKeyWithValue("http.status", float64(200)))
+			Expect(decoded).To(HaveKeyWithValue("log.level", "info"))
+		})
+	})
+
+	When("configured", func() {
+		It("should pick an encoder with the mode", func() {
+			Expect((&Config{Keys: KeysExpand}).New(nil).Encoder).To(Equal(JSON{Keys: KeysExpand}))
+			Expect((&Config{}).New(nil).Encoder).To(BeNil())
+		})
+
+		It("should validate the mode against the format", func() {
+			Expect((&Config{Keys: "deep"}).Validate()).To(MatchError("unknown keys mode: deep"))
+			Expect((&Config{Keys: KeysFlatten, Format: "logfmt"}).Validate()).To(
+				MatchError("keys mode not supported by format: logfmt"),
+			)
+		})
+	})
+})
@@ -0,0 +1,225 @@
+package sabot
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// Level is the severity of an event, greater being more severe.
+//
+// Built-in levels are spaced so that custom levels can be registered between them.
+type Level int
+
+// Built-in levels, with audit above all others as audit events are never to be skipped.
+const (
+	Trace Level = -8
+	Debug Level = -4
+	Info  Level = 0
+	Warn  Level = 4
+	Error Level = 8
+	Fatal Level = 12
+	Audit Level = 16
+)
+
+var (
+	levelNames = map[Level]string{
+		Trace: "trace",
+		Debug: "debug",
+		Info:  "info",
+		Warn:  "warn",
+		Error: "error",
+		Fatal: "fatal",
+		Audit: "audit",
+	}
+	levelValues = map[string]Level{
+		"trace": Trace,
+		"debug": Debug,
+		"info":  Info,
+		"warn":  Warn,
+		"error": Error,
+		"fatal": Fatal,
+		"audit": Audit,
+	}
+	levelMu sync.RWMutex
+)
+
+// RegisterLevel adds a custom level, ordered relative to built-ins by value.
+//
+// For example, RegisterLevel("notice", sabot.Info+1) orders notice just above info.
+// Register levels during init, before logging.
+func RegisterLevel(name string, lvl Level) error {
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	if name == "" {
+		return errors.Errorf("level name is empty")
+	}
+
+	_, ok := levelValues[name]
+	if ok {
+		return errors.Errorf("level already registered: %s", name)
+	}
+
+	other, ok := levelNames[lvl]
+	if ok {
+		return errors.Errorf("level value %d already registered as: %s", lvl, other)
+	}
+
+	levelNames[lvl] = name
+	levelValues[name] = lvl
+
+	return nil
+}
+
+// ParseLevel returns the level registered under name.
+func ParseLevel(name string) (lvl Level, err error) {
+
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+
+	lvl, ok := levelValues[name]
+	if !ok {
+		err = errors.Errorf("unknown level: %s", name)
+	}
+
+	return
+}
+
+// String returns the name of a level.
+func (lvl Level) String() string {
+
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+
+	name, ok := levelNames[lvl]
+	if !ok {
+		return "unknown"
+	}
+
+	return name
+}
+
+// MarshalText returns the name of a level, for config and json encoding.
+func (lvl Level) MarshalText() ([]byte, error) {
+
+	return []byte(lvl.String()), nil
+}
+
+// UnmarshalText sets a level from its name, with empty taken as info.
+func (lvl *Level) UnmarshalText(text []byte) (err error) {
+
+	if len(text) == 0 {
+		*lvl = Info
+		return
+	}
+
+	*lvl, err = ParseLevel(string(text))
+	return
+}
+
+// SetLevel sets the least severe level logged, overriding MinLevel, and is safe while logging.
+func (sabot *Sabot) SetLevel(lvl Level) {
+
+	// level is stored before it's marked set, so readers never see a set zero
+
+	atomic.StoreInt64(&sabot.level, int64(lvl))
+	atomic.StoreInt32(&sabot.levelSet, 1)
+}
+
+// GetLevel returns the least severe level logged, as set by SetLevel or MinLevel.
+func (sabot *Sabot) GetLevel() Level {
+
+	if atomic.LoadInt32(&sabot.levelSet) == 0 {
+		return sabot.MinLevel
+	}
+
+	return Level(atomic.LoadInt64(&sabot.level))
+}
+
+// WithLevel adds a level override to ctx, taking precedence over GetLevel for events logged with it.
+//
+// Useful for enabling debug or trace for a single suspicious request while the rest stays at info.
+func (sabot *Sabot) WithLevel(ctx context.Context, lvl Level) context.Context {
+
+	return context.WithValue(ctx, levelKey{}, lvl)
+}
+
+// Enabled reports whether events at lvl would be logged with ctx, as for adapters checking ahead of time.
+func (sabot *Sabot) Enabled(ctx context.Context, lvl Level) bool {
+
+	return sabot.enabled(ctx, lvl)
+}
+
+// Log logs events at a given level, built-in or registered.
+func (sabot *Sabot) Log(ctx context.Context, lvl Level, msg string, kv ...any) {
+
+	if !sabot.enabled(ctx, lvl) {
+		return
+	}
+
+	sabot.log(ctx, lvl.String(), msg, nil, kv)
+}
+
+//
+// unexported
+//
+
+type levelKey struct{}
+
+func (sabot *Sabot) enabled(ctx context.Context, lvl Level) bool {
+
+	if capturing(ctx) {
+		return true
+	}
+
+	if sabot.Disabled[lvl] {
+		return false
+	}
+
+	override, ok := ctx.Value(levelKey{}).(Level)
+	if ok {
+		return lvl >= override
+	}
+
+	// deprecated bools enable their level regardless of MinLevel
+
+	switch {
+	case lvl == Trace && sabot.EnableTrace:
+		return true
+	case lvl == Debug && sabot.EnableDebug:
+		return true
+	}
+
+	return lvl >= sabot.GetLevel()
+}
+
+func (sabot *Sabot) writerFor(level string) (writer io.Writer) {
+
+	writer = sabot.Writer
+	if len(sabot.Routes) == 0 {
+		return
+	}
+
+	route, ok := sabot.Routes[levelOf(level)]
+	if ok {
+		writer = route
+	}
+
+	return
+}
+
+// levelOf returns the level for a name, with unknown names taken as info.
+func levelOf(name string) Level {
+
+	lvl, err := ParseLevel(name)
+	if err != nil {
+		return Info
+	}
+
+	return lvl
+}
@@ -0,0 +1,234 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Levels", func() {
+
+	var (
+		buf       *bytes.Buffer
+		noticeBuf *bytes.Buffer
+		lgr       *Sabot
+		ctx       context.Context
+	)
+
+	notice := Info + 1
+	BeforeEach(func() {
+		err := RegisterLevel("notice", notice)
+		if err != nil {
+			Expect(err).To(MatchError("level already registered: notice"))
+		}
+
+		buf = &bytes.Buffer{}
+		noticeBuf = &bytes.Buffer{}
+		lgr = &Sabot{
+			Writer: buf,
+			Routes: map[Level]io.Writer{notice: noticeBuf},
+		}
+		ctx = context.Background()
+	})
+
+	Describe("registering a level", func() {
+		It("should be parsable and ordered", func() {
+			lvl, err := ParseLevel("notice")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(lvl).To(Equal(notice))
+			Expect(lvl.String()).To(Equal("notice"))
+			Expect(lvl > Info && lvl < Error).To(BeTrue())
+		})
+
+		It("should reject duplicate values", func() {
+			Expect(RegisterLevel("chatter", Info)).To(MatchError("level value 0 already registered as: info"))
+		})
+
+		It("should reject unknown names", func() {
+			_, err := ParseLevel("loud")
+			Expect(err).To(MatchError("unknown level: loud"))
+			Expect(Level(99).String()).To(Equal("unknown"))
+		})
+	})
+
+	Describe("logging at a custom level", func() {
+		JustBeforeEach(func() {
+			lgr.Log(ctx, notice, "disk nearly full", "pct", 91)
+			lgr.Info(ctx, "routine")
+		})
+
+		It("should route by level", func() {
+			Expect(delog(noticeBuf)).To(Equal(Fields{
+				"level": "notice",
+				"msg":   "disk nearly full",
+				"ts":    "nowish",
+				"pct":   float64(91),
+			}))
+			Expect(delog(buf)).To(HaveKeyWithValue("msg", "routine"))
+		})
+
+		When("the level is disabled", func() {
+			BeforeEach(func() {
+				lgr.Disabled = map[Level]bool{notice: true, Info: true}
+			})
+
+			It("should skip", func() {
+				Expect(noticeBuf.Len()).To(BeZero())
+				Expect(buf.Len()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("logging at a built-in level", func() {
+		JustBeforeEach(func() {
+			lgr.Log(ctx, Debug, "looking")
+			lgr.Log(ctx, Error, "failed", "error", "oops")
+		})
+
+		It("should honor built-in enablement", func() {
+			Expect(delog(buf)).To(HaveKeyWithValue("msg", "failed"))
+		})
+	})
+
+	Describe("setting a min level", func() {
+		JustBeforeEach(func() {
+			lgr.Debug(ctx, "looking")
+			lgr.Info(ctx, "routine")
+			lgr.Warn(ctx, "retrying")
+		})
+
+		When("min level is debug", func() {
+			BeforeEach(func() {
+				lgr.MinLevel = Debug
+			})
+
+			It("should log debug and above", func() {
+				msgs := []string{}
+				for evt, err := range Decode(buf) {
+					Expect(err).ToNot(HaveOccurred())
+					msgs = append(msgs, evt.Msg)
+				}
+				Expect(msgs).To(Equal([]string{"looking", "routine", "retrying"}))
+			})
+		})
+
+		When("min level is warn", func() {
+			BeforeEach(func() {
+				lgr.MinLevel = Warn
+			})
+
+			It("should silence info", func() {
+				Expect(delog(buf)).To(HaveKeyWithValue("msg", "retrying"))
+			})
+		})
+
+		When("set at runtime", func() {
+			BeforeEach(func() {
+				lgr.MinLevel = Debug
+			})
+
+			It("should override min level", func() {
+				Expect(lgr.GetLevel()).To(Equal(Debug))
+
+				lgr.SetLevel(Warn)
+				Expect(lgr.GetLevel()).To(Equal(Warn))
+
+				buf.Reset()
+				lgr.Info(ctx, "skipped")
+				lgr.Warn(ctx, "retrying")
+				Expect(delog(buf)).To(HaveKeyWithValue("msg", "retrying"))
+			})
+
+			It("should be safe while logging", func() {
+				done := make(chan struct{})
+				go func() {
+					defer close(done)
+					for i := 0; i < 100; i++ {
+						lgr.Info(ctx, "racing")
+					}
+				}()
+				lgr.SetLevel(Error)
+				<-done
+
+				Expect(lgr.GetLevel()).To(Equal(Error))
+			})
+		})
+
+		When("overridden for a ctx", func() {
+			It("should take precedence over the logger's level", func() {
+				buf.Reset()
+				suspect := lgr.WithLevel(ctx, Trace)
+				lgr.Trace(suspect, "tracing suspect")
+				lgr.Bind(suspect).Trace("tracing bound")
+				lgr.Trace(ctx, "tracing others")
+
+				msgs := []string{}
+				for evt, err := range Decode(buf) {
+					Expect(err).ToNot(HaveOccurred())
+					msgs = append(msgs, evt.Msg)
+				}
+				Expect(msgs).To(Equal([]string{"tracing suspect", "tracing bound"}))
+			})
+
+			It("should be able to silence as well", func() {
+				buf.Reset()
+				lgr.Warn(lgr.WithLevel(ctx, Error), "quieted")
+				Expect(buf.Len()).To(BeZero())
+			})
+		})
+
+		When("configured by name", func() {
+			It("should unmarshal from json", func() {
+				cfg := &Config{}
+				Expect(json.Unmarshal([]byte(`{"min_level":"warn"}`), cfg)).To(Succeed())
+				Expect(cfg.MinLevel).To(Equal(Warn))
+
+				Expect(json.Unmarshal([]byte(`{"min_level":"loud"}`), cfg)).To(MatchError("unknown level: loud"))
+			})
+
+			It("should default to info when empty", func() {
+				lvl := Warn
+				Expect(lvl.UnmarshalText(nil)).To(Succeed())
+				Expect(lvl).To(Equal(Info))
+			})
+		})
+	})
+
+	Describe("logging at warn level", func() {
+		JustBeforeEach(func() {
+			lgr.Warn(ctx, "retrying", "attempt", 2)
+			lgr.Bind(ctx).Warn("retrying bound")
+			(&Dual{Old: lgr, New: lgr}).Warn(ctx, "retrying dual")
+		})
+
+		It("should log warn between info and error", func() {
+			Expect(Warn > notice && Warn < Error).To(BeTrue())
+
+			msgs := []string{}
+			for evt, err := range Decode(buf) {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(evt.Level).To(Equal("warn"))
+				msgs = append(msgs, evt.Msg)
+			}
+			Expect(msgs).To(Equal([]string{"retrying", "retrying bound", "retrying dual"}))
+		})
+
+		It("should map to the syslog warning severity", func() {
+			Expect(syslogLevel(Warn)).To(Equal(4))
+		})
+
+		When("warn is disabled", func() {
+			BeforeEach(func() {
+				lgr.Disabled = map[Level]bool{Warn: true}
+			})
+
+			It("should skip", func() {
+				Expect(buf.Len()).To(BeZero())
+			})
+		})
+	})
+})
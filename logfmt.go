@@ -0,0 +1,76 @@
+package sabot
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Logfmt encodes events as logfmt key=value pairs.
+//
+// Boilerplate comes first with remaining fields sorted by key.
+// Values are quoted when they contain spaces, quotes, equals, or control characters.
+type Logfmt struct{}
+
+// Encode encodes an event.
+func (enc Logfmt) Encode(evt *Event) (data []byte, err error) {
+
+	buf := &bytes.Buffer{}
+
+	writePair(buf, "ts", stringify(evt.ts()))
+	writePair(buf, "level", evt.Level)
+	writePair(buf, "msg", evt.Msg)
+
+	keys := make([]string, 0, len(evt.Fields))
+	for key := range evt.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		writePair(buf, key, stringify(evt.Fields[key]))
+	}
+
+	data = buf.Bytes()
+	return
+}
+
+//
+// unexported
+//
+
+func writePair(buf *bytes.Buffer, key, val string) {
+
+	if buf.Len() > 0 {
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(logfmtKey(key))
+	buf.WriteByte('=')
+	writeValue(buf, val)
+}
+
+func writeValue(buf *bytes.Buffer, val string) {
+
+	if val == "" || strings.ContainsAny(val, " =\"\\") || strings.IndexFunc(val, isControl) >= 0 {
+		buf.WriteString(strconv.Quote(val))
+		return
+	}
+	buf.WriteString(val)
+}
+
+func logfmtKey(key string) string {
+
+	return strings.Map(func(rn rune) rune {
+		if rn <= ' ' || rn == '=' || rn == '"' {
+			return '_'
+		}
+		return rn
+	}, key)
+}
+
+func isControl(rn rune) bool {
+
+	return rn < ' ' || rn == 0x7f
+}
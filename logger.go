@@ -0,0 +1,41 @@
+package sabot
+
+import (
+	"context"
+)
+
+// Logger is the contextual logging api common to Sabot, Dual, and Discard,
+// for libraries to depend on rather than a concrete logger.
+type Logger interface {
+	Info(ctx context.Context, msg string, kv ...any)
+	Warn(ctx context.Context, msg string, kv ...any)
+	Error(ctx context.Context, msg string, err error, kv ...any)
+	Debug(ctx context.Context, msg string, kv ...any)
+	Trace(ctx context.Context, msg string, kv ...any)
+	WithFields(ctx context.Context, kv ...any) context.Context
+}
+
+// Discard returns a Logger that drops everything without doing any work, as for tests.
+//
+// WithFields returns ctx as given.
+func Discard() Logger {
+
+	return discard{}
+}
+
+//
+// unexported
+//
+
+type discard struct{}
+
+func (discard) Info(ctx context.Context, msg string, kv ...any)             {}
+func (discard) Warn(ctx context.Context, msg string, kv ...any)             {}
+func (discard) Error(ctx context.Context, msg string, err error, kv ...any) {}
+func (discard) Debug(ctx context.Context, msg string, kv ...any)            {}
+func (discard) Trace(ctx context.Context, msg string, kv ...any)            {}
+
+func (discard) WithFields(ctx context.Context, kv ...any) context.Context {
+
+	return ctx
+}
@@ -0,0 +1,34 @@
+package sabot
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Logger", func() {
+
+	var (
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should be implemented by Sabot, Dual, and Discard", func() {
+		Expect([]Logger{&Sabot{}, &Dual{}, Discard()}).To(HaveLen(3))
+	})
+
+	When("discarding", func() {
+		It("should drop everything, leaving ctx as is", func() {
+			lgr := Discard()
+			lgr.Info(ctx, "dropped", "count", 3)
+			lgr.Error(ctx, "dropped", errors.New("oops"))
+
+			Expect(lgr.WithFields(ctx, "run_id", "123")).To(BeIdenticalTo(ctx))
+		})
+	})
+})
@@ -0,0 +1,102 @@
+// Package logrsink adapts Sabot as a logr.LogSink, for libraries taking a logr.Logger such as controller-runtime.
+//
+// V-levels map onto sabot levels, zero being info, one debug, and greater trace.
+// Names given via WithName are joined with "/" and logged as the logger field.
+package logrsink
+
+import (
+	"context"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const nameKey string = "logger"
+
+// Sink is a logr.LogSink logging via Sabot.
+type Sink struct {
+	lgr   *sabot.Sabot
+	ctx   context.Context
+	kv    []any
+	names []string
+}
+
+// New creates a logr.Logger logging via lgr, with fields from ctx.
+func New(ctx context.Context, lgr *sabot.Sabot) logr.Logger {
+
+	return logr.New(NewSink(ctx, lgr))
+}
+
+// NewSink creates a Sink logging via lgr, with fields from ctx.
+func NewSink(ctx context.Context, lgr *sabot.Sabot) *Sink {
+
+	return &Sink{lgr: lgr, ctx: ctx}
+}
+
+// Init is a no-op, call depth not being needed.
+func (snk *Sink) Init(info logr.RuntimeInfo) {}
+
+// Enabled reports whether lgr logs at the level a V-level maps to.
+func (snk *Sink) Enabled(level int) bool {
+
+	return snk.lgr.Enabled(snk.ctx, levelOf(level))
+}
+
+// Info logs a non-error event at the level a V-level maps to.
+func (snk *Sink) Info(level int, msg string, kv ...any) {
+
+	snk.lgr.Log(snk.ctx, levelOf(level), msg, snk.fields(kv)...)
+}
+
+// Error logs an error level event.
+func (snk *Sink) Error(err error, msg string, kv ...any) {
+
+	snk.lgr.Error(snk.ctx, msg, err, snk.fields(kv)...)
+}
+
+// WithValues returns a Sink adding kv to each event.
+func (snk *Sink) WithValues(kv ...any) logr.LogSink {
+
+	clone := *snk
+	clone.kv = append(append([]any(nil), snk.kv...), kv...)
+
+	return &clone
+}
+
+// WithName returns a Sink with name appended to the logger field.
+func (snk *Sink) WithName(name string) logr.LogSink {
+
+	clone := *snk
+	clone.names = append(append([]string(nil), snk.names...), name)
+
+	return &clone
+}
+
+//
+// unexported
+//
+
+func (snk *Sink) fields(kv []any) []any {
+
+	fields := make([]any, 0, len(snk.kv)+len(kv)+2)
+	if len(snk.names) > 0 {
+		fields = append(fields, nameKey, strings.Join(snk.names, "/"))
+	}
+	fields = append(fields, snk.kv...)
+
+	return append(fields, kv...)
+}
+
+func levelOf(level int) sabot.Level {
+
+	switch {
+	case level <= 0:
+		return sabot.Info
+	case level == 1:
+		return sabot.Debug
+	default:
+		return sabot.Trace
+	}
+}
@@ -0,0 +1,70 @@
+package logrsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestLogrSink(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "LogrSink Suite")
+}
+
+var _ = Describe("Sink", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *sabot.Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &sabot.Sabot{Writer: buf, MinLevel: sabot.Debug}
+		ctx = lgr.WithFields(context.Background(), "run_id", "123")
+	})
+
+	When("logging with names and values", func() {
+		JustBeforeEach(func() {
+			log := New(ctx, lgr).WithName("controller").WithName("pod").WithValues("namespace", "default")
+			log.V(1).Info("reconciling", "pod", "web-0")
+			log.V(2).Info("skipped at trace")
+		})
+
+		It("should log at the mapped level with fields", func() {
+			fields := sabot.Fields{}
+			Expect(json.Unmarshal(buf.Bytes(), &fields)).To(Succeed())
+			delete(fields, "ts")
+
+			Expect(fields).To(Equal(sabot.Fields{
+				"level":     "debug",
+				"msg":       "reconciling",
+				"run_id":    "123",
+				"logger":    "controller/pod",
+				"namespace": "default",
+				"pod":       "web-0",
+			}))
+		})
+	})
+
+	When("logging an error", func() {
+		JustBeforeEach(func() {
+			New(ctx, lgr).Error(errors.New("oops"), "failed to reconcile")
+		})
+
+		It("should log at error level", func() {
+			fields := sabot.Fields{}
+			Expect(json.Unmarshal(buf.Bytes(), &fields)).To(Succeed())
+			Expect(fields).To(HaveKeyWithValue("level", "error"))
+			Expect(fields).To(HaveKeyWithValue("error", "oops"))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package sabot
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const misuseNotice string = "sabot misuse"
+
+// misuseSeen holds call site and kind of misuse already warned of.
+var misuseSeen sync.Map
+
+// pkgDir is where this package's source lives, for telling callers apart.
+var pkgDir = func() string {
+
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+//
+// unexported
+//
+
+type misuseKey struct {
+	pc   uintptr
+	kind string
+}
+
+// checkMisuse warns once per call site of kv that would yield logerror fields or lose data.
+func (sabot *Sabot) checkMisuse(ctx context.Context, kv []any) {
+
+	kinds := sabot.misuses(kv)
+	if len(kinds) == 0 {
+		return
+	}
+
+	pc, site := caller(0)
+	for _, kind := range kinds {
+
+		_, seen := misuseSeen.LoadOrStore(misuseKey{pc: pc, kind: kind}, true)
+		if seen {
+			continue
+		}
+
+		sabot.emit(ctx, nil, "warn", misuseNotice, nil, []any{"misuse", kind, "caller", site})
+	}
+}
+
+func (sabot *Sabot) misuses(kv []any) (kinds []string) {
+
+	for i := 0; i < len(kv); {
+
+		_, ok := kv[i].(Field)
+		if ok {
+			i++
+			continue
+		}
+
+		if i+1 == len(kv) {
+			kinds = append(kinds, "odd kv count")
+			return
+		}
+
+		key, ok := kv[i].(string)
+		switch {
+		case !ok:
+			kinds = append(kinds, "non-string key")
+		case key == "msg" || key == "level" || key == "ts":
+			kinds = append(kinds, fmt.Sprintf("reserved key: %s", key))
+		}
+
+		str, ok := kv[i+1].(string)
+		if ok && sabot.MaxLen > 0 && len(str) > sabot.MaxLen {
+			kinds = append(kinds, fmt.Sprintf("oversized value: %v", kv[i]))
+		}
+
+		i += 2
+	}
+
+	return
+}
+
+// caller finds the first frame outside of this package, tests aside, and then skip frames further out.
+func caller(skip int) (pc uintptr, site string) {
+
+	pcs := make([]uintptr, 32)
+	count := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:count])
+
+	for {
+		frame, more := frames.Next()
+
+		inPkg := filepath.Dir(frame.File) == pkgDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !inPkg {
+			if skip == 0 {
+				return frame.PC, fmt.Sprintf("%s:%d", frame.File, frame.Line)
+			}
+			skip--
+		}
+		if !more {
+			return
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Misuse", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf, MaxLen: 99, WarnMisuse: true}
+		ctx = context.Background()
+	})
+
+	warnings := func() (found []Fields) {
+		for evt, err := range Decode(bytes.NewReader(buf.Bytes())) {
+			Expect(err).ToNot(HaveOccurred())
+			if evt.Msg == "sabot misuse" {
+				found = append(found, evt.Fields)
+			}
+		}
+		return
+	}
+
+	When("the same call site misuses repeatedly", func() {
+		It("should warn once with file and line", func() {
+			for i := 0; i < 3; i++ {
+				lgr.Info(ctx, "misusing", "dangling")
+			}
+
+			found := warnings()
+			Expect(found).To(HaveLen(1))
+			Expect(found[0]["misuse"]).To(Equal("odd kv count"))
+			Expect(found[0]["caller"]).To(MatchRegexp(`misuse_test\.go:\d+$`))
+		})
+	})
+
+	When("misusing in several ways", func() {
+		It("should warn of each", func() {
+			lgr.Info(ctx, "misusing", "msg", "shadowed", 7, "non-string", "big", strings.Repeat("b", 100))
+
+			kinds := []any{}
+			for _, fields := range warnings() {
+				kinds = append(kinds, fields["misuse"])
+			}
+			Expect(kinds).To(ConsistOf("reserved key: msg", "non-string key", "oversized value: big"))
+		})
+	})
+
+	When("not enabled", func() {
+		It("should not warn", func() {
+			lgr.WarnMisuse = false
+			lgr.Info(ctx, "misusing", "dangling")
+
+			Expect(warnings()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,253 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Msgpack encodes events as a MessagePack map, as with json, for file and network sinks
+// where encode cost and size count, and as spoken by fluentd.
+//
+// Times are encoded with the msgpack timestamp extension, unless ts is formatted per TimeLayout.
+// Keys are sorted, so that like events encode alike.
+type Msgpack struct{}
+
+// Encode encodes an event.
+func (enc Msgpack) Encode(evt *Event) (data []byte, err error) {
+
+	return appendMsgpack(nil, evt.Merged()), nil
+}
+
+// Binary marks Msgpack as binary, its events being written without a trailing newline.
+func (enc Msgpack) Binary() {}
+
+//
+// unexported
+//
+
+const (
+	mpNil       byte = 0xc0
+	mpFalse     byte = 0xc2
+	mpTrue      byte = 0xc3
+	mpBin8      byte = 0xc4
+	mpBin16     byte = 0xc5
+	mpBin32     byte = 0xc6
+	mpExt8      byte = 0xc7
+	mpFloat64   byte = 0xcb
+	mpUint8     byte = 0xcc
+	mpUint16    byte = 0xcd
+	mpUint32    byte = 0xce
+	mpUint64    byte = 0xcf
+	mpInt8      byte = 0xd0
+	mpInt16     byte = 0xd1
+	mpInt32     byte = 0xd2
+	mpInt64     byte = 0xd3
+	mpStr8      byte = 0xd9
+	mpStr16     byte = 0xda
+	mpStr32     byte = 0xdb
+	mpArray16   byte = 0xdc
+	mpArray32   byte = 0xdd
+	mpMap16     byte = 0xde
+	mpMap32     byte = 0xdf
+	mpFixStr    byte = 0xa0
+	mpFixArray  byte = 0x90
+	mpFixMap    byte = 0x80
+	mpTimestamp byte = 0xff
+)
+
+// appendMsgpack appends the msgpack of a value, objects becoming maps and slices arrays.
+func appendMsgpack(data []byte, val any) []byte {
+
+	switch val := val.(type) {
+	case nil:
+		return append(data, mpNil)
+	case string:
+		return appendMpStr(data, val)
+	case []byte:
+		return appendMpBin(data, val)
+	case time.Time:
+		// timestamp 96, as seconds may not fit in 34 bits
+
+		data = append(data, mpExt8, 12, mpTimestamp)
+		data = binary.BigEndian.AppendUint32(data, uint32(val.Nanosecond()))
+		return binary.BigEndian.AppendUint64(data, uint64(val.Unix()))
+	case json.Number:
+		num, err := val.Int64()
+		if err == nil {
+			return appendMpInt(data, num)
+		}
+		flt, _ := val.Float64()
+		return appendMpFloat(data, flt)
+	case json.RawMessage:
+		return appendMpJson(data, val)
+	case nested:
+		return appendMpJson(data, val)
+	case group:
+		return appendMpMap(data, val)
+	case Fields:
+		return appendMpMap(data, val)
+	case map[string]any:
+		return appendMpMap(data, val)
+	case []string:
+		data = appendMpLen(data, len(val), mpFixArray, mpArray16, mpArray32)
+		for _, str := range val {
+			data = appendMpStr(data, str)
+		}
+		return data
+	case []any:
+		data = appendMpLen(data, len(val), mpFixArray, mpArray16, mpArray32)
+		for _, elem := range val {
+			data = appendMsgpack(data, elem)
+		}
+		return data
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.String:
+		return appendMpStr(data, rv.String())
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(data, mpTrue)
+		}
+		return append(data, mpFalse)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMpInt(data, rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return appendMpUint(data, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendMpFloat(data, rv.Float())
+	}
+
+	// as json for anything else, as with kv
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return appendMpStr(data, fmt.Sprintf("%v", val))
+	}
+	return appendMpJson(data, raw)
+}
+
+func appendMpJson(data, raw []byte) []byte {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var val any
+	err := decoder.Decode(&val)
+	if err != nil {
+		return appendMpStr(data, string(raw))
+	}
+
+	return appendMsgpack(data, val)
+}
+
+func appendMpMap(data []byte, fields map[string]any) []byte {
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	data = appendMpLen(data, len(keys), mpFixMap, mpMap16, mpMap32)
+	for _, key := range keys {
+		data = appendMpStr(data, key)
+		data = appendMsgpack(data, fields[key])
+	}
+
+	return data
+}
+
+// appendMpLen appends the header of a map or array, fixed for fewer than sixteen elements.
+func appendMpLen(data []byte, size int, fixed, code16, code32 byte) []byte {
+
+	switch {
+	case size < 16:
+		return append(data, fixed|byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, code16), uint16(size))
+	}
+
+	return binary.BigEndian.AppendUint32(append(data, code32), uint32(size))
+}
+
+func appendMpStr(data []byte, str string) []byte {
+
+	size := len(str)
+	switch {
+	case size < 32:
+		data = append(data, mpFixStr|byte(size))
+	case size <= math.MaxUint8:
+		data = append(data, mpStr8, byte(size))
+	case size <= math.MaxUint16:
+		data = binary.BigEndian.AppendUint16(append(data, mpStr16), uint16(size))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, mpStr32), uint32(size))
+	}
+
+	return append(data, str...)
+}
+
+func appendMpBin(data, bin []byte) []byte {
+
+	size := len(bin)
+	switch {
+	case size <= math.MaxUint8:
+		data = append(data, mpBin8, byte(size))
+	case size <= math.MaxUint16:
+		data = binary.BigEndian.AppendUint16(append(data, mpBin16), uint16(size))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, mpBin32), uint32(size))
+	}
+
+	return append(data, bin...)
+}
+
+// appendMpInt appends an int in the fewest bytes, fixints for -32 through 127.
+func appendMpInt(data []byte, num int64) []byte {
+
+	if num >= 0 {
+		return appendMpUint(data, uint64(num))
+	}
+
+	switch {
+	case num >= -32:
+		return append(data, byte(num))
+	case num >= math.MinInt8:
+		return append(data, mpInt8, byte(num))
+	case num >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(data, mpInt16), uint16(num))
+	case num >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(data, mpInt32), uint32(num))
+	}
+
+	return binary.BigEndian.AppendUint64(append(data, mpInt64), uint64(num))
+}
+
+func appendMpUint(data []byte, num uint64) []byte {
+
+	switch {
+	case num <= math.MaxInt8:
+		return append(data, byte(num))
+	case num <= math.MaxUint8:
+		return append(data, mpUint8, byte(num))
+	case num <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(data, mpUint16), uint16(num))
+	case num <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(data, mpUint32), uint32(num))
+	}
+
+	return binary.BigEndian.AppendUint64(append(data, mpUint64), num)
+}
+
+func appendMpFloat(data []byte, flt float64) []byte {
+
+	return binary.BigEndian.AppendUint64(append(data, mpFloat64), math.Float64bits(flt))
+}
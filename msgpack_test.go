@@ -0,0 +1,196 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// unpack decodes a single msgpack value, enough for checking Msgpack, returning what follows it.
+func unpack(data []byte) (val any, rest []byte) {
+
+	code := data[0]
+	data = data[1:]
+
+	size := func(width int) (int, []byte) {
+		switch width {
+		case 1:
+			return int(data[0]), data[1:]
+		case 2:
+			return int(binary.BigEndian.Uint16(data)), data[2:]
+		}
+		return int(binary.BigEndian.Uint32(data)), data[4:]
+	}
+
+	var length int
+	switch {
+	case code <= 0x7f:
+		return int64(code), data
+	case code >= 0xe0:
+		return int64(int8(code)), data
+	case code&0xe0 == mpFixStr:
+		return string(data[:code&0x1f]), data[code&0x1f:]
+	case code&0xf0 == mpFixArray:
+		return unpackArray(int(code&0x0f), data)
+	case code&0xf0 == mpFixMap:
+		return unpackMap(int(code&0x0f), data)
+	}
+
+	switch code {
+	case mpNil:
+		return nil, data
+	case mpFalse:
+		return false, data
+	case mpTrue:
+		return true, data
+	case mpBin8, mpBin16, mpBin32:
+		length, data = size(1 << (code - mpBin8))
+		return data[:length], data[length:]
+	case mpStr8, mpStr16, mpStr32:
+		length, data = size(1 << (code - mpStr8))
+		return string(data[:length]), data[length:]
+	case mpArray16, mpArray32:
+		length, data = size(2 << (code - mpArray16))
+		return unpackArray(length, data)
+	case mpMap16, mpMap32:
+		length, data = size(2 << (code - mpMap16))
+		return unpackMap(length, data)
+	case mpUint8:
+		return int64(data[0]), data[1:]
+	case mpUint16:
+		return int64(binary.BigEndian.Uint16(data)), data[2:]
+	case mpUint32:
+		return int64(binary.BigEndian.Uint32(data)), data[4:]
+	case mpUint64:
+		return int64(binary.BigEndian.Uint64(data)), data[8:]
+	case mpInt8:
+		return int64(int8(data[0])), data[1:]
+	case mpInt16:
+		return int64(int16(binary.BigEndian.Uint16(data))), data[2:]
+	case mpInt32:
+		return int64(int32(binary.BigEndian.Uint32(data))), data[4:]
+	case mpInt64:
+		return int64(binary.BigEndian.Uint64(data)), data[8:]
+	case mpFloat64:
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:]
+	case mpExt8:
+		Expect(data[:2]).To(Equal([]byte{12, mpTimestamp}))
+		nsec := binary.BigEndian.Uint32(data[2:])
+		sec := binary.BigEndian.Uint64(data[6:])
+		return time.Unix(int64(sec), int64(nsec)).UTC(), data[14:]
+	}
+
+	Fail("unexpected msgpack code")
+	return
+}
+
+func unpackArray(length int, data []byte) (any, []byte) {
+
+	list := []any{}
+	for i := 0; i < length; i++ {
+		var elem any
+		elem, data = unpack(data)
+		list = append(list, elem)
+	}
+
+	return list, data
+}
+
+func unpackMap(length int, data []byte) (any, []byte) {
+
+	fields := map[string]any{}
+	for i := 0; i < length; i++ {
+		var key, val any
+		key, data = unpack(data)
+		val, data = unpack(data)
+		fields[key.(string)] = val
+	}
+
+	return fields, data
+}
+
+var _ = Describe("Msgpack", func() {
+
+	var (
+		evt  *Event
+		data []byte
+	)
+
+	BeforeEach(func() {
+		evt = &Event{
+			Time:  time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+			Level: "info",
+			Msg:   "shipped",
+			Fields: Fields{
+				"small":  7,
+				"neg":    -3,
+				"big":    int64(1) << 40,
+				"wide":   -70000,
+				"ratio":  0.25,
+				"ok":     true,
+				"none":   nil,
+				"long":   strings.Repeat("y", 300),
+				"tags":   []string{"a", "b"},
+				"http":   group{"status": 200},
+				"order":  nested(`{"id":42,"items":[1,2]}`),
+				"digest": []byte{0xde, 0xad},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		data, err = Msgpack{}.Encode(evt)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should encode a map of fields and boilerplate", func() {
+		val, rest := unpack(data)
+		Expect(rest).To(BeEmpty())
+		Expect(val).To(Equal(map[string]any{
+			"ts":     evt.Time,
+			"level":  "info",
+			"msg":    "shipped",
+			"small":  int64(7),
+			"neg":    int64(-3),
+			"big":    int64(1) << 40,
+			"wide":   int64(-70000),
+			"ratio":  0.25,
+			"ok":     true,
+			"none":   nil,
+			"long":   strings.Repeat("y", 300),
+			"tags":   []any{"a", "b"},
+			"http":   map[string]any{"status": int64(200)},
+			"order":  map[string]any{"id": int64(42), "items": []any{int64(1), int64(2)}},
+			"digest": []byte{0xde, 0xad},
+		}))
+	})
+
+	It("should be smaller than json", func() {
+		js, err := JSON{}.Encode(evt)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(data)).To(BeNumerically("<", len(js)))
+	})
+
+	When("logging with the msgpack format", func() {
+		It("should write events back to back, sans newline", func() {
+			buf := &bytes.Buffer{}
+			lgr := (&Config{Format: "msgpack"}).New(buf)
+
+			lgr.Info(context.Background(), "one")
+			lgr.Info(context.Background(), "two", "count", 2)
+
+			first, rest := unpack(buf.Bytes())
+			second, rest := unpack(rest)
+			Expect(rest).To(BeEmpty())
+			Expect(first).To(HaveKeyWithValue("msg", "one"))
+			Expect(second).To(HaveKeyWithValue("count", int64(2)))
+		})
+	})
+})
@@ -0,0 +1,55 @@
+package sabot
+
+import (
+	"encoding/json"
+)
+
+//
+// unexported
+//
+
+// nested is the json of a value of unknown type, embedded as is when Nested, and as a string otherwise.
+type nested []byte
+
+// MarshalJSON marshals as a string, should a nested value be encoded before being resolved, as when Set by a hook.
+func (nst nested) MarshalJSON() ([]byte, error) {
+
+	return json.Marshal(string(nst))
+}
+
+// unnest resolves nested values, and renders bytes as per mode, within groups and hints as well.
+func (fields Fields) unnest(embed bool, mode BytesMode) {
+
+	for key, val := range fields {
+		fields[key] = unnest(val, embed, mode)
+	}
+}
+
+func unnest(val any, embed bool, mode BytesMode) any {
+
+	switch val := val.(type) {
+	case []byte:
+		return mode.render(val)
+	case nested:
+		if embed {
+			return json.RawMessage(val)
+		}
+		return string(val)
+	case group:
+		// copy, as groups may be shared via ctx
+
+		cp := make(Fields, len(val))
+		for key, gv := range val {
+			cp[key] = unnest(gv, embed, mode)
+		}
+		return group(cp)
+	case hinted:
+		val.val = unnest(val.val, embed, mode)
+		return val
+	case whole:
+		val.val = unnest(val.val, embed, mode)
+		return val
+	}
+
+	return val
+}
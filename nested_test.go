@@ -0,0 +1,58 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Nested", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = (&Config{Nested: true, MaxLen: 99}).New(buf)
+		ctx = lgr.WithFields(context.Background(), "config", map[string]any{"version": "1.2.3"})
+	})
+
+	When("logging objects, maps, and slices", func() {
+		It("should embed them as json", func() {
+			lgr.Info(ctx, "nesting", "tags", []string{"a", "b"}, Group("http", "headers", map[string]int{"x": 1}))
+
+			logged := delog(buf)
+			Expect(logged["config"]).To(Equal(map[string]any{"version": "1.2.3"}))
+			Expect(logged["tags"]).To(Equal([]any{"a", "b"}))
+			Expect(logged["http"]).To(Equal(map[string]any{"headers": map[string]any{"x": 1.0}}))
+		})
+
+		It("should embed them when bound", func() {
+			lgr.Bind(ctx).Info("nesting")
+
+			Expect(delog(buf)["config"]).To(Equal(map[string]any{"version": "1.2.3"}))
+		})
+
+		It("should truncate over-long ones as strings", func() {
+			lgr.Info(ctx, "nesting", "tags", []string{string(bytes.Repeat([]byte("x"), 200))})
+
+			Expect(delog(buf)["tags"]).To(ContainSubstring("--truncated--"))
+		})
+	})
+
+	When("not nested", func() {
+		It("should log them as strings", func() {
+			lgr.Nested = false
+			lgr.Info(ctx, "nesting", "tags", []string{"a", "b"})
+
+			logged := delog(buf)
+			Expect(logged["config"]).To(Equal(`{"version":"1.2.3"}`))
+			Expect(logged["tags"]).To(Equal(`["a","b"]`))
+		})
+	})
+})
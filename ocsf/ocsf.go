@@ -0,0 +1,142 @@
+// Package ocsf produces Open Cybersecurity Schema Framework shaped security events.
+//
+// Attributes are logged with dotted keys following OCSF attribute paths, "actor.user.name" for example.
+package ocsf
+
+import (
+	"context"
+	"time"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const schemaVersion string = "1.1.0"
+
+// Level is the level security events are logged at, just above info.
+const Level = sabot.Info + 2
+
+func init() {
+	err := sabot.RegisterLevel("security", Level)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Class is an OCSF event class.
+type Class struct {
+	UID         int
+	CategoryUID int
+}
+
+// Classes commonly logged by applications.
+var (
+	FileActivity     = Class{UID: 1001, CategoryUID: 1}
+	DetectionFinding = Class{UID: 2004, CategoryUID: 2}
+	AccountChange    = Class{UID: 3001, CategoryUID: 3}
+	Authentication   = Class{UID: 3002, CategoryUID: 3}
+	NetworkActivity  = Class{UID: 4001, CategoryUID: 4}
+	APIActivity      = Class{UID: 6003, CategoryUID: 6}
+)
+
+// Severity is an OCSF severity_id.
+type Severity int
+
+// Severities.
+const (
+	Unknown Severity = iota
+	Informational
+	Low
+	Medium
+	High
+	Critical
+	Fatal
+)
+
+// Status is an OCSF status_id.
+type Status int
+
+// Statuses.
+const (
+	StatusUnknown Status = iota
+	Success
+	Failure
+)
+
+// Event is a simplified OCSF security event.
+type Event struct {
+	// Class is the event class, Authentication for example.
+	Class Class
+	// Activity is the class specific activity_id, 1 is logon for Authentication for example.
+	Activity int
+	// Severity is the severity of the event.
+	Severity Severity
+	// Status is the outcome of the activity.
+	Status Status
+	// Message is a description of the event.
+	Message string
+	// Actor is the name of the user performing the activity.
+	Actor string
+	// SrcIP is the ip address the activity originated from.
+	SrcIP string
+	// DstIP is the ip address the activity was directed to.
+	DstIP string
+	// Product is the name of the product reporting the event.
+	Product string
+	// Time is when the event occurred, defaulting to now.
+	Time time.Time
+	// Unmapped are additional attributes without an OCSF home.
+	Unmapped map[string]any
+}
+
+// Kv returns key-value pairs for the event, as for logging.
+func (evt Event) Kv() (kv []any) {
+
+	ts := evt.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	kv = []any{
+		"class_uid", evt.Class.UID,
+		"category_uid", evt.Class.CategoryUID,
+		"activity_id", evt.Activity,
+		"type_uid", evt.Class.UID*100 + evt.Activity,
+		"severity_id", int(evt.Severity),
+		"status_id", int(evt.Status),
+		"time", ts.UnixMilli(),
+		"metadata.version", schemaVersion,
+	}
+
+	optional := []struct {
+		key string
+		val string
+	}{
+		{"message", evt.Message},
+		{"actor.user.name", evt.Actor},
+		{"src_endpoint.ip", evt.SrcIP},
+		{"dst_endpoint.ip", evt.DstIP},
+		{"metadata.product.name", evt.Product},
+	}
+	for _, opt := range optional {
+		if opt.val != "" {
+			kv = append(kv, opt.key, opt.val)
+		}
+	}
+
+	for key, val := range evt.Unmapped {
+		kv = append(kv, "unmapped."+key, val)
+	}
+
+	return
+}
+
+// Log logs a security event at the security Level, which can be routed to a security sink.
+func Log(ctx context.Context, lgr *sabot.Sabot, evt Event) {
+
+	msg := evt.Message
+	if msg == "" {
+		msg = "security event"
+	}
+
+	lgr.Log(ctx, Level, msg, evt.Kv()...)
+}
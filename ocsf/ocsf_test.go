@@ -0,0 +1,71 @@
+package ocsf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestOcsf(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ocsf Suite")
+}
+
+var _ = Describe("Log", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *sabot.Sabot
+		evt Event
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &sabot.Sabot{Writer: buf}
+		evt = Event{
+			Class:    Authentication,
+			Activity: 1,
+			Severity: Medium,
+			Status:   Failure,
+			Message:  "logon failed",
+			Actor:    "mallory",
+			SrcIP:    "10.0.0.7",
+			Time:     time.UnixMilli(1700947254758),
+			Unmapped: map[string]any{"attempts": 3},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Log(context.Background(), lgr, evt)
+	})
+
+	It("should log an ocsf shaped event at the security level", func() {
+		logged := sabot.Fields{}
+		Expect(json.Unmarshal(buf.Bytes(), &logged)).To(Succeed())
+		delete(logged, "ts")
+
+		Expect(logged).To(Equal(sabot.Fields{
+			"level":             "security",
+			"msg":               "logon failed",
+			"message":           "logon failed",
+			"class_uid":         float64(3002),
+			"category_uid":      float64(3),
+			"activity_id":       float64(1),
+			"type_uid":          float64(300201),
+			"severity_id":       float64(3),
+			"status_id":         float64(2),
+			"time":              float64(1700947254758),
+			"metadata.version":  "1.1.0",
+			"actor.user.name":   "mallory",
+			"src_endpoint.ip":   "10.0.0.7",
+			"unmapped.attempts": float64(3),
+		}))
+	})
+})
@@ -0,0 +1,86 @@
+package sabot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	offloadedNotice string = "--offloaded--"
+	refSuffix       string = "_ref"
+)
+
+// BlobStore keeps payloads too large for the main stream, such as a local dir or object storage.
+type BlobStore interface {
+	// Put stores data under name, returning a url at which it can be found.
+	Put(ctx context.Context, name string, data []byte) (url string, err error)
+}
+
+// Offload moves over-long field values to a BlobStore, and is suitable for use as a Hook.
+//
+// Offloaded values are replaced with a notice and a companion <key>_ref field carries the url.
+// Blobs are named by content hash, so that repeated payloads are stored once.
+type Offload struct {
+	// Threshold is the length past which values are offloaded, zero for never.
+	Threshold int
+	// Store is where offloaded values are put.
+	Store BlobStore
+}
+
+// Apply offloads an event's large fields.
+func (ofl *Offload) Apply(ctx context.Context, evt *Event) bool {
+
+	if ofl.Threshold < 1 {
+		return true
+	}
+
+	// find candidates first, as refs and errors are added along the way
+
+	large := map[string][]byte{}
+	for key, val := range evt.Fields {
+
+		var data []byte
+		switch val := val.(type) {
+		case string:
+			data = []byte(val)
+		case []byte:
+			data = val
+		case json.RawMessage:
+			// as from RawJSON, a json.Marshaler, or a nested value
+			data = val
+		case whole:
+			str, ok := val.val.(string)
+			if !ok {
+				continue
+			}
+			data = []byte(str)
+		default:
+			continue
+		}
+		if len(data) > ofl.Threshold {
+			large[key] = data
+		}
+	}
+
+	for key, data := range large {
+
+		sum := sha256.Sum256(data)
+		name := hex.EncodeToString(sum[:])
+
+		// leave value for truncation when store fails
+
+		url, err := ofl.Store.Put(ctx, name, data)
+		if err != nil {
+			evt.Fields[logErrorKey] = fmt.Sprintf("failed to offload %s: %+v", key, err)
+			continue
+		}
+
+		evt.Fields[key] = offloadedNotice
+		evt.Fields[key+refSuffix] = url
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type memStore struct {
+	blobs map[string][]byte
+	fail  bool
+}
+
+func (ms *memStore) Put(ctx context.Context, name string, data []byte) (string, error) {
+
+	if ms.fail {
+		return "", errors.Errorf("store is down")
+	}
+
+	ms.blobs[name] = data
+	return fmt.Sprintf("mem://%s", name), nil
+}
+
+var _ = Describe("Offload", func() {
+
+	var (
+		buf   *bytes.Buffer
+		lgr   *Sabot
+		store *memStore
+		big   string
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		store = &memStore{blobs: map[string][]byte{}}
+		ofl := &Offload{Threshold: 20, Store: store}
+		lgr = &Sabot{Writer: buf, MaxLen: 99, Hooks: []Hook{ofl.Apply}}
+		big = strings.Repeat("b", 199)
+	})
+
+	When("a field exceeds the threshold", func() {
+		It("should offload it and log a ref", func() {
+			lgr.Info(context.Background(), "offloading", "body", big, "small", "tiny")
+
+			fields := delog(buf)
+			Expect(fields["body"]).To(Equal("--offloaded--"))
+			Expect(fields["small"]).To(Equal("tiny"))
+			Expect(fields["body_ref"]).To(HavePrefix("mem://"))
+
+			Expect(store.blobs).To(HaveLen(1))
+			for _, data := range store.blobs {
+				Expect(string(data)).To(Equal(big))
+			}
+		})
+	})
+
+	When("a raw json field exceeds the threshold", func() {
+		It("should offload it as is", func() {
+			raw := `{"body":"` + big + `"}`
+			lgr.Info(context.Background(), "offloading", RawJSON("doc", []byte(raw)))
+
+			fields := delog(buf)
+			Expect(fields["doc"]).To(Equal("--offloaded--"))
+			Expect(fields["doc_ref"]).To(HavePrefix("mem://"))
+			for _, data := range store.blobs {
+				Expect(string(data)).To(Equal(raw))
+			}
+		})
+	})
+
+	When("the store fails", func() {
+		It("should keep the value for truncation and note the error", func() {
+			store.fail = true
+			lgr.Info(context.Background(), "offloading", "body", big)
+
+			fields := delog(buf)
+			Expect(fields["body"]).To(HaveSuffix("--truncated--"))
+			Expect(fields["logerror"]).To(HavePrefix("failed to offload body: store is down"))
+		})
+	})
+})
@@ -0,0 +1,287 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// OTLP encodes events as protobuf OpenTelemetry LogRecords, for network sinks where payload size
+// and parse cost count, as with very high-volume services.
+//
+// Msg becomes the body, level the severity, and fields attributes, with an error field becoming
+// exception.message and exception.stacktrace, and error_type exception.type, as per semantic conventions.
+// Hex ids found at TraceKey and SpanKey become the record's trace and span ids.
+// Being binary, OTLP is for sinks handed events, such as sink.Kafka, rather than for line oriented output.
+type OTLP struct {
+	// TraceKey is the field of a hex trace id, trace_id when empty.
+	TraceKey string
+	// SpanKey is the field of a hex span id, span_id when empty.
+	SpanKey string
+}
+
+// Encode encodes an event.
+func (enc OTLP) Encode(evt *Event) (data []byte, err error) {
+
+	fields := make(Fields, len(evt.Fields))
+	for key, val := range evt.Fields {
+		fields[key] = val
+	}
+
+	trace, ok := fields["error"].(string)
+	if ok {
+		delete(fields, "error")
+		fields["exception.message"] = firstLine(trace)
+		if trace != fields["exception.message"] {
+			fields["exception.stacktrace"] = trace
+		}
+	}
+
+	typ, ok := fields[errorTypeKey].(string)
+	if ok {
+		delete(fields, errorTypeKey)
+		fields["exception.type"] = typ
+	}
+
+	if !evt.Time.IsZero() {
+		data = protoTag(data, otlpTime, wireFixed64)
+		data = binary.LittleEndian.AppendUint64(data, uint64(evt.Time.UnixNano()))
+	}
+
+	data = protoTag(data, otlpSeverity, wireVarint)
+	data = binary.AppendUvarint(data, uint64(otlpSeverityOf(levelOf(evt.Level))))
+	data = protoBytes(data, otlpSeverityText, []byte(evt.Level))
+	data = protoBytes(data, otlpBody, anyValue(evt.Msg))
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// ids are taken from among fields only when valid, and left as attributes otherwise
+
+	traceId, traceOk := otlpId(fields, enc.traceKey(), otlpTraceLen)
+	spanId, spanOk := otlpId(fields, enc.spanKey(), otlpSpanLen)
+
+	for _, key := range keys {
+		if (traceOk && key == enc.traceKey()) || (spanOk && key == enc.spanKey()) {
+			continue
+		}
+		data = protoBytes(data, otlpAttributes, keyValue(key, fields[key]))
+	}
+
+	if traceOk {
+		data = protoBytes(data, otlpTraceId, traceId)
+	}
+	if spanOk {
+		data = protoBytes(data, otlpSpanId, spanId)
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+// field numbers of opentelemetry.proto.logs.v1.LogRecord and opentelemetry.proto.common.v1 messages.
+const (
+	otlpTime         = 1
+	otlpSeverity     = 2
+	otlpSeverityText = 3
+	otlpBody         = 5
+	otlpAttributes   = 6
+	otlpTraceId      = 9
+	otlpSpanId       = 10
+
+	anyString = 1
+	anyBool   = 2
+	anyInt    = 3
+	anyDouble = 4
+	anyArray  = 5
+	anyKvlist = 6
+	anyBytes  = 7
+
+	kvKey   = 1
+	kvValue = 2
+
+	// listValues is the field of values in both ArrayValue and KeyValueList.
+	listValues = 1
+
+	otlpTraceLen = 16
+	otlpSpanLen  = 8
+)
+
+// protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+func (enc OTLP) traceKey() string {
+
+	if enc.TraceKey == "" {
+		return "trace_id"
+	}
+	return enc.TraceKey
+}
+
+func (enc OTLP) spanKey() string {
+
+	if enc.SpanKey == "" {
+		return "span_id"
+	}
+	return enc.SpanKey
+}
+
+// otlpSeverityOf maps a level to a severity number, info at 9 and built-ins four apart as in otel,
+// custom levels falling between.
+func otlpSeverityOf(lvl Level) int {
+
+	return min(max(int(lvl)+9, 1), 24)
+}
+
+func otlpId(fields Fields, key string, size int) (id []byte, ok bool) {
+
+	str, ok := fields[key].(string)
+	if !ok {
+		return
+	}
+
+	id, err := hex.DecodeString(str)
+	if err != nil || len(id) != size {
+		return nil, false
+	}
+
+	return id, true
+}
+
+func protoTag(data []byte, num, wire int) []byte {
+
+	return binary.AppendUvarint(data, uint64(num<<3|wire))
+}
+
+func protoBytes(data []byte, num int, val []byte) []byte {
+
+	data = protoTag(data, num, wireBytes)
+	data = binary.AppendUvarint(data, uint64(len(val)))
+	return append(data, val...)
+}
+
+// keyValue encodes a KeyValue message.
+func keyValue(key string, val any) (data []byte) {
+
+	data = protoBytes(data, kvKey, []byte(key))
+	return protoBytes(data, kvValue, anyValue(val))
+}
+
+// anyValue encodes an AnyValue message, objects becoming kvlists and slices arrays.
+func anyValue(val any) (data []byte) {
+
+	switch val := val.(type) {
+	case nil:
+		return
+	case string:
+		return protoBytes(data, anyString, []byte(val))
+	case []byte:
+		return protoBytes(data, anyBytes, val)
+	case time.Time:
+		return protoBytes(data, anyString, []byte(val.Format(time.RFC3339Nano)))
+	case json.Number:
+		num, err := val.Int64()
+		if err == nil {
+			return anyValue(num)
+		}
+		flt, _ := val.Float64()
+		return anyValue(flt)
+	case json.RawMessage:
+		return anyJson(val)
+	case nested:
+		return anyJson(val)
+	case group:
+		return anyKv(Fields(val))
+	case Fields:
+		return anyKv(val)
+	case map[string]any:
+		return anyKv(val)
+	case []string:
+		var list []byte
+		for _, str := range val {
+			list = protoBytes(list, listValues, anyValue(str))
+		}
+		return protoBytes(data, anyArray, list)
+	case []any:
+		var list []byte
+		for _, elem := range val {
+			list = protoBytes(list, listValues, anyValue(elem))
+		}
+		return protoBytes(data, anyArray, list)
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.String:
+		return protoBytes(data, anyString, []byte(rv.String()))
+	case reflect.Bool:
+		data = protoTag(data, anyBool, wireVarint)
+		if rv.Bool() {
+			return append(data, 1)
+		}
+		return append(data, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		data = protoTag(data, anyInt, wireVarint)
+		return binary.AppendUvarint(data, uint64(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		data = protoTag(data, anyInt, wireVarint)
+		return binary.AppendUvarint(data, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		data = protoTag(data, anyDouble, wireFixed64)
+		return binary.LittleEndian.AppendUint64(data, math.Float64bits(rv.Float()))
+	}
+
+	// as json for anything else, as with kv
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return anyValue(fmt.Sprintf("%v", val))
+	}
+	return anyJson(raw)
+}
+
+func anyJson(raw []byte) []byte {
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var val any
+	err := decoder.Decode(&val)
+	if err != nil {
+		return anyValue(string(raw))
+	}
+
+	return anyValue(val)
+}
+
+func anyKv(fields map[string]any) (data []byte) {
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var list []byte
+	for _, key := range keys {
+		list = protoBytes(list, listValues, keyValue(key, fields[key]))
+	}
+
+	return protoBytes(data, anyKvlist, list)
+}
@@ -0,0 +1,175 @@
+package sabot
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// unproto decodes a protobuf message into values by field number, enough for checking OTLP.
+func unproto(data []byte) map[int][]any {
+
+	msg := map[int][]any{}
+	for len(data) > 0 {
+		tag, size := binary.Uvarint(data)
+		Expect(size).To(BeNumerically(">", 0))
+		data = data[size:]
+
+		num := int(tag >> 3)
+		switch tag & 7 {
+		case wireVarint:
+			val, size := binary.Uvarint(data)
+			msg[num] = append(msg[num], val)
+			data = data[size:]
+		case wireFixed64:
+			msg[num] = append(msg[num], binary.LittleEndian.Uint64(data))
+			data = data[8:]
+		case wireBytes:
+			length, size := binary.Uvarint(data)
+			data = data[size:]
+			msg[num] = append(msg[num], data[:length])
+			data = data[length:]
+		default:
+			Fail("unexpected wire type")
+		}
+	}
+
+	return msg
+}
+
+// unany decodes an AnyValue message.
+func unany(data []byte) any {
+
+	msg := unproto(data)
+	switch {
+	case msg[anyString] != nil:
+		return string(msg[anyString][0].([]byte))
+	case msg[anyBool] != nil:
+		return msg[anyBool][0].(uint64) == 1
+	case msg[anyInt] != nil:
+		return int64(msg[anyInt][0].(uint64))
+	case msg[anyDouble] != nil:
+		return math.Float64frombits(msg[anyDouble][0].(uint64))
+	case msg[anyBytes] != nil:
+		return msg[anyBytes][0]
+	case msg[anyArray] != nil:
+		list := []any{}
+		for _, elem := range unproto(msg[anyArray][0].([]byte))[listValues] {
+			list = append(list, unany(elem.([]byte)))
+		}
+		return list
+	case msg[anyKvlist] != nil:
+		return unkv(unproto(msg[anyKvlist][0].([]byte))[listValues])
+	}
+
+	return nil
+}
+
+// unkv decodes KeyValue messages.
+func unkv(kvs []any) map[string]any {
+
+	fields := map[string]any{}
+	for _, kv := range kvs {
+		msg := unproto(kv.([]byte))
+		fields[string(msg[kvKey][0].([]byte))] = unany(msg[kvValue][0].([]byte))
+	}
+
+	return fields
+}
+
+var _ = Describe("OTLP", func() {
+
+	var (
+		evt  *Event
+		enc  OTLP
+		data []byte
+		rec  map[int][]any
+	)
+
+	BeforeEach(func() {
+		enc = OTLP{}
+		evt = &Event{
+			Time:  time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+			Level: "warn",
+			Msg:   "slow query",
+			Fields: Fields{
+				"elapsed_ms": 1234,
+				"ratio":      0.5,
+				"cached":     false,
+				"tags":       []string{"db", "read"},
+				"http":       group{"status": 200, "route": "/users"},
+				"query":      nested(`{"table":"users","limit":10}`),
+				"body":       []byte{0x01, 0x02},
+				"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+				"span_id":    "not hex",
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		data, err = enc.Encode(evt)
+		Expect(err).ToNot(HaveOccurred())
+		rec = unproto(data)
+	})
+
+	It("should encode a log record", func() {
+		Expect(rec[otlpTime]).To(Equal([]any{uint64(evt.Time.UnixNano())}))
+		Expect(rec[otlpSeverity]).To(Equal([]any{uint64(13)}))
+		Expect(rec[otlpSeverityText]).To(Equal([]any{[]byte("warn")}))
+		Expect(unany(rec[otlpBody][0].([]byte))).To(Equal("slow query"))
+		Expect(rec[otlpTraceId]).To(Equal([]any{[]byte{
+			0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36,
+		}}))
+		Expect(rec[otlpSpanId]).To(BeNil())
+
+		Expect(unkv(rec[otlpAttributes])).To(Equal(map[string]any{
+			"elapsed_ms": int64(1234),
+			"ratio":      0.5,
+			"cached":     false,
+			"tags":       []any{"db", "read"},
+			"http":       map[string]any{"status": int64(200), "route": "/users"},
+			"query":      map[string]any{"table": "users", "limit": int64(10)},
+			"body":       []byte{0x01, 0x02},
+			"span_id":    "not hex",
+		}))
+	})
+
+	It("should be smaller than json", func() {
+		js, err := JSON{}.Encode(evt)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(data)).To(BeNumerically("<", len(js)))
+	})
+
+	When("the event has an error", func() {
+		BeforeEach(func() {
+			evt.Level = "error"
+			evt.Fields = Fields{"error": "oops\nmain.main()", "error_type": "*errors.fundamental"}
+		})
+
+		It("should follow exception conventions", func() {
+			Expect(rec[otlpSeverity]).To(Equal([]any{uint64(17)}))
+			Expect(unkv(rec[otlpAttributes])).To(Equal(map[string]any{
+				"exception.message":    "oops",
+				"exception.stacktrace": "oops\nmain.main()",
+				"exception.type":       "*errors.fundamental",
+			}))
+		})
+	})
+
+	DescribeTable("mapping severity",
+		func(lvl Level, expected int) {
+			Expect(otlpSeverityOf(lvl)).To(Equal(expected))
+		},
+		Entry("trace", Trace, 1),
+		Entry("debug", Debug, 5),
+		Entry("info", Info, 9),
+		Entry("custom", Info+1, 10),
+		Entry("error", Error, 17),
+		Entry("fatal", Fatal, 21),
+		Entry("audit", Audit, 24),
+	)
+})
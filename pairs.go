@@ -0,0 +1,100 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Pairs encodes events as a json array of {"k": key, "v": value} objects,
+// for consumers that need exact field order preserved.
+//
+// Boilerplate comes first, then fields in the order of Keys.
+type Pairs struct{}
+
+// Pair is a single field as encoded by Pairs.
+type Pair struct {
+	Key string `json:"k"`
+	Val any    `json:"v"`
+}
+
+// Encode encodes an event.
+func (enc Pairs) Encode(evt *Event) (data []byte, err error) {
+
+	pairs := make([]Pair, 0, len(evt.Fields)+3)
+	pairs = append(pairs,
+		Pair{Key: "ts", Val: evt.ts()},
+		Pair{Key: "level", Val: evt.Level},
+		Pair{Key: "msg", Val: evt.Msg},
+	)
+
+	for _, key := range evt.Keys() {
+		pairs = append(pairs, Pair{Key: key, Val: evt.Fields[key]})
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	err = encoder.Encode(pairs)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to marshal pairs event")
+		return
+	}
+
+	data = bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return
+}
+
+// Keys returns the event's field keys, in the order given as kv at the call site,
+// followed by others, such as from ctx, computed, and hooks, sorted.
+func (evt *Event) Keys() (keys []string) {
+
+	keys = make([]string, 0, len(evt.Fields))
+	seen := make(map[string]bool, len(evt.Fields))
+
+	for _, key := range evt.order {
+		_, ok := evt.Fields[key]
+		if ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+
+	rest := make([]string, 0, len(evt.Fields)-len(keys))
+	for key := range evt.Fields {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
+
+//
+// unexported
+//
+
+func kvKeys(kv []any) (keys []string) {
+
+	for i := 0; i < len(kv); {
+
+		field, ok := kv[i].(Field)
+		if ok {
+			keys = append(keys, field.Key)
+			i++
+			continue
+		}
+
+		key, ok := kv[i].(string)
+		if ok {
+			keys = append(keys, key)
+		}
+		i += 2
+	}
+
+	return
+}
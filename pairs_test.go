@@ -0,0 +1,43 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pairs", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = (&Config{Format: "pairs"}).New(buf)
+		ctx = lgr.WithFields(context.Background(), "run_id", "123", "app", "sabot")
+	})
+
+	When("logging with kv in a particular order", func() {
+		It("should keep that order, ctx fields following sorted", func() {
+			lgr.Info(ctx, "ordered", "zulu", 1, NoTruncate("alpha", "a"), "mike", "<m>")
+
+			Expect(buf.String()).To(MatchRegexp(
+				`^\[{"k":"ts","v":"[^"]+"},{"k":"level","v":"info"},{"k":"msg","v":"ordered"},` +
+					`{"k":"zulu","v":1},{"k":"alpha","v":"a"},{"k":"mike","v":"<m>"},` +
+					`{"k":"app","v":"sabot"},{"k":"run_id","v":"123"}\]\n$`,
+			))
+		})
+	})
+
+	When("a decoded event has no call site order", func() {
+		It("should sort keys", func() {
+			evt := &Event{Fields: Fields{"b": 2, "a": 1}}
+			Expect(evt.Keys()).To(Equal([]string{"a", "b"}))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package sabot
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+)
+
+// Do calls fn with pprof labels set from ctx fields named in LabelKeys.
+//
+// CPU profiles taken meanwhile can then be sliced by the same identifiers found in the logs,
+// say with "go tool pprof -tagfocus run_id=123123123".
+func (sabot *Sabot) Do(ctx context.Context, fn func(ctx context.Context)) {
+
+	labels := sabot.labels(ctx)
+	if len(labels) == 0 {
+		fn(ctx)
+		return
+	}
+
+	pprof.Do(ctx, pprof.Labels(labels...), fn)
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) labels(ctx context.Context) (labels []string) {
+
+	fields := sabot.GetFields(ctx)
+	for _, key := range sabot.LabelKeys {
+
+		val, ok := fields[key]
+		if ok {
+			labels = append(labels, key, fmt.Sprintf("%v", val))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,51 @@
+package sabot
+
+import (
+	"context"
+	"runtime/pprof"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Do", func() {
+
+	var (
+		ctx    context.Context
+		lgr    *Sabot
+		labels map[string]string
+	)
+
+	BeforeEach(func() {
+		lgr = &Sabot{
+			LabelKeys: []string{"run_id", "component"},
+		}
+		ctx = lgr.WithFields(context.Background(), "run_id", "123", "worker_id", 7)
+		labels = map[string]string{}
+	})
+
+	JustBeforeEach(func() {
+		lgr.Do(ctx, func(ctx context.Context) {
+			pprof.ForLabels(ctx, func(key, value string) bool {
+				labels[key] = value
+				return true
+			})
+		})
+	})
+
+	When("ctx has a label key", func() {
+		It("should set the label", func() {
+			Expect(labels).To(Equal(map[string]string{"run_id": "123"}))
+		})
+	})
+
+	When("ctx has no label keys", func() {
+		BeforeEach(func() {
+			ctx = context.Background()
+		})
+
+		It("should set no labels", func() {
+			Expect(labels).To(BeEmpty())
+		})
+	})
+})
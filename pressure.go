@@ -0,0 +1,79 @@
+package sabot
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Pressurer is implemented by writers that buffer, reporting how full they are from zero to one.
+type Pressurer interface {
+	Pressure() float64
+}
+
+// PressureFunc is called when pressure crosses a threshold, rising or falling.
+type PressureFunc func(pressure float64, rising bool)
+
+// PressureAlarm calls OnCross as pressure crosses Thresholds, when set on Sabot,
+// so that request handlers can shed their own load or log less before events are dropped.
+type PressureAlarm struct {
+	// Thresholds are the pressures crossings of which are signaled, such as 0.5 and 0.9.
+	Thresholds []float64
+	// OnCross is called once per crossing, after the write that caused it.
+	OnCross PressureFunc
+	band    atomic.Int32
+}
+
+// Pressure returns how full the fullest of Writer and Routes is, zero when none buffer.
+func (sabot *Sabot) Pressure() (pressure float64) {
+
+	pressure = pressureOf(sabot.Writer)
+	for _, writer := range sabot.Routes {
+		pressure = max(pressure, pressureOf(writer))
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) alarm() {
+
+	if sabot.Alarm == nil {
+		return
+	}
+
+	sabot.Alarm.check(sabot.Pressure())
+}
+
+func (alarm *PressureAlarm) check(pressure float64) {
+
+	if alarm.OnCross == nil {
+		return
+	}
+
+	var band int32
+	for _, threshold := range alarm.Thresholds {
+		if pressure >= threshold {
+			band++
+		}
+	}
+
+	// swap so that concurrent writers signal a crossing once
+
+	prev := alarm.band.Swap(band)
+	if band != prev {
+		alarm.OnCross(pressure, band > prev)
+	}
+}
+
+func pressureOf(writer io.Writer) float64 {
+
+	pr, ok := writer.(Pressurer)
+	if !ok {
+		return 0
+	}
+
+	return pr.Pressure()
+}
@@ -0,0 +1,72 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type pressedWriter struct {
+	bytes.Buffer
+	pressure float64
+}
+
+func (pw *pressedWriter) Pressure() float64 {
+
+	return pw.pressure
+}
+
+var _ = Describe("Pressure", func() {
+
+	var (
+		pw      *pressedWriter
+		lgr     *Sabot
+		ctx     context.Context
+		crossed []bool
+	)
+
+	BeforeEach(func() {
+		pw = &pressedWriter{}
+		crossed = nil
+		lgr = &Sabot{
+			Writer: pw,
+			Alarm: &PressureAlarm{
+				Thresholds: []float64{0.5, 0.9},
+				OnCross:    func(pressure float64, rising bool) { crossed = append(crossed, rising) },
+			},
+		}
+		ctx = context.Background()
+	})
+
+	When("the writer buffers", func() {
+		It("should report its fullness", func() {
+			pw.pressure = 0.25
+			Expect(lgr.Pressure()).To(Equal(0.25))
+		})
+
+		It("should report the fullest route", func() {
+			lgr.Routes = map[Level]io.Writer{Error: &pressedWriter{pressure: 0.75}}
+			Expect(lgr.Pressure()).To(Equal(0.75))
+		})
+	})
+
+	When("the writer does not buffer", func() {
+		It("should report none", func() {
+			lgr.Writer = &bytes.Buffer{}
+			Expect(lgr.Pressure()).To(BeZero())
+		})
+	})
+
+	When("pressure crosses thresholds", func() {
+		It("should signal once per crossing", func() {
+			for _, pressure := range []float64{0.1, 0.6, 0.7, 0.95, 0.3} {
+				pw.pressure = pressure
+				lgr.Info(ctx, "pressing")
+			}
+			Expect(crossed).To(Equal([]bool{true, true, false}))
+		})
+	})
+})
@@ -0,0 +1,64 @@
+package sabot
+
+import (
+	"context"
+	"slices"
+)
+
+// WithPurposes adds purposes, such as those consented to, to those of ctx.
+//
+// Purposes gate fields configured in a PurposeGate, supporting purpose limitation
+// in the manner of GDPR, where precise location is logged only for fraud, for example.
+func (sabot *Sabot) WithPurposes(ctx context.Context, purposes ...string) context.Context {
+
+	existing, _ := ctx.Value(purposeKey{}).([]string)
+
+	merged := slices.Clone(existing)
+	for _, purpose := range purposes {
+		if !slices.Contains(merged, purpose) {
+			merged = append(merged, purpose)
+		}
+	}
+
+	return context.WithValue(ctx, purposeKey{}, merged)
+}
+
+// PurposeGate deletes gated fields unless logged with a ctx having a purpose that permits them.
+type PurposeGate struct {
+	// Fields are the purposes permitting each gated field key, any one of which suffices.
+	Fields map[string][]string `json:"fields" desc:"purposes permitting each gated field key, any one sufficing"`
+}
+
+// Empty is true when there is nothing gated.
+func (gate *PurposeGate) Empty() bool {
+
+	return len(gate.Fields) == 0
+}
+
+// Apply deletes gated fields lacking a permitting purpose in ctx, and is suitable for use as a Hook.
+func (gate *PurposeGate) Apply(ctx context.Context, evt *Event) bool {
+
+	purposes, _ := ctx.Value(purposeKey{}).([]string)
+
+	for key, permitting := range gate.Fields {
+		_, ok := evt.Fields[key]
+		if !ok {
+			continue
+		}
+
+		permitted := slices.ContainsFunc(permitting, func(purpose string) bool {
+			return slices.Contains(purposes, purpose)
+		})
+		if !permitted {
+			delete(evt.Fields, key)
+		}
+	}
+
+	return true
+}
+
+//
+// unexported
+//
+
+type purposeKey struct{}
@@ -0,0 +1,59 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurposeGate", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		cfg := &Config{
+			Purposes: PurposeGate{
+				Fields: map[string][]string{
+					"location": {"fraud", "support"},
+					"device":   {"analytics"},
+				},
+			},
+		}
+		lgr = cfg.New(buf)
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		lgr.Info(ctx, "checking out", "location", "51.5007,-0.1246", "device", "abc123", "amount", 42)
+	})
+
+	When("ctx has no purposes", func() {
+		It("should delete gated fields", func() {
+			logged := delog(buf)
+			Expect(logged).ToNot(HaveKey("location"))
+			Expect(logged).ToNot(HaveKey("device"))
+			Expect(logged).To(HaveKeyWithValue("amount", 42.0))
+		})
+	})
+
+	When("ctx has a permitting purpose", func() {
+		BeforeEach(func() {
+			ctx = lgr.WithPurposes(ctx, "marketing")
+			ctx = lgr.WithPurposes(ctx, "fraud", "marketing")
+		})
+
+		It("should keep the fields it permits", func() {
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("location", "51.5007,-0.1246"))
+			Expect(logged).ToNot(HaveKey("device"))
+			Expect(ctx.Value(purposeKey{})).To(Equal([]string{"marketing", "fraud"}))
+		})
+	})
+})
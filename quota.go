@@ -0,0 +1,175 @@
+package sabot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	quotaNotice   string        = "tenant log quota exceeded, suppressing further events below error"
+	defaultWindow time.Duration = time.Minute
+)
+
+// TenantQuota caps the events and bytes logged per tenant in each window, when set on Sabot,
+// so that one noisy tenant of a multi-tenant service can't drown out the rest.
+//
+// The tenant is the value of the Key field, from kv or ctx, and events without it are not limited.
+// Once a tenant's quota is reached, further events below error are suppressed until its window ends,
+// with a single notice logged in their stead.
+// Bytes are counted as encoded, and so are not counted for an EventWriter.
+type TenantQuota struct {
+	// Key is the field identifying the tenant, such as tenant_id.
+	Key string
+	// Events is the number of events allowed per tenant per window, zero for no limit.
+	Events int64
+	// Bytes is the number of bytes allowed per tenant per window, zero for no limit.
+	Bytes int64
+	// Window is how long a tenant's quota lasts before renewing, a minute when zero.
+	Window time.Duration
+
+	tenants map[string]*tenantUsage
+	pruned  time.Time
+	mu      sync.Mutex
+}
+
+// Suppressed returns the count of events suppressed by tenant in their current windows.
+func (quota *TenantQuota) Suppressed() (suppressed map[string]int64) {
+
+	now := time.Now()
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	// skip windows ended but not yet pruned
+
+	suppressed = map[string]int64{}
+	for tenant, usage := range quota.tenants {
+		if usage.suppressed > 0 && now.Sub(usage.start) < quota.window() {
+			suppressed[tenant] = usage.suppressed
+		}
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+type tenantUsage struct {
+	start      time.Time
+	events     int64
+	bytes      int64
+	suppressed int64
+}
+
+// admit checks an event against the quota of its tenant, if any, logging a notice on first suppression.
+func (sabot *Sabot) admit(ctx context.Context, ctxFields Fields, evt *Event) (tenant string, ok bool) {
+
+	quota := sabot.Quota
+	if quota == nil || quota.Key == "" || evt.Msg == quotaNotice {
+		return "", true
+	}
+
+	// ctx fields take precedence, as they do when the event is built
+
+	val, found := ctxFields[quota.Key]
+	if !found {
+		val, found = evt.Fields[quota.Key]
+	}
+	if !found {
+		return "", true
+	}
+
+	hnt, isHinted := val.(hinted)
+	if isHinted {
+		val = hnt.val
+	}
+	tenant = stringify(val)
+
+	ok, notice := quota.take(tenant, levelOf(evt.Level) >= Error)
+	if notice {
+		sabot.emit(ctx, ctxFields, "info", quotaNotice, nil, []any{
+			quota.Key, tenant,
+			"quota_events", quota.Events,
+			"quota_bytes", quota.Bytes,
+			"quota_window", quota.window().String(),
+		})
+	}
+	if !ok {
+		sabot.Stats.drop()
+	}
+
+	return
+}
+
+func (quota *TenantQuota) take(tenant string, exempt bool) (ok, notice bool) {
+
+	now := time.Now()
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	quota.prune(now)
+
+	usage, found := quota.tenants[tenant]
+	if !found || now.Sub(usage.start) >= quota.window() {
+		usage = &tenantUsage{start: now}
+		quota.tenants[tenant] = usage
+	}
+	usage.events++
+
+	over := (quota.Events > 0 && usage.events > quota.Events) || (quota.Bytes > 0 && usage.bytes >= quota.Bytes)
+	if !over || exempt {
+		return true, false
+	}
+
+	usage.suppressed++
+	return false, usage.suppressed == 1
+}
+
+// used counts bytes written for a tenant.
+func (quota *TenantQuota) used(tenant string, size int) {
+
+	if quota == nil || tenant == "" {
+		return
+	}
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	usage, found := quota.tenants[tenant]
+	if found {
+		usage.bytes += int64(size)
+	}
+}
+
+// prune forgets tenants whose window has ended, at most once a window, so that usage doesn't grow unbounded.
+func (quota *TenantQuota) prune(now time.Time) {
+
+	if quota.tenants == nil {
+		quota.tenants = map[string]*tenantUsage{}
+	}
+
+	window := quota.window()
+	if now.Sub(quota.pruned) < window {
+		return
+	}
+	quota.pruned = now
+
+	for tenant, usage := range quota.tenants {
+		if now.Sub(usage.start) >= window {
+			delete(quota.tenants, tenant)
+		}
+	}
+}
+
+func (quota *TenantQuota) window() time.Duration {
+
+	if quota.Window <= 0 {
+		return defaultWindow
+	}
+
+	return quota.Window
+}
@@ -0,0 +1,110 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TenantQuota", func() {
+
+	var (
+		buf   *bytes.Buffer
+		lgr   *Sabot
+		quota *TenantQuota
+		ctx   context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		quota = &TenantQuota{Key: "tenant_id", Events: 2}
+		lgr = &Sabot{Writer: buf, Quota: quota, Stats: &Stats{}}
+		ctx = lgr.WithFields(context.Background(), "tenant_id", "noisy")
+	})
+
+	When("a tenant exceeds its event quota", func() {
+		BeforeEach(func() {
+			for i := 0; i < 5; i++ {
+				lgr.Info(ctx, "chatty")
+			}
+			lgr.Error(ctx, "failed", fmt.Errorf("oops"))
+			lgr.Info(context.Background(), "quiet", "tenant_id", "polite")
+			lgr.Info(context.Background(), "untenanted")
+		})
+
+		It("should suppress with one notice, sparing errors and others", func() {
+			msgs := []string{}
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				evt, err := DecodeEvent([]byte(line))
+				Expect(err).ToNot(HaveOccurred())
+				msgs = append(msgs, evt.Msg)
+			}
+			Expect(msgs).To(Equal([]string{"chatty", "chatty", quotaNotice, "failed", "quiet", "untenanted"}))
+			Expect(buf.String()).To(ContainSubstring(`"quota_events":2`))
+
+			Expect(quota.Suppressed()).To(Equal(map[string]int64{"noisy": 3}))
+			Expect(lgr.State().Dropped).To(Equal(int64(3)))
+		})
+	})
+
+	When("a tenant exceeds its byte quota", func() {
+		BeforeEach(func() {
+			quota.Events = 0
+			quota.Bytes = 50
+			for i := 0; i < 5; i++ {
+				lgr.Info(ctx, "chatty")
+			}
+		})
+
+		It("should suppress once bytes are used", func() {
+			Expect(strings.Count(buf.String(), `"msg":"chatty"`)).To(Equal(1))
+			Expect(buf.String()).To(ContainSubstring(`"quota_bytes":50`))
+		})
+	})
+
+	When("the window ends without further events", func() {
+		BeforeEach(func() {
+			quota.Window = 20 * time.Millisecond
+			for i := 0; i < 3; i++ {
+				lgr.Info(ctx, "chatty")
+			}
+		})
+
+		It("should no longer report suppression", func() {
+			Expect(quota.Suppressed()).To(Equal(map[string]int64{"noisy": 1}))
+			time.Sleep(30 * time.Millisecond)
+			Expect(quota.Suppressed()).To(BeEmpty())
+		})
+	})
+
+	When("the window ends", func() {
+		BeforeEach(func() {
+			quota.Window = 20 * time.Millisecond
+			for i := 0; i < 3; i++ {
+				lgr.Info(ctx, "chatty")
+			}
+			time.Sleep(30 * time.Millisecond)
+			lgr.Info(ctx, "renewed")
+		})
+
+		It("should renew the quota", func() {
+			Expect(buf.String()).To(ContainSubstring(`"msg":"renewed"`))
+			Expect(quota.Suppressed()).To(BeEmpty())
+		})
+	})
+
+	When("configured", func() {
+		It("should set up the quota", func() {
+			lgr = (&Config{TenantKey: "org_id", TenantEvents: 10}).New(buf)
+			Expect(lgr.Quota.Key).To(Equal("org_id"))
+			Expect(lgr.Quota.window()).To(Equal(time.Minute))
+
+			Expect((&Config{TenantBytes: -1}).Validate()).To(MatchError("tenant quota is negative"))
+		})
+	})
+})
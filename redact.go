@@ -0,0 +1,86 @@
+package sabot
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	redactedNotice string = "--redacted--"
+	hashLen        int    = 16
+	pseudonymLen   int    = 32
+)
+
+// Redaction redacts, consistently hashes, or pseudonymizes the values of configured field keys.
+type Redaction struct {
+	// Keys are field keys whose values are replaced with a notice.
+	Keys []string `json:"keys" desc:"field keys whose values are redacted"`
+	// Hash are field keys whose values are replaced with a hash, keeping them correlatable across events.
+	Hash []string `json:"hash" desc:"field keys whose values are replaced with a salted hash"`
+	// Salt is prepended to values before hashing, so they can't be recovered by guessing.
+	Salt string `json:"salt" desc:"salt for hashed values" secret:"true"`
+	// Pseudonymize are field keys whose values are replaced with an hmac under PseudoKey,
+	// keeping identifiers correlatable across events and services sharing the key without exposing them.
+	Pseudonymize []string `json:"pseudonymize" desc:"field keys whose values are replaced with a keyed hmac"`
+	// PseudoKey is the hmac key for pseudonymized values.
+	PseudoKey string `json:"pseudo_key" desc:"key for pseudonymized values" secret:"true"`
+	// PseudoKeyId identifies PseudoKey, prefixing pseudonyms so that those under a rotated key are told apart.
+	PseudoKeyId string `json:"pseudo_key_id" desc:"id of key for pseudonymized values, prefixing each"`
+}
+
+// Empty is true when there is nothing to redact.
+func (rdt *Redaction) Empty() bool {
+
+	return len(rdt.Keys) == 0 && len(rdt.Hash) == 0 && len(rdt.Pseudonymize) == 0
+}
+
+// Apply redacts an event, and is suitable for use as a Hook.
+func (rdt *Redaction) Apply(ctx context.Context, evt *Event) bool {
+
+	for _, key := range rdt.Keys {
+		_, ok := evt.Fields[key]
+		if ok {
+			evt.Fields[key] = redactedNotice
+		}
+	}
+
+	for _, key := range rdt.Hash {
+		val, ok := evt.Fields[key]
+		if ok {
+			evt.Fields[key] = rdt.hash(stringify(val))
+		}
+	}
+
+	for _, key := range rdt.Pseudonymize {
+		val, ok := evt.Fields[key]
+		if ok {
+			evt.Fields[key] = rdt.pseudonym(stringify(val))
+		}
+	}
+
+	return true
+}
+
+//
+// unexported
+//
+
+func (rdt *Redaction) hash(val string) string {
+
+	sum := sha256.Sum256([]byte(rdt.Salt + val))
+	return hex.EncodeToString(sum[:])[:hashLen]
+}
+
+func (rdt *Redaction) pseudonym(val string) string {
+
+	mac := hmac.New(sha256.New, []byte(rdt.PseudoKey))
+	mac.Write([]byte(val))
+	sum := hex.EncodeToString(mac.Sum(nil))[:pseudonymLen]
+
+	if rdt.PseudoKeyId == "" {
+		return sum
+	}
+	return rdt.PseudoKeyId + ":" + sum
+}
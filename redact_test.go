@@ -0,0 +1,87 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Redaction", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		cfg := &Config{
+			Redact: Redaction{
+				Keys: []string{"password"},
+				Hash: []string{"user_id"},
+				Salt: "pepper",
+			},
+		}
+		lgr = cfg.New(buf)
+	})
+
+	JustBeforeEach(func() {
+		ctx := lgr.WithFields(context.Background(), "user_id", 12345)
+		lgr.Info(ctx, "logging in", "password", "hunter2", "email", "someone@example.com")
+		lgr.Info(ctx, "logged in")
+	})
+
+	When("redaction is configured", func() {
+		It("should redact and hash consistently", func() {
+			first := Fields(unjson(bytes.Split(buf.Bytes(), []byte("\n"))[0]))
+
+			Expect(first).To(HaveKeyWithValue("password", "--redacted--"))
+			Expect(first).To(HaveKeyWithValue("email", "someone@example.com"))
+			Expect(first["user_id"]).To(HaveLen(16))
+			Expect(first["user_id"]).ToNot(ContainSubstring("12345"))
+			Expect(bytes.Count(buf.Bytes(), []byte(first["user_id"].(string)))).To(Equal(2)) //nolint: forcetypeassert
+		})
+	})
+
+	When("pseudonymization is configured", func() {
+		BeforeEach(func() {
+			cfg := &Config{
+				Redact: Redaction{
+					Pseudonymize: []string{"user_id"},
+					PseudoKey:    "sekret",
+					PseudoKeyId:  "k1",
+				},
+			}
+			lgr = cfg.New(buf)
+		})
+
+		It("should pseudonymize consistently under the key", func() {
+			lines := bytes.Split(buf.Bytes(), []byte("\n"))
+			first := Fields(unjson(lines[0]))
+			second := Fields(unjson(lines[1]))
+
+			Expect(first["user_id"]).To(HavePrefix("k1:"))
+			Expect(first["user_id"]).To(HaveLen(35))
+			Expect(first["user_id"]).ToNot(ContainSubstring("12345"))
+			Expect(second["user_id"]).To(Equal(first["user_id"]))
+			Expect(first).To(HaveKeyWithValue("password", "hunter2"))
+		})
+
+		It("should differ under another key", func() {
+			other := &Redaction{Pseudonymize: []string{"user_id"}, PseudoKey: "other", PseudoKeyId: "k1"}
+			evt := &Event{Fields: Fields{"user_id": 12345}}
+			other.Apply(context.Background(), evt)
+
+			first := Fields(unjson(bytes.Split(buf.Bytes(), []byte("\n"))[0]))
+			Expect(evt.Fields["user_id"]).To(HavePrefix("k1:"))
+			Expect(evt.Fields["user_id"]).ToNot(Equal(first["user_id"]))
+		})
+
+		It("should require a key", func() {
+			cfg := &Config{Redact: Redaction{Pseudonymize: []string{"user_id"}}}
+			Expect(cfg.Validate()).To(MatchError("pseudonymize requires a pseudo key"))
+		})
+	})
+})
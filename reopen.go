@@ -0,0 +1,84 @@
+package sabot
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/pkg/errors"
+)
+
+// Reopener is implemented by writers that can reopen their files, as after external rotation.
+type Reopener interface {
+	Reopen() error
+}
+
+// Reopen reopens Writer, AltWriter, and Routes that implement Reopener,
+// so that classic logrotate configurations with a postrotate signal work.
+func (sabot *Sabot) Reopen() (err error) {
+
+	err = reopen(sabot.Writer)
+	if err != nil {
+		return
+	}
+
+	err = reopen(sabot.AltWriter)
+	if err != nil {
+		return
+	}
+
+	for _, writer := range sabot.Routes {
+		err = reopen(writer)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// RunReopen calls Reopen for each of sigs received until ctx is done, logging any error.
+//
+// SIGHUP is used when sigs is empty, matching logrotate's customary postrotate kill -HUP,
+// while on platforms without it RunReopen just waits for ctx.
+func (sabot *Sabot) RunReopen(ctx context.Context, sigs ...os.Signal) {
+
+	if len(sigs) == 0 {
+		sigs = reopenSignals
+	}
+	if len(sigs) == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	defer signal.Stop(ch)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ch:
+			err := sabot.Reopen()
+			if err != nil {
+				sabot.Error(ctx, "failed to reopen log files", err)
+			}
+		}
+	}
+}
+
+//
+// unexported
+//
+
+func reopen(writer io.Writer) error {
+
+	ro, ok := writer.(Reopener)
+	if !ok {
+		return nil
+	}
+
+	return errors.Wrapf(ro.Reopen(), "failed to reopen %T", writer)
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package sabot
+
+import (
+	"os"
+)
+
+//
+// unexported
+//
+
+// reopenSignals are received by RunReopen when none are given, there being no SIGHUP on this platform.
+var reopenSignals = []os.Signal{}
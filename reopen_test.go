@@ -0,0 +1,70 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type reopenWriter struct {
+	bytes.Buffer
+	reopened chan struct{}
+}
+
+func (rw *reopenWriter) Reopen() error {
+
+	rw.reopened <- struct{}{}
+	return nil
+}
+
+var _ = Describe("Reopen", func() {
+
+	var (
+		rw  *reopenWriter
+		lgr *Sabot
+	)
+
+	BeforeEach(func() {
+		rw = &reopenWriter{reopened: make(chan struct{}, 2)}
+		lgr = &Sabot{
+			Writer: rw,
+			Routes: map[Level]io.Writer{Error: &bytes.Buffer{}},
+		}
+	})
+
+	When("reopening", func() {
+		It("should reopen writers that can", func() {
+			Expect(lgr.Reopen()).To(Succeed())
+			Expect(rw.reopened).To(Receive())
+		})
+	})
+
+	When("signaled", func() {
+		It("should reopen", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// keep hup from killing the test before RunReopen is listening
+
+			held := make(chan os.Signal, 1)
+			signal.Notify(held, syscall.SIGHUP)
+			defer signal.Stop(held)
+
+			proc, err := os.FindProcess(os.Getpid())
+			Expect(err).ToNot(HaveOccurred())
+
+			go lgr.RunReopen(ctx)
+			Eventually(func() bool {
+				_ = proc.Signal(syscall.SIGHUP)
+				return len(rw.reopened) > 0
+			}, time.Second, 10*time.Millisecond).Should(BeTrue())
+		})
+	})
+})
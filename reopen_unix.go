@@ -0,0 +1,15 @@
+//go:build unix
+
+package sabot
+
+import (
+	"os"
+	"syscall"
+)
+
+//
+// unexported
+//
+
+// reopenSignals are received by RunReopen when none are given.
+var reopenSignals = []os.Signal{syscall.SIGHUP}
@@ -0,0 +1,125 @@
+// Package replay implements replay of recorded ndjson event streams through a sink.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Config is the configurable fields of Replayer.
+type Config struct {
+	Speed float64 `json:"speed" desc:"multiple of recorded pace at which to replay, zero for as fast as possible"`
+}
+
+// New creates a Replayer from Config.
+func (cfg *Config) New(sink io.Writer) *Replayer {
+
+	return &Replayer{
+		Speed: cfg.Speed,
+		Sink:  sink,
+	}
+}
+
+// Replayer writes recorded events to a sink, paced per their timestamps.
+//
+// Useful for capacity testing a sink with a production-like stream before rollout.
+type Replayer struct {
+	// Speed is the multiple of recorded pace, zero for as fast as possible.
+	Speed float64
+	// Sink is where events are written.
+	Sink io.Writer
+}
+
+// Result summarizes a replay.
+type Result struct {
+	Events  int           `json:"events"`
+	Bytes   int64         `json:"bytes"`
+	Errors  int           `json:"errors"`
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+// Replay reads ndjson events from src and writes them to the sink until src is exhausted or ctx is done.
+//
+// Events lacking a parsable ts are written without delay.
+func (rp *Replayer) Replay(ctx context.Context, src io.Reader) (result Result, err error) {
+
+	start := time.Now()
+	defer func() { result.Elapsed = time.Since(start) }()
+
+	scanner := sabot.NewScanner(src)
+
+	var first time.Time
+	for scanner.Scan() {
+
+		line := scanner.Bytes()
+
+		ts, ok := eventTime(line)
+		if ok && rp.Speed > 0 {
+			if first.IsZero() {
+				first = ts
+			}
+
+			err = pause(ctx, start, ts.Sub(first), rp.Speed)
+			if err != nil {
+				return
+			}
+		}
+
+		n, wErr := rp.Sink.Write(append(line, '\n'))
+		result.Events++
+		result.Bytes += int64(n)
+		if wErr != nil {
+			result.Errors++
+		}
+	}
+
+	err = scanner.Err()
+	return
+}
+
+//
+// unexported
+//
+
+func eventTime(line []byte) (ts time.Time, ok bool) {
+
+	head := struct {
+		Ts any `json:"ts"`
+	}{}
+
+	err := json.Unmarshal(line, &head)
+	if err != nil {
+		return
+	}
+
+	ts, err = sabot.ParseTs(head.Ts)
+	if err != nil || ts.IsZero() {
+		return
+	}
+
+	return ts, true
+}
+
+func pause(ctx context.Context, start time.Time, offset time.Duration, speed float64) error {
+
+	wait := time.Until(start.Add(time.Duration(float64(offset) / speed)))
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "replay interrupted")
+	case <-timer.C:
+		return nil
+	}
+}
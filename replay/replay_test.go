@@ -0,0 +1,68 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestReplay(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Replay Suite")
+}
+
+var _ = Describe("Replayer", func() {
+
+	var (
+		sink   *bytes.Buffer
+		rp     *Replayer
+		src    string
+		result Result
+		err    error
+	)
+
+	BeforeEach(func() {
+		sink = &bytes.Buffer{}
+		src = strings.Join([]string{
+			`{"level":"info","msg":"one","ts":"2023-11-25T21:20:54.000Z"}`,
+			``,
+			`{"level":"info","msg":"two","ts":"2023-11-25T21:20:54.100Z"}`,
+			`{"level":"info","msg":"three","ts":"2023-11-25T21:20:54.200Z"}`,
+		}, "\n")
+	})
+
+	JustBeforeEach(func() {
+		result, err = rp.Replay(context.Background(), strings.NewReader(src))
+	})
+
+	When("replaying as fast as possible", func() {
+		BeforeEach(func() {
+			rp = (&Config{}).New(sink)
+		})
+
+		It("should write all events promptly", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Events).To(Equal(3))
+			Expect(result.Bytes).To(BeNumerically("==", sink.Len()))
+			Expect(result.Elapsed).To(BeNumerically("<", 50*time.Millisecond))
+			Expect(strings.Count(sink.String(), "\n")).To(Equal(3))
+		})
+	})
+
+	When("replaying at double speed", func() {
+		BeforeEach(func() {
+			rp = (&Config{Speed: 2}).New(sink)
+		})
+
+		It("should pace events", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Events).To(Equal(3))
+			Expect(result.Elapsed).To(BeNumerically("~", 100*time.Millisecond, 40*time.Millisecond))
+		})
+	})
+})
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -22,16 +21,109 @@ type Fields map[string]any
 
 // Config is the configurable fields of Sabot.
 type Config struct {
-	MaxLen int `json:"max_len" desc:"maximum length that will be logged for any field"`
+	MaxLen        int               `json:"max_len" desc:"maximum length that will be logged for any field, zero for unlimited"`
+	MinLevel      Level             `json:"min_level" desc:"least severe level logged: trace, debug, info, warn, or error, defaulting to info"`
+	LabelKeys     []string          `json:"label_keys" desc:"ctx field keys to set as pprof labels"`
+	Format        string            `json:"format" desc:"output format: json, ecs, logfmt, gelf, pairs, console, msgpack, cbor, or auto for console on a terminal and json otherwise"`
+	Labels        []string          `json:"labels" desc:"field keys of low-cardinality values, for sinks to index as labels"`
+	Payloads      []string          `json:"payloads" desc:"field keys of high-cardinality values, for sinks to leave unindexed"`
+	Internal      []string          `json:"internal" desc:"field keys classified as internal"`
+	Confidential  []string          `json:"confidential" desc:"field keys classified as confidential"`
+	MaxClass      Class             `json:"max_class" desc:"most sensitive class of field written: public, internal, or confidential, all when empty"`
+	SiteStats     bool              `json:"site_stats" desc:"count events and bytes by call site"`
+	Schema        int               `json:"schema" desc:"schema version stamped on events, zero for none"`
+	WarnMisuse    bool              `json:"warn_misuse" desc:"warn once per call site of misuse such as odd kv counts"`
+	Keys          KeyMode           `json:"keys" desc:"dotted key handling for json and ecs: expand or flatten, as-is when empty"`
+	Columns       []string          `json:"columns" desc:"field keys whose values lead console lines, in order, as key or key:width"`
+	Deterministic bool              `json:"deterministic" desc:"encode cbor deterministically, keys ordered by encoding and floats shortest, for equal events to be equal bytes"`
+	Compact       bool              `json:"compact" desc:"hide console fields other than columns, noting their count"`
+	Theme         string            `json:"theme" desc:"console color theme: dark or light, dark when empty"`
+	ThemeColors   map[string]string `json:"theme_colors" desc:"ansi sgr codes overriding theme colors by level name or key, column, count, and stack"`
+	Color         ColorMode         `json:"color" desc:"console color: always, never, or auto when empty, honoring NO_COLOR, FORCE_COLOR, and CLICOLOR"`
+	HashTruncated bool              `json:"hash_truncated" desc:"end truncated values with a hash of the whole, for comparison across events"`
+	TimeLayout    string            `json:"time_layout" desc:"layout of the ts field: rfc3339, rfc3339milli, rfc3339micro, rfc3339nano, a go layout parsing as rfc3339, or unixms for a number of epoch milliseconds, rfc3339nano when empty"`
+	Nested        bool              `json:"nested" desc:"embed marshalled objects, maps, and slices as nested json rather than as strings"`
+	Bytes         BytesMode         `json:"bytes" desc:"[]byte value handling: hex, text for utf-8 when valid, or length, base64 when empty"`
+	AddCaller     bool              `json:"add_caller" desc:"log the call site as the caller field"`
+	ErrorStack    bool              `json:"error_stack" desc:"log a stack from where errors are logged as the stack field, when the error carries none"`
+	ErrorChain    bool              `json:"error_chain" desc:"log the messages of a wrapped error's chain as the error_chain array, innermost first"`
+	CallerSkip    int               `json:"caller_skip" desc:"frames skipped past the first outside of sabot when finding the caller, for wrappers"`
+	TenantKey     string            `json:"tenant_key" desc:"field identifying the tenant, enabling per-tenant quotas when set"`
+	TenantEvents  int64             `json:"tenant_events" desc:"events allowed per tenant per window, zero for no limit"`
+	TenantBytes   int64             `json:"tenant_bytes" desc:"bytes allowed per tenant per window, zero for no limit"`
+	TenantWindow  time.Duration     `json:"tenant_window" desc:"how long a tenant's quota lasts before renewing, a minute when zero"`
+	Redact        Redaction         `json:"redact"`
+	Purposes      PurposeGate       `json:"purposes"`
 }
 
 // New creates a Sabot from Config.
 func (cfg *Config) New(writer io.Writer) *Sabot {
 
-	return &Sabot{
-		MaxLen: cfg.MaxLen,
-		Writer: writer,
+	sabot := &Sabot{
+		MaxLen:        cfg.MaxLen,
+		MinLevel:      cfg.MinLevel,
+		HashTruncated: cfg.HashTruncated,
+		TimeLayout:    timeLayout(cfg.TimeLayout),
+		Nested:        cfg.Nested,
+		Bytes:         cfg.Bytes,
+		AddCaller:     cfg.AddCaller,
+		ErrorStack:    cfg.ErrorStack,
+		ErrorChain:    cfg.ErrorChain,
+		CallerSkip:    cfg.CallerSkip,
+		Schema:        cfg.Schema,
+		MaxClass:      cfg.MaxClass,
+		WarnMisuse:    cfg.WarnMisuse,
+		LabelKeys:     cfg.LabelKeys,
+		Encoder:       cfg.encoder(writer),
+		Writer:        writer,
+		Stats:         &Stats{},
 	}
+
+	for _, key := range cfg.Labels {
+		sabot.hint(key, LabelHint)
+	}
+	for _, key := range cfg.Payloads {
+		sabot.hint(key, PayloadHint)
+	}
+	for _, key := range cfg.Internal {
+		sabot.classify(key, Internal)
+	}
+	for _, key := range cfg.Confidential {
+		sabot.classify(key, Confidential)
+	}
+
+	if cfg.SiteStats {
+		sabot.Sites = &SiteStats{}
+	}
+
+	if cfg.TenantKey != "" {
+		sabot.Quota = &TenantQuota{
+			Key:    cfg.TenantKey,
+			Events: cfg.TenantEvents,
+			Bytes:  cfg.TenantBytes,
+			Window: cfg.TenantWindow,
+		}
+	}
+
+	if !cfg.Redact.Empty() {
+		redact := cfg.Redact
+		sabot.Hooks = append(sabot.Hooks, redact.Apply)
+	}
+
+	if !cfg.Purposes.Empty() {
+		gate := cfg.Purposes
+		sabot.Hooks = append(sabot.Hooks, gate.Apply)
+	}
+
+	return sabot
+}
+
+// LevelWriter is implemented by writers wanting the level of each event.
+//
+// When Writer implements it, WriteLevel is used in place of Write,
+// so that a sink can route by level without re-parsing the json it's handed.
+type LevelWriter interface {
+	WriteLevel(level string, data []byte) (n int, err error)
 }
 
 // LogKey is a unique to this package key for use with context Value.
@@ -43,45 +135,130 @@ type Sabot struct {
 	Writer io.Writer
 	// AltWriter is where output is written when Writer.Write returns an error.
 	AltWriter io.Writer
-	// MaxLen is the length at which string field values are truncated.
+	// MaxLen is the length at which string field values are truncated, zero for unlimited.
 	MaxLen int
-	// EnableDebug determines if debug events are logged.
+	// MinLevel is the least severe level logged, info when zero.
+	MinLevel Level
+	// EnableDebug determines if debug events are logged, regardless of MinLevel.
+	//
+	// Deprecated: set MinLevel to Debug instead.
 	EnableDebug bool
-	// EnableTrace determines if trace events are logged.
+	// EnableTrace determines if trace events are logged, regardless of MinLevel.
+	//
+	// Deprecated: set MinLevel to Trace instead.
 	EnableTrace bool
+	// LabelKeys are the ctx field keys set as pprof labels by Do.
+	LabelKeys []string
+	// Computed are evaluated for each event, yielding fields that kv and ctx fields take precedence over.
+	Computed []FieldFunc
+	// Hooks are called in order with each event before it is written.
+	Hooks []Hook
+	// Extractors read fields placed in ctx by other loggers and libraries.
+	Extractors []Extractor
+	// Encoder encodes events, json when nil.
+	Encoder Encoder
+	// Disabled levels are not logged, regardless of other settings.
+	Disabled map[Level]bool
+	// Routes are writers by level, used in place of Writer for the given levels.
+	Routes map[Level]io.Writer
+	// ExitHooks are run by Fatal before exiting.
+	ExitHooks []ExitHook
+	// ExitCode is the code Fatal exits with, one when zero.
+	ExitCode int
+	// StrictAudit determines if audit events missing required fields are refused.
+	StrictAudit bool
+	// Hints are how fields are best indexed by key, overridden by AsLabel and AsPayload at call sites.
+	Hints map[string]Hint
+	// Classes are the classifications of fields by key, overridden by Classified at call sites.
+	Classes map[string]Class
+	// MaxClass is the most sensitive class of field written, with those above deleted after hooks, zero for all.
+	MaxClass Class
+	// WarnMisuse determines if misuse, such as an odd kv count, is warned of once per call site.
+	WarnMisuse bool
+	// Stats counts written and dropped events when set, as seen via State.
+	Stats *Stats
+	// Sites counts events and bytes by call site when set.
+	Sites *SiteStats
+	// Alarm signals pressure crossing thresholds when set, as seen via Pressure.
+	Alarm *PressureAlarm
+	// Quota caps events and bytes per tenant when set.
+	Quota *TenantQuota
+	// Schema is the version stamped on events as the schema field, zero for none.
+	Schema int
+	// HashTruncated determines if truncated values end with a hash of the whole, for comparison across events.
+	HashTruncated bool
+	// TimeLayout is the go layout of the ts field, or TimeUnixMilli, as by json.Marshal of time.Time when empty.
+	TimeLayout string
+	// Nested determines if marshalled objects, maps, slices, and json.Marshalers are embedded as nested json rather than as strings,
+	// sparing a second decode downstream.
+	Nested bool
+	// Bytes is how []byte values are logged, base64 when empty.
+	Bytes BytesMode
+	// ErrorStack determines if a stack is captured where Error or Fatal is called, as the stack field,
+	// when the error carries none of its own.
+	ErrorStack bool
+	// ErrorChain determines if the messages of a wrapped error's Unwrap chain are logged as the error_chain array,
+	// innermost first, sparing the parse of the flattened error field.
+	ErrorChain bool
+	// AddCaller determines if the call site is logged as the caller field, as in "pkg/file.go:42".
+	AddCaller bool
+	// CallerSkip is the number of frames skipped past the first outside of sabot, so that wrappers are skipped.
+	CallerSkip int
+	// fields are merged into each event, as from With.
+	fields Fields
+	// level overrides MinLevel when levelSet, both accessed atomically.
+	level    int64
+	levelSet int32
 }
 
 // Info logs info level events.
 func (sabot *Sabot) Info(ctx context.Context, msg string, kv ...any) {
 
-	sabot.log(ctx, "info", msg, kv)
+	if !sabot.enabled(ctx, Info) {
+		return
+	}
+
+	sabot.log(ctx, "info", msg, nil, kv)
+}
+
+// Warn logs warn level events, for conditions that are recoverable but notable.
+func (sabot *Sabot) Warn(ctx context.Context, msg string, kv ...any) {
+
+	if !sabot.enabled(ctx, Warn) {
+		return
+	}
+
+	sabot.log(ctx, "warn", msg, nil, kv)
 }
 
 // Debug logs debug level events.
 func (sabot *Sabot) Debug(ctx context.Context, msg string, kv ...any) {
 
-	if !sabot.EnableDebug {
+	if !sabot.enabled(ctx, Debug) {
 		return
 	}
 
-	sabot.log(ctx, "debug", msg, kv)
+	sabot.log(ctx, "debug", msg, nil, kv)
 }
 
 // Trace logs trace level events.
 func (sabot *Sabot) Trace(ctx context.Context, msg string, kv ...any) {
 
-	if !sabot.EnableTrace {
+	if !sabot.enabled(ctx, Trace) {
 		return
 	}
 
-	sabot.log(ctx, "trace", msg, kv)
+	sabot.log(ctx, "trace", msg, nil, kv)
 }
 
 // Error logs error level events.
 func (sabot *Sabot) Error(ctx context.Context, msg string, err error, kv ...any) {
 
-	kv = append(kv, "error", fmt.Sprintf("%+v", err))
-	sabot.log(ctx, "error", msg, kv)
+	if !sabot.enabled(ctx, Error) || !sabot.first(ctx, msg, err) {
+		return
+	}
+
+	sabot.log(ctx, "error", msg, err, sabot.errorKv(ctx, err, kv))
 }
 
 // WithFields adds log fields to a given context.
@@ -96,45 +273,159 @@ func (sabot *Sabot) GetFields(ctx context.Context) Fields {
 	return getFields(ctx)
 }
 
+// WithoutFields returns a context with the given keys removed from its log fields,
+// such as to stop logging a customer email after an auth step.
+func (sabot *Sabot) WithoutFields(ctx context.Context, keys ...string) context.Context {
+
+	fields := copyFields(ctx)
+	for _, key := range keys {
+		delete(fields, key)
+	}
+
+	return context.WithValue(ctx, LogKey{}, fields)
+}
+
+// ClearFields returns a context with no log fields.
+func (sabot *Sabot) ClearFields(ctx context.Context) context.Context {
+
+	return context.WithValue(ctx, LogKey{}, Fields{})
+}
+
+// With returns a child logger merging kv into each of its events, for when there's no ctx to carry fields.
+//
+// Fields from With are taken over by kv and ctx fields when duplicate key.
+// The child shares Stats and Sites, and otherwise is a Clone.
+func (sabot *Sabot) With(kv ...any) *Sabot {
+
+	child := sabot.Clone()
+	child.Stats = sabot.Stats
+	child.Sites = sabot.Sites
+
+	child.fields = Fields{}
+	merge(child.fields, sabot.fields)
+	merge(child.fields, newFields(kv))
+
+	return child
+}
+
 //
 // unexported
 //
 
-func (sabot *Sabot) log(ctx context.Context, level, msg string, kv []any) {
+func (sabot *Sabot) log(ctx context.Context, level, msg string, err error, kv []any) {
 
-	now := time.Now().UTC()
+	if !sabot.spend(ctx, level) {
+		return
+	}
 
-	ctxFields := sabot.GetFields(ctx)
-	fields := newFields(kv)
+	sabot.emit(ctx, sabot.ctxFields(ctx), level, msg, err, kv)
+}
 
-	// silently overwrite kv from ctx and boilerplate when duplicate key
+func (sabot *Sabot) emit(ctx context.Context, ctxFields Fields, level, msg string, err error, kv []any) {
 
-	for key, val := range ctxFields {
-		fields[key] = val
+	sabot.emitEvent(ctx, ctxFields, sabot.newEvent(ctx, ctxFields, level, msg, err, kv))
+}
+
+func (sabot *Sabot) emitEvent(ctx context.Context, ctxFields Fields, evt *Event) {
+
+	level := evt.Level
+	if !sabot.hook(ctx, evt) {
+		sabot.Stats.drop()
+		return
 	}
 
-	fields["msg"] = msg
-	fields["level"] = level
-	fields["ts"] = now
+	tenant, ok := sabot.admit(ctx, ctxFields, evt)
+	if !ok {
+		return
+	}
+	evt.Declassify(sabot.MaxClass)
+	evt.truncate(sabot.truncation())
+	record(ctx, evt)
 
-	fields.truncate(sabot.MaxLen)
+	ew, ok := sabot.writerFor(level).(EventWriter)
+	if ok {
+		sabot.writeEvent(ew, evt)
+		return
+	}
 
-	// marshal and try to emit something in case of trouble
+	// encode and try to emit something in case of trouble
 
-	data, err := json.Marshal(fields)
+	data, err := sabot.encode(evt)
 	if err != nil {
 		// hard to trigger since newFields returns valid
-		err = errors.Wrapf(err, "failed to marshal log message")
-		data = []byte(fmt.Sprintf(`{"%s": "%+v", "msg": "%#v"}`, logErrorKey, err, fields))
+		err = errors.Wrapf(err, "failed to encode log message")
+		data = []byte(fmt.Sprintf(`{"%s": "%+v", "msg": "%#v"}`, logErrorKey, err, evt.Fields))
+	}
+
+	sabot.Quota.used(tenant, len(data)+1)
+	sabot.write(level, data, evt.Fields)
+}
+
+func (sabot *Sabot) write(level string, data []byte, fields Fields) {
+
+	_, isBinary := sabot.Encoder.(BinaryEncoder)
+	if !isBinary {
+		data = append(data, []byte("\n")...)
 	}
 
-	_, err = sabot.Writer.Write(append(data, []byte("\n")...))
+	writer := sabot.writerFor(level)
+
+	var err error
+	lw, ok := writer.(LevelWriter)
+	if ok {
+		_, err = lw.WriteLevel(level, data)
+	} else {
+		_, err = writer.Write(data)
+	}
+	sabot.Stats.wrote(err)
+	sabot.Sites.count(len(data))
+	sabot.alarm()
+
 	if err != nil && sabot.AltWriter != nil {
 		err = errors.Wrapf(err, "failed to write")
 		_, _ = fmt.Fprintf(sabot.AltWriter, "%s: %+v with fields %#v\n", logErrorKey, err, fields)
 	}
 }
 
+func (sabot *Sabot) writeEvent(ew EventWriter, evt *Event) {
+
+	err := ew.WriteEvent(evt)
+	sabot.Stats.wrote(err)
+	sabot.Sites.count(0)
+	sabot.alarm()
+
+	if err != nil && sabot.AltWriter != nil {
+		err = errors.Wrapf(err, "failed to write event")
+		_, _ = fmt.Fprintf(sabot.AltWriter, "%s: %+v with event %#v\n", logErrorKey, err, evt)
+	}
+}
+
+func (sabot *Sabot) errorKv(ctx context.Context, err error, kv []any) []any {
+
+	// kv after error fields, so as to take precedence
+
+	kv = append(errorFields(err), kv...)
+	kv = append(kv, originFields(ctx, err)...)
+	if sabot.ErrorStack && !hasStack(err) {
+		kv = append(kv, stackKey, callStack())
+	}
+
+	// typed, so as to be logged as arrays rather than marshalled to strings
+
+	joined := joinedErrors(err)
+	if joined != nil {
+		kv = append(kv, Field{Key: errorsKey, Val: joined, typed: true})
+	}
+	if sabot.ErrorChain {
+		chain := errorChain(err)
+		if chain != nil {
+			kv = append(kv, Field{Key: errorChainKey, Val: chain, typed: true})
+		}
+	}
+
+	return append(kv, errorTypeKey, errorType(err), "error", fmt.Sprintf("%+v", err))
+}
+
 func withFields(ctx context.Context, kv []any) context.Context {
 
 	fields := copyFields(ctx)
@@ -175,26 +466,40 @@ func logErrorFields(err error, kv []any) Fields {
 
 func newFields(kv []any) Fields {
 
-	if len(kv)%2 != 0 {
-		err := errors.Errorf("cannot create fields from odd count")
-		return logErrorFields(err, kv)
-	}
-
-	// interpret elements of slice as key-value pairs
+	// interpret elements of slice as key-value pairs, or a Field
 
 	fields := Fields{}
-	for i := 0; i < len(kv); i += 2 {
+	for i := 0; i < len(kv); {
+
+		field, ok := kv[i].(Field)
+		if ok {
+			i++
+
+			// typed fields are ready as-is, sparing the type switch and any marshalling
 
-		key, ok := kv[i].(string)
-		if !ok {
-			err := errors.Errorf("non-string field key: %#v", kv[i])
-			return logErrorFields(err, kv)
+			if field.typed {
+				fields[field.Key] = field.Val
+				continue
+			}
+		} else {
+			if i+1 == len(kv) {
+				err := errors.Errorf("cannot create fields from odd count")
+				return logErrorFields(err, kv)
+			}
+
+			field.Key, ok = kv[i].(string)
+			if !ok {
+				err := errors.Errorf("non-string field key: %#v", kv[i])
+				return logErrorFields(err, kv)
+			}
+			field.Val = kv[i+1]
+			i += 2
 		}
 
 		var err error
-		fields[key], err = marshalUnknown(kv[i+1])
+		fields[field.Key], err = marshalUnknown(field.Val)
 		if err != nil {
-			delete(fields, key)
+			delete(fields, field.Key)
 			for ek, ev := range logErrorFields(err, kv) {
 				fields[ek] = ev
 			}
@@ -206,16 +511,36 @@ func newFields(kv []any) Fields {
 
 func marshalUnknown(obj any) (any, error) {
 
-	switch obj.(type) {
-	case string, []byte, int, int64, float64, time.Time, time.Duration:
+	switch obj := obj.(type) {
+	case string, []byte, int, int64, float64, time.Time, time.Duration, whole, group:
 		return obj, nil
+	case hinted:
+		val, err := marshalUnknown(obj.val)
+		return hinted{val: val, hint: obj.hint, class: obj.class}, err
+	case Lazy:
+		return obj.evaluate()
+	case func() any:
+		return Lazy(obj).evaluate()
+	case LogValuer:
+		return resolve(obj)
+	case json.Marshaler:
+		// as marshalled, rather than via fmt.Stringer, embedded when Nested as with other objects
+		data, err := json.Marshal(obj)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to marshal: %#v", obj)
+			return logErrorKey, err
+		}
+		return nested(redactSecrets(obj, data)), nil
+	case fmt.Stringer:
+		// via fmt, so that a nil receiver or panic is printed rather than raised
+		return fmt.Sprint(obj), nil
 	default:
 		data, err := json.Marshal(obj)
 		if err != nil {
 			err = errors.Wrapf(err, "failed to marshal: %#v", obj)
 			return logErrorKey, err
 		}
-		return string(data), nil
+		return nested(redactSecrets(obj, data)), nil
 	}
 }
 
@@ -228,21 +553,3 @@ func copyFields(ctx context.Context) Fields {
 
 	return cp
 }
-
-func (fields Fields) truncate(max int) {
-
-	// account for notice length in truncation result
-
-	max -= len(truncationNotice)
-	if max < 1 {
-		return
-	}
-
-	for key, val := range fields {
-
-		str, ok := val.(string)
-		if ok && max < len(str) {
-			fields[key] = strings.Join([]string{str[:max], truncationNotice}, "")
-		}
-	}
-}
@@ -61,6 +61,16 @@ func BenchmarkLog(b *testing.B) {
 				"obj_field", demo{One: "one", Two: 2},
 			},
 		},
+		{
+			"typed fields",
+			[]any{
+				String("string_field", "an important thing"),
+				Int("integer_field", 88),
+				Float64("float_field", 88.8),
+				Time("ts_field", time.Time{}),
+				Duration("duration_field", time.Minute),
+			},
+		},
 	}
 	for _, tt := range tests {
 		b.Run(fmt.Sprintf("Info-%s", tt.name), func(b *testing.B) {
@@ -87,6 +97,44 @@ func BenchmarkLog(b *testing.B) {
 	}
 }
 
+// bound vs ctx with twenty fields
+
+/*
+~/proj/sabot$ go test -run=XXX -bench=BenchmarkBound github.com/clarktrimble/sabot
+BenchmarkBound/Info-ctx         	  103440	     11658 ns/op	    4304 B/op	      68 allocs/op
+BenchmarkBound/Info-bound       	  352592	      4047 ns/op	    3240 B/op	      25 allocs/op
+*/
+
+func BenchmarkBound(b *testing.B) {
+
+	lgr := &Sabot{
+		Writer: &nullWriter{},
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 20; i++ {
+		ctx = lgr.WithFields(ctx, fmt.Sprintf("field_%02d", i), "a moderately long value")
+	}
+
+	b.Run("Info-ctx", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			lgr.Info(ctx, "test message", "idx", i)
+		}
+	})
+
+	b.Run("Info-bound", func(b *testing.B) {
+		bnd := lgr.Bind(ctx)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			bnd.Info("test message", "idx", i)
+		}
+	})
+}
+
 type nullWriter struct{}
 
 func (nw *nullWriter) Write(p []byte) (n int, err error) {
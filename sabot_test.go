@@ -94,6 +94,32 @@ var _ = Describe("Sabot", func() {
 						}))
 					})
 				})
+
+				When("another thing is added and then removed", func() {
+					var (
+						parent context.Context
+					)
+
+					BeforeEach(func() {
+						parent = lgr.WithFields(ctx, "another", "thing")
+						ctx = lgr.WithoutFields(parent, "another", "absent")
+					})
+
+					It("should return the rest, leaving the parent as was", func() {
+						Expect(fields).To(Equal(Fields{"foo": "bar"}))
+						Expect(lgr.GetFields(parent)).To(Equal(Fields{"foo": "bar", "another": "thing"}))
+					})
+				})
+
+				When("cleared", func() {
+					BeforeEach(func() {
+						ctx = lgr.ClearFields(ctx)
+					})
+
+					It("should return none", func() {
+						Expect(fields).To(Equal(Fields{}))
+					})
+				})
 			})
 
 			When("odd count stored in ctx", func() {
@@ -153,10 +179,11 @@ var _ = Describe("Sabot", func() {
 					})
 					It("should write the message, level, ts, and error", func() {
 						Expect(delog(buf)).To(Equal(Fields{
-							"level": "error",
-							"msg":   "a noteworthy occurrence",
-							"ts":    "nowish",
-							"error": "oops",
+							"level":      "error",
+							"msg":        "a noteworthy occurrence",
+							"ts":         "nowish",
+							"error_type": "*errors.errorString",
+							"error":      "oops",
 						}))
 					})
 				})
@@ -288,10 +315,11 @@ var _ = Describe("Sabot", func() {
 
 						Expect(lgd["foo"]).To(HaveLen(44))
 						Expect(lgd).To(Equal(Fields{
-							"level": "info",
-							"msg":   "a noteworthy occurrence",
-							"ts":    "nowish",
-							"foo":   `["bar","bar","bar","bar","bar",--truncated--`,
+							"level":        "info",
+							"msg":          "a noteworthy occurrence",
+							"ts":           "nowish",
+							"foo":          `["bar","bar","bar","bar","bar"--truncated--]`,
+							"foo_orig_len": float64(46),
 						}))
 					})
 				})
@@ -392,6 +420,38 @@ var _ = Describe("Sabot", func() {
 						Expect(altBuf.String()).To(HavePrefix("logerror"))
 					})
 				})
+
+				When("writer implements EventWriter", func() {
+					var ew *eventWriter
+
+					BeforeEach(func() {
+						ew = &eventWriter{}
+						lgr.Writer = ew
+						kv = []any{"foo", "bar"}
+					})
+
+					It("should write the event without encoding", func() {
+						Expect(ew.events).To(HaveLen(1))
+						Expect(ew.events[0].Msg).To(Equal("a noteworthy occurrence"))
+						Expect(ew.events[0].Level).To(Equal("info"))
+						Expect(ew.events[0].Time).To(BeTemporally("~", time.Now(), 9*time.Millisecond))
+						Expect(ew.events[0].Fields).To(Equal(Fields{"foo": "bar"}))
+					})
+				})
+
+				When("writer implements LevelWriter", func() {
+					var lw *levelWriter
+
+					BeforeEach(func() {
+						lw = &levelWriter{}
+						lgr.Writer = lw
+					})
+
+					It("should write with the level", func() {
+						Expect(lw.level).To(Equal("info"))
+						Expect(delog(&lw.buf)).To(HaveKeyWithValue("msg", "a noteworthy occurrence"))
+					})
+				})
 			})
 
 		})
@@ -434,6 +494,39 @@ func replace(logged Fields) {
 	}
 }
 
+type eventWriter struct {
+	events []*Event
+}
+
+func (ew *eventWriter) Write(p []byte) (n int, err error) {
+
+	err = fmt.Errorf("should use WriteEvent")
+	return
+}
+
+func (ew *eventWriter) WriteEvent(evt *Event) error {
+
+	ew.events = append(ew.events, evt)
+	return nil
+}
+
+type levelWriter struct {
+	level string
+	buf   bytes.Buffer
+}
+
+func (lw *levelWriter) Write(p []byte) (n int, err error) {
+
+	err = fmt.Errorf("should use WriteLevel")
+	return
+}
+
+func (lw *levelWriter) WriteLevel(level string, p []byte) (n int, err error) {
+
+	lw.level = level
+	return lw.buf.Write(p)
+}
+
 type failWriter struct{}
 
 func (fw failWriter) Write(p []byte) (n int, err error) {
@@ -0,0 +1,111 @@
+package sabottest
+
+import (
+	"fmt"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// HaveLogged succeeds when a Recorder, or slice of events, includes an event with level and msg.
+func HaveLogged(level, msg string) types.GomegaMatcher {
+
+	return &eventMatcher{
+		desc: fmt.Sprintf("to have logged %q at level %q", msg, level),
+		match: func(event sabot.Fields) (bool, error) {
+			return event["level"] == level && event["msg"] == msg, nil
+		},
+	}
+}
+
+// HaveField succeeds when a Recorder, slice of events, or event includes key with val.
+//
+// Note that gomega has a HaveField of it's own, so sabottest is best not dot-imported alongside it.
+// Val may be a matcher, otherwise it is compared with BeEquivalentTo,
+// so that an int will match the float64 found after decoding.
+func HaveField(key string, val any) types.GomegaMatcher {
+
+	matcher, ok := val.(types.GomegaMatcher)
+	if !ok {
+		matcher = gomega.BeEquivalentTo(val)
+	}
+
+	return &eventMatcher{
+		desc: fmt.Sprintf("to have field %q with value %s", key, format.Object(val, 1)),
+		match: func(event sabot.Fields) (bool, error) {
+			actual, ok := event[key]
+			if !ok {
+				return false, nil
+			}
+			return matcher.Match(actual)
+		},
+	}
+}
+
+//
+// unexported
+//
+
+type eventMatcher struct {
+	desc  string
+	match func(event sabot.Fields) (bool, error)
+}
+
+func (em *eventMatcher) Match(actual any) (success bool, err error) {
+
+	events, err := toEvents(actual)
+	if err != nil {
+		return
+	}
+
+	for _, event := range events {
+		success, err = em.match(event)
+		if success || err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (em *eventMatcher) FailureMessage(actual any) string {
+
+	return format.Message(describe(actual), em.desc)
+}
+
+func (em *eventMatcher) NegatedFailureMessage(actual any) string {
+
+	return format.Message(describe(actual), "not "+em.desc)
+}
+
+func toEvents(actual any) (events []sabot.Fields, err error) {
+
+	switch act := actual.(type) {
+	case *Recorder:
+		events = act.Events()
+	case []sabot.Fields:
+		events = act
+	case sabot.Fields:
+		events = []sabot.Fields{act}
+	case map[string]any:
+		events = []sabot.Fields{act}
+	default:
+		err = errors.Errorf("expected a *Recorder, []sabot.Fields, or sabot.Fields, got: %s", format.Object(actual, 1))
+	}
+
+	return
+}
+
+func describe(actual any) any {
+
+	rec, ok := actual.(*Recorder)
+	if ok {
+		return rec.Events()
+	}
+
+	return actual
+}
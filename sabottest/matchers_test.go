@@ -0,0 +1,78 @@
+package sabottest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+	"github.com/clarktrimble/sabot/sabottest"
+)
+
+func TestSabotTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SabotTest Suite")
+}
+
+var _ = Describe("Matchers", func() {
+
+	var (
+		rec *sabottest.Recorder
+	)
+
+	BeforeEach(func() {
+		rec = &sabottest.Recorder{}
+
+		lgr := rec.Logger()
+		ctx := lgr.WithFields(context.Background(), "run_id", "123")
+
+		lgr.Debug(ctx, "looking", "count", 3)
+		lgr.Error(ctx, "failed", fmt.Errorf("oops"))
+	})
+
+	Describe("HaveLogged", func() {
+		It("should match a recorded level and msg", func() {
+			Expect(rec).To(sabottest.HaveLogged("debug", "looking"))
+			Expect(rec).To(sabottest.HaveLogged("error", "failed"))
+		})
+
+		It("should not match otherwise", func() {
+			Expect(rec).ToNot(sabottest.HaveLogged("info", "looking"))
+		})
+
+		It("should describe the failure", func() {
+			matcher := sabottest.HaveLogged("info", "looking")
+			Expect(matcher.Match(rec)).To(BeFalse())
+			Expect(matcher.FailureMessage(rec)).To(ContainSubstring(`to have logged "looking" at level "info"`))
+		})
+	})
+
+	Describe("HaveField", func() {
+		It("should match a recorded field", func() {
+			Expect(rec).To(sabottest.HaveField("count", 3))
+			Expect(rec).To(sabottest.HaveField("error", ContainSubstring("oops")))
+			Expect(rec.Events()[0]).To(sabottest.HaveField("run_id", "123"))
+		})
+
+		It("should not match otherwise", func() {
+			Expect(rec).ToNot(sabottest.HaveField("count", 4))
+			Expect(rec).ToNot(sabottest.HaveField("missing", "123"))
+		})
+
+		It("should error on unsupported actual", func() {
+			_, err := sabottest.HaveField("count", 3).Match("garbage")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Reset", func() {
+		It("should discard events", func() {
+			rec.Reset()
+			Expect(rec.Events()).To(BeEmpty())
+			Expect(rec.Events()).To(Equal([]sabot.Fields{}))
+		})
+	})
+})
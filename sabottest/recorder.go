@@ -0,0 +1,67 @@
+// Package sabottest provides a Recorder and Gomega matchers for asserting on logged events.
+package sabottest
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Recorder is an io.Writer sink keeping decoded events for inspection in tests.
+type Recorder struct {
+	events []sabot.Fields
+	mu     sync.Mutex
+}
+
+// Logger returns a logger writing to the Recorder with all levels enabled.
+func (rec *Recorder) Logger() *sabot.Sabot {
+
+	return &sabot.Sabot{
+		Writer:   rec,
+		MinLevel: sabot.Trace,
+	}
+}
+
+// Write decodes and keeps one or more newline delimited events.
+func (rec *Recorder) Write(data []byte) (n int, err error) {
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+
+		fields := sabot.Fields{}
+		err = json.Unmarshal(line, &fields)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to unmarshal event: %s", line)
+			return
+		}
+
+		rec.events = append(rec.events, fields)
+	}
+
+	n = len(data)
+	return
+}
+
+// Events returns a copy of the events recorded so far.
+func (rec *Recorder) Events() []sabot.Fields {
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	return append([]sabot.Fields{}, rec.events...)
+}
+
+// Reset discards recorded events.
+func (rec *Recorder) Reset() {
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	rec.events = nil
+}
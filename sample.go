@@ -0,0 +1,128 @@
+package sabot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	sampleRateKey string = "sample_rate"
+	sampleNotice  string = "effective sample rates"
+)
+
+// Sampler keeps one in N events by msg, and is suitable for use as a Hook.
+//
+// Events at error and above, and those logged with a ctx from CaptureAll, are always kept.
+// Kept events carry a sample_rate field when N > 1 and the effective rate per msg is available,
+// so that counts derived from logs can be rescaled.
+type Sampler struct {
+	// Default is N for msgs not found in Rates, zero or one keeping all.
+	Default int
+	// Rates is N by msg.
+	Rates map[string]int
+
+	counts map[string]*sampleCount
+	mu     sync.Mutex
+}
+
+// SampleRate is the effective sampling of a msg.
+type SampleRate struct {
+	Seen int     `json:"seen"`
+	Kept int     `json:"kept"`
+	Rate float64 `json:"rate"`
+}
+
+// Apply samples an event.
+func (smp *Sampler) Apply(ctx context.Context, evt *Event) bool {
+
+	if levelOf(evt.Level) >= Error || capturing(ctx) {
+		return true
+	}
+
+	every, ok := smp.Rates[evt.Msg]
+	if !ok {
+		every = smp.Default
+	}
+
+	keep := smp.count(evt.Msg, every)
+	if keep && every > 1 {
+		evt.Fields[sampleRateKey] = every
+	}
+
+	return keep
+}
+
+// Effective returns the effective sample rates by msg since the last reset.
+func (smp *Sampler) Effective(reset bool) (rates map[string]SampleRate) {
+
+	smp.mu.Lock()
+	defer smp.mu.Unlock()
+
+	rates = map[string]SampleRate{}
+	for msg, cnt := range smp.counts {
+		rates[msg] = SampleRate{
+			Seen: cnt.seen,
+			Kept: cnt.kept,
+			Rate: float64(cnt.kept) / float64(cnt.seen),
+		}
+	}
+
+	if reset {
+		smp.counts = nil
+	}
+
+	return
+}
+
+// Run logs effective sample rates every interval until ctx is done.
+func (smp *Sampler) Run(ctx context.Context, lgr *Sabot, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rates := smp.Effective(true)
+			if len(rates) > 0 {
+				lgr.Info(ctx, sampleNotice, "rates", rates, "interval", interval)
+			}
+		}
+	}
+}
+
+//
+// unexported
+//
+
+type sampleCount struct {
+	seen int
+	kept int
+}
+
+func (smp *Sampler) count(msg string, every int) (keep bool) {
+
+	smp.mu.Lock()
+	defer smp.mu.Unlock()
+
+	if smp.counts == nil {
+		smp.counts = map[string]*sampleCount{}
+	}
+
+	cnt, ok := smp.counts[msg]
+	if !ok {
+		cnt = &sampleCount{}
+		smp.counts[msg] = cnt
+	}
+
+	keep = every < 2 || cnt.seen%every == 0
+	cnt.seen++
+	if keep {
+		cnt.kept++
+	}
+
+	return
+}
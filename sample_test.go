@@ -0,0 +1,66 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sampler", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		smp *Sampler
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		smp = &Sampler{
+			Rates: map[string]int{"chatty": 4},
+		}
+		lgr = &Sabot{Writer: buf, Hooks: []Hook{smp.Apply}}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 8; i++ {
+			lgr.Info(ctx, "chatty")
+			lgr.Info(ctx, "quiet")
+		}
+		lgr.Error(ctx, "chatty", fmt.Errorf("oops"))
+	})
+
+	When("sampling by msg", func() {
+		It("should keep one in n, noting the rate", func() {
+			Expect(strings.Count(buf.String(), `"msg":"chatty","sample_rate":4`)).To(Equal(2))
+			Expect(strings.Count(buf.String(), `"msg":"quiet"`)).To(Equal(8))
+			Expect(strings.Count(buf.String(), `"level":"error"`)).To(Equal(1))
+		})
+
+		It("should report effective rates", func() {
+			Expect(smp.Effective(true)).To(Equal(map[string]SampleRate{
+				"chatty": {Seen: 8, Kept: 2, Rate: 0.25},
+				"quiet":  {Seen: 8, Kept: 8, Rate: 1},
+			}))
+			Expect(smp.Effective(false)).To(BeEmpty())
+		})
+
+		It("should log effective rates periodically", func() {
+			buf.Reset()
+
+			runCtx, cancel := context.WithTimeout(ctx, 15*time.Millisecond)
+			defer cancel()
+			smp.Run(runCtx, &Sabot{Writer: buf}, 10*time.Millisecond)
+
+			Expect(buf.String()).To(ContainSubstring(`"msg":"effective sample rates"`))
+			Expect(buf.String()).To(ContainSubstring(`\"chatty\":{\"seen\":8,\"kept\":2,\"rate\":0.25}`))
+		})
+	})
+})
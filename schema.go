@@ -0,0 +1,66 @@
+package sabot
+
+import (
+	"github.com/pkg/errors"
+)
+
+const schemaKey string = "schema"
+
+// Upgrade migrates an event from schema version From to From+1,
+// so that archives remain parseable as field conventions evolve.
+type Upgrade struct {
+	From  int
+	Apply func(evt *Event) error
+}
+
+// Migrate applies upgrades in turn, starting from the event's schema version, zero when not stamped.
+func Migrate(evt *Event, upgrades []Upgrade) (err error) {
+
+	if len(upgrades) == 0 {
+		return
+	}
+
+	byFrom := map[int]Upgrade{}
+	for _, upgrade := range upgrades {
+		byFrom[upgrade.From] = upgrade
+	}
+
+	version := schemaOf(evt)
+	for {
+		upgrade, ok := byFrom[version]
+		if !ok {
+			return
+		}
+
+		err = upgrade.Apply(evt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upgrade from schema %d", version)
+		}
+
+		version++
+		evt.Fields[schemaKey] = version
+	}
+}
+
+//
+// unexported
+//
+
+func (sabot *Sabot) stamp(fields Fields) {
+
+	if sabot.Schema > 0 {
+		fields[schemaKey] = sabot.Schema
+	}
+}
+
+func schemaOf(evt *Event) int {
+
+	switch version := evt.Fields[schemaKey].(type) {
+	case int:
+		return version
+	case float64:
+		return int(version)
+	}
+
+	return 0
+}
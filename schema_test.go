@@ -0,0 +1,82 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schema", func() {
+
+	var (
+		buf      *bytes.Buffer
+		upgrades []Upgrade
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+
+		// v0 called it "user", v1 "user_id", and v2 nests under "user."
+		upgrades = []Upgrade{
+			{From: 0, Apply: func(evt *Event) error {
+				evt.Fields["user_id"] = evt.Fields["user"]
+				delete(evt.Fields, "user")
+				return nil
+			}},
+			{From: 1, Apply: func(evt *Event) error {
+				evt.Fields["user.id"] = evt.Fields["user_id"]
+				delete(evt.Fields, "user_id")
+				return nil
+			}},
+		}
+	})
+
+	When("schema is enabled", func() {
+		It("should stamp events, taking precedence over kv", func() {
+			lgr := (&Config{Schema: 2}).New(buf)
+			lgr.Info(context.Background(), "stamped", "schema", 7)
+
+			Expect(delog(buf)["schema"]).To(Equal(float64(2)))
+		})
+	})
+
+	When("schema is not enabled", func() {
+		It("should not stamp", func() {
+			lgr := &Sabot{Writer: buf}
+			lgr.Info(context.Background(), "unstamped")
+
+			Expect(delog(buf)).ToNot(HaveKey("schema"))
+		})
+	})
+
+	When("decoding an archive of mixed versions", func() {
+		It("should upgrade each to current", func() {
+			(&Sabot{Writer: buf}).Info(context.Background(), "old", "user", "u1")
+			(&Sabot{Writer: buf, Schema: 1}).Info(context.Background(), "newer", "user_id", "u2")
+			(&Sabot{Writer: buf, Schema: 2}).Info(context.Background(), "current", "user.id", "u3")
+
+			users := []any{}
+			for evt, err := range Decode(buf, upgrades...) {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(schemaOf(&evt)).To(Equal(2))
+				users = append(users, evt.Fields["user.id"])
+			}
+			Expect(users).To(Equal([]any{"u1", "u2", "u3"}))
+		})
+	})
+
+	When("an upgrade fails", func() {
+		It("should yield a decode error", func() {
+			(&Sabot{Writer: buf}).Info(context.Background(), "old")
+			upgrades[0].Apply = func(evt *Event) error { return errors.Errorf("nope") }
+
+			for _, err := range Decode(buf, upgrades...) {
+				Expect(err).To(MatchError("line 1: failed to upgrade from schema 0: nope"))
+			}
+		})
+	})
+})
@@ -0,0 +1,158 @@
+package sabot
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// secretTag marks struct fields whose values are redacted when logged,
+// as with `secret:"true"`.
+const secretTag = "secret"
+
+// secretPaths caches json key paths to secret fields by type.
+var secretPaths sync.Map
+
+// redactSecrets replaces secret field values in data, the json encoding of obj.
+func redactSecrets(obj any, data []byte) []byte {
+
+	paths := pathsFor(reflect.TypeOf(obj))
+	if len(paths) == 0 {
+		return data
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var tree any
+	err := decoder.Decode(&tree)
+	if err != nil {
+		return data
+	}
+
+	for _, path := range paths {
+		tree = redactPath(tree, path)
+	}
+
+	redacted, err := json.Marshal(tree)
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func pathsFor(typ reflect.Type) [][]string {
+
+	if typ == nil {
+		return nil
+	}
+
+	cached, ok := secretPaths.Load(typ)
+	if ok {
+		return cached.([][]string) //nolint: forcetypeassert
+	}
+
+	paths := findSecrets(typ, nil, map[reflect.Type]bool{})
+	secretPaths.Store(typ, paths)
+
+	return paths
+}
+
+func findSecrets(typ reflect.Type, prefix []string, seen map[reflect.Type]bool) (paths [][]string) {
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return findSecrets(typ.Elem(), prefix, seen)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return findSecrets(typ.Elem(), append(prefix, "*"), seen)
+	case reflect.Struct:
+	default:
+		return
+	}
+
+	// guard against recursive types
+
+	if seen[typ] {
+		return
+	}
+	seen[typ] = true
+	defer delete(seen, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		// untagged embedded structs have their fields promoted
+
+		if field.Anonymous && name == "" {
+			paths = append(paths, findSecrets(field.Type, prefix, seen)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		path := append(append([]string{}, prefix...), name)
+		if field.Tag.Get(secretTag) == "true" {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, findSecrets(field.Type, path, seen)...)
+	}
+
+	return
+}
+
+func jsonName(field reflect.StructField) (name string, ok bool) {
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return
+	}
+
+	name, _, _ = strings.Cut(tag, ",")
+	ok = true
+	return
+}
+
+func redactPath(tree any, path []string) any {
+
+	if len(path) == 0 {
+		return redactedNotice
+	}
+
+	switch node := tree.(type) {
+	case map[string]any:
+		if path[0] == "*" {
+			for key, val := range node {
+				node[key] = redactPath(val, path[1:])
+			}
+			return node
+		}
+
+		// absent as with omitempty
+
+		val, ok := node[path[0]]
+		if ok {
+			node[path[0]] = redactPath(val, path[1:])
+		}
+	case []any:
+		if path[0] == "*" {
+			for i, val := range node {
+				node[i] = redactPath(val, path[1:])
+			}
+		}
+	}
+
+	return tree
+}
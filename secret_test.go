@@ -0,0 +1,81 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type dbConfig struct {
+	Host     string `json:"host"`
+	Password string `json:"password" secret:"true"`
+}
+
+type appConfig struct {
+	Name    string            `json:"name"`
+	Token   string            `json:"token,omitempty" secret:"true"`
+	Db      *dbConfig         `json:"db"`
+	Replica []dbConfig        `json:"replica"`
+	Extra   map[string]string `json:"extra"`
+}
+
+var _ = Describe("Secret", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+		cfg any
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		lgr.Info(ctx, "configured", "config", cfg)
+	})
+
+	When("config has secrets at several depths", func() {
+		BeforeEach(func() {
+			cfg = &appConfig{
+				Name:    "app",
+				Token:   "t0ken",
+				Db:      &dbConfig{Host: "db1", Password: "hunter2"},
+				Replica: []dbConfig{{Host: "db2", Password: "hunter3"}},
+			}
+		})
+
+		It("should redact them", func() {
+			Expect(delog(buf)["config"]).To(Equal(
+				`{"db":{"host":"db1","password":"--redacted--"},"extra":null,"name":"app",` +
+					`"replica":[{"host":"db2","password":"--redacted--"}],"token":"--redacted--"}`,
+			))
+		})
+	})
+
+	When("a secret is omitted", func() {
+		BeforeEach(func() {
+			cfg = appConfig{Name: "app"}
+		})
+
+		It("should leave it out", func() {
+			Expect(delog(buf)["config"]).To(Equal(`{"db":null,"extra":null,"name":"app","replica":null}`))
+		})
+	})
+
+	When("logging the logger's own config", func() {
+		BeforeEach(func() {
+			cfg = &Config{Redact: Redaction{Hash: []string{"email"}, Salt: "pepper"}}
+		})
+
+		It("should redact the salt", func() {
+			Expect(delog(buf)["config"]).To(ContainSubstring(`"salt":"--redacted--"`))
+			Expect(delog(buf)["config"]).ToNot(ContainSubstring("pepper"))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package sink
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// DirStore is a sabot.BlobStore keeping blobs as files in a local dir.
+type DirStore struct {
+	// Dir is where blobs are written.
+	Dir string
+}
+
+// Put writes data to a file named for it, returning a file url.
+func (ds *DirStore) Put(ctx context.Context, name string, data []byte) (ref string, err error) {
+
+	path, err := filepath.Abs(filepath.Join(ds.Dir, filepath.Base(name)))
+	if err != nil {
+		err = errors.Wrapf(err, "failed to resolve blob path")
+		return
+	}
+
+	// content named blobs are written once
+
+	_, err = os.Stat(path)
+	if os.IsNotExist(err) {
+		err = os.WriteFile(path, data, 0o644)
+	}
+	if err != nil {
+		err = errors.Wrapf(err, "failed to write blob")
+		return
+	}
+
+	ref = (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+	return
+}
@@ -0,0 +1,28 @@
+package sink
+
+import (
+	"context"
+	"net/url"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DirStore", func() {
+
+	It("should write blobs and return file urls", func() {
+		ds := &DirStore{Dir: GinkgoT().TempDir()}
+
+		ref, err := ds.Put(context.Background(), "abc123", []byte("payload"))
+		Expect(err).ToNot(HaveOccurred())
+
+		parsed, err := url.Parse(ref)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Scheme).To(Equal("file"))
+
+		data, err := os.ReadFile(parsed.Path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("payload"))
+	})
+})
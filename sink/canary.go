@@ -0,0 +1,106 @@
+// Package sink implements io.Writer sinks and wrappers for use with Sabot.
+package sink
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Canary writes to a primary and a shadow writer, keeping stats for comparing the two.
+//
+// Results from the primary are returned, while those of the shadow are only counted,
+// so that a new sink can be validated against a trusted one before cutover.
+// Writes are made serially, primary first.
+type Canary struct {
+	Primary io.Writer
+	Shadow  io.Writer
+
+	primary Stats
+	shadow  Stats
+	dropped int
+	mu      sync.Mutex
+}
+
+// Stats are counts of writes made to a writer.
+type Stats struct {
+	// Writes is the count of writes attempted.
+	Writes int `json:"writes"`
+	// Errors is the count of writes returning an error.
+	Errors int `json:"errors"`
+	// Bytes is the count of bytes written successfully.
+	Bytes int64 `json:"bytes"`
+	// Latency is the total time spent writing.
+	Latency time.Duration `json:"latency"`
+}
+
+// Comparison compares the primary and shadow writers.
+type Comparison struct {
+	Primary Stats `json:"primary"`
+	Shadow  Stats `json:"shadow"`
+	// Dropped is the count of writes succeeding in primary but not in shadow.
+	Dropped int `json:"dropped"`
+	// BytesDiff is shadow less primary bytes written.
+	BytesDiff int64 `json:"bytes_diff"`
+	// MeanLatencyDiff is shadow less primary mean latency.
+	MeanLatencyDiff time.Duration `json:"mean_latency_diff"`
+}
+
+// Write writes to primary and then shadow.
+func (can *Canary) Write(data []byte) (n int, err error) {
+
+	n, err = record(&can.primary, &can.mu, can.Primary, data)
+	_, shadowErr := record(&can.shadow, &can.mu, can.Shadow, data)
+
+	if err == nil && shadowErr != nil {
+		can.mu.Lock()
+		can.dropped++
+		can.mu.Unlock()
+	}
+
+	return
+}
+
+// Report returns a comparison of primary and shadow so far.
+func (can *Canary) Report() (rpt Comparison) {
+
+	can.mu.Lock()
+	defer can.mu.Unlock()
+
+	rpt = Comparison{
+		Primary:   can.primary,
+		Shadow:    can.shadow,
+		Dropped:   can.dropped,
+		BytesDiff: can.shadow.Bytes - can.primary.Bytes,
+	}
+
+	if can.primary.Writes > 0 && can.shadow.Writes > 0 {
+		rpt.MeanLatencyDiff = can.shadow.Latency/time.Duration(can.shadow.Writes) -
+			can.primary.Latency/time.Duration(can.primary.Writes)
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+func record(stats *Stats, mu *sync.Mutex, writer io.Writer, data []byte) (n int, err error) {
+
+	start := time.Now()
+	n, err = writer.Write(data)
+	latency := time.Since(start)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	stats.Writes++
+	stats.Latency += latency
+	stats.Bytes += int64(n)
+	if err != nil {
+		stats.Errors++
+	}
+
+	return
+}
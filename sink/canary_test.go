@@ -0,0 +1,82 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSink(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Sink Suite")
+}
+
+var _ = Describe("Canary", func() {
+
+	var (
+		primary *bytes.Buffer
+		shadow  *flakyWriter
+		can     *Canary
+	)
+
+	BeforeEach(func() {
+		primary = &bytes.Buffer{}
+		shadow = &flakyWriter{every: 2}
+		can = &Canary{Primary: primary, Shadow: shadow}
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 4; i++ {
+			_, err := fmt.Fprintf(can, `{"msg":"canary","idx":%d}`+"\n", i)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	When("shadow drops some writes", func() {
+		It("should report the divergence", func() {
+			rpt := can.Report()
+			Expect(rpt.Primary.Writes).To(Equal(4))
+			Expect(rpt.Primary.Errors).To(Equal(0))
+			Expect(rpt.Shadow.Writes).To(Equal(4))
+			Expect(rpt.Shadow.Errors).To(Equal(2))
+			Expect(rpt.Dropped).To(Equal(2))
+			Expect(rpt.Primary.Bytes).To(BeNumerically("==", 4*25))
+			Expect(rpt.BytesDiff).To(BeNumerically("==", -2*25))
+			Expect(primary.Len()).To(BeNumerically("==", rpt.Primary.Bytes))
+		})
+	})
+
+	When("primary fails too", func() {
+		It("should count only writes dropped by shadow", func() {
+			can = &Canary{Primary: &flakyWriter{every: 2}, Shadow: &flakyWriter{every: 3}}
+			for i := 0; i < 4; i++ {
+				_, _ = fmt.Fprintf(can, `{"msg":"canary","idx":%d}`+"\n", i)
+			}
+
+			rpt := can.Report()
+			Expect(rpt.Primary.Errors).To(Equal(2))
+			Expect(rpt.Shadow.Errors).To(Equal(1))
+			Expect(rpt.Dropped).To(Equal(1))
+		})
+	})
+})
+
+type flakyWriter struct {
+	every int
+	count int
+	buf   bytes.Buffer
+}
+
+func (fw *flakyWriter) Write(data []byte) (n int, err error) {
+
+	fw.count++
+	if fw.count%fw.every == 0 {
+		err = fmt.Errorf("oops")
+		return
+	}
+
+	return fw.buf.Write(data)
+}
@@ -0,0 +1,98 @@
+package sink
+
+import (
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Durability levels of File, trading fsync IOPS for what survives a crash.
+const (
+	// DurableNone leaves syncing to the os.
+	DurableNone = "none"
+	// DurableInterval syncs writes in groups, once per interval.
+	DurableInterval = "interval"
+	// DurableLine syncs each write.
+	DurableLine = "line"
+)
+
+const defaultSyncInterval = time.Second
+
+//
+// unexported
+//
+
+func (fl *File) durable() bool {
+
+	return fl.durability == DurableInterval || fl.durability == DurableLine
+}
+
+// sync syncs the active file if written to since the last, mutex held.
+func (fl *File) sync() error {
+
+	if !fl.dirty {
+		return nil
+	}
+	fl.dirty = false
+
+	return errors.Wrapf(fl.file.Sync(), "failed to sync log file")
+}
+
+func (fl *File) startSync(interval, jitter time.Duration) {
+
+	if interval <= 0 {
+		interval = defaultSyncInterval
+	}
+
+	fl.stop = make(chan struct{})
+	fl.done = make(chan struct{})
+
+	go fl.syncLoop(interval, jitter)
+}
+
+func (fl *File) syncLoop(interval, jitter time.Duration) {
+
+	defer close(fl.done)
+
+	for {
+		// jitter keeps hosts started together from syncing in lockstep
+
+		wait := interval
+		if jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(jitter))) //nolint: gosec
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-fl.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			fl.groupSync()
+		}
+	}
+}
+
+// groupSync syncs all writes since the last in one go, without holding up writers.
+func (fl *File) groupSync() {
+
+	fl.mu.Lock()
+	if !fl.dirty {
+		fl.mu.Unlock()
+		return
+	}
+	file := fl.file
+	fl.dirty = false
+	fl.mu.Unlock()
+
+	// rotation syncs before closing, so a closed file has nothing pending
+
+	err := file.Sync()
+	if err != nil && !errors.Is(err, os.ErrClosed) {
+		fl.mu.Lock()
+		fl.syncErr = errors.Wrapf(err, "failed to sync log file")
+		fl.mu.Unlock()
+	}
+}
@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Durable", func() {
+
+	var (
+		cfg *FileConfig
+		fl  *File
+		err error
+	)
+
+	BeforeEach(func() {
+		cfg = &FileConfig{Dir: GinkgoT().TempDir(), Name: "app"}
+	})
+
+	JustBeforeEach(func() {
+		fl, err = cfg.New()
+	})
+
+	AfterEach(func() {
+		if fl != nil && err == nil {
+			Expect(fl.Close()).To(Succeed())
+		}
+	})
+
+	When("durability is line", func() {
+		BeforeEach(func() {
+			cfg.Durability = DurableLine
+		})
+
+		It("should sync each write", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err := fmt.Fprintf(fl, `{"msg":"synced"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fl.dirty).To(BeFalse())
+		})
+	})
+
+	When("durability is interval", func() {
+		BeforeEach(func() {
+			cfg.Durability = DurableInterval
+			cfg.SyncInterval = 10 * time.Millisecond
+			cfg.SyncJitter = 5 * time.Millisecond
+		})
+
+		It("should sync writes as a group", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			for i := 0; i < 3; i++ {
+				_, err := fmt.Fprintf(fl, `{"msg":"grouped","idx":%d}`+"\n", i)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Eventually(func() bool {
+				fl.mu.Lock()
+				defer fl.mu.Unlock()
+				return fl.dirty
+			}).Should(BeFalse())
+		})
+	})
+
+	When("durability is none", func() {
+		It("should leave syncing to the os", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err := fmt.Fprintf(fl, `{"msg":"unsynced"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fl.dirty).To(BeTrue())
+			Expect(fl.stop).To(BeNil())
+		})
+	})
+
+	When("durability is unknown", func() {
+		BeforeEach(func() {
+			cfg.Durability = "sometimes"
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError(ContainSubstring("unknown durability")))
+		})
+	})
+})
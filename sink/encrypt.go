@@ -0,0 +1,170 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// KeyProvider looks up encryption keys by id, as from a KMS.
+type KeyProvider interface {
+	Key(id string) (key []byte, err error)
+}
+
+// StaticKeys is a KeyProvider of keys held in memory.
+type StaticKeys map[string][]byte
+
+// Key returns the key for id.
+func (sk StaticKeys) Key(id string) (key []byte, err error) {
+
+	key, ok := sk[id]
+	if !ok {
+		err = errors.Errorf("no key for id: %s", id)
+	}
+	return
+}
+
+// EncryptConfig is the configurable fields of Encrypter.
+type EncryptConfig struct {
+	KeyId string `json:"key_id" desc:"id of encryption key, recorded with each record"`
+	Key   string `json:"key" desc:"hex aes key of 16, 24, or 32 bytes, used when no key provider is given" secret:"true"`
+}
+
+// New creates an Encrypter writing to writer, with key from provider when not nil.
+func (cfg *EncryptConfig) New(writer io.Writer, provider KeyProvider) (enc *Encrypter, err error) {
+
+	if provider == nil {
+		key, dErr := hex.DecodeString(cfg.Key)
+		if dErr != nil {
+			err = errors.Wrapf(dErr, "failed to decode encryption key")
+			return
+		}
+		provider = StaticKeys{cfg.KeyId: key}
+	}
+
+	aead, err := newAead(provider, cfg.KeyId)
+	if err != nil {
+		return
+	}
+
+	enc = &Encrypter{
+		Writer: writer,
+		keyId:  cfg.KeyId,
+		aead:   aead,
+	}
+	return
+}
+
+// Encrypter is an io.Writer encrypting each write with AES-GCM.
+//
+// Records are written one per line as "<key id>:<base64 nonce and ciphertext>",
+// so that line oriented sinks, such as File, rotate and seal them as usual.
+type Encrypter struct {
+	Writer io.Writer
+	keyId  string
+	aead   cipher.AEAD
+}
+
+// Write encrypts data and writes it as a single record.
+func (enc *Encrypter) Write(data []byte) (n int, err error) {
+
+	sealed, err := seal(enc.aead, data, enc.keyId)
+	if err != nil {
+		return
+	}
+
+	record := enc.keyId + ":" + sealed + "\n"
+
+	_, err = io.WriteString(enc.Writer, record)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to write encrypted record")
+		return
+	}
+
+	n = len(data)
+	return
+}
+
+// Decrypt reads records written by Encrypter from src, writing plaintext to dst.
+func Decrypt(dst io.Writer, src io.Reader, provider KeyProvider) (err error) {
+
+	aeads := map[string]cipher.AEAD{}
+
+	scanner := bufio.NewScanner(src)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		keyId, encoded, ok := bytes.Cut(scanner.Bytes(), []byte(":"))
+		if !ok {
+			return errors.Errorf("malformed encrypted record")
+		}
+
+		aead, ok := aeads[string(keyId)]
+		if !ok {
+			aead, err = newAead(provider, string(keyId))
+			if err != nil {
+				return
+			}
+			aeads[string(keyId)] = aead
+		}
+
+		sealed, dErr := base64.StdEncoding.DecodeString(string(encoded))
+		if dErr != nil || len(sealed) < aead.NonceSize() {
+			return errors.Errorf("malformed encrypted record")
+		}
+
+		size := aead.NonceSize()
+		data, oErr := aead.Open(nil, sealed[:size], sealed[size:], keyId)
+		if oErr != nil {
+			return errors.Wrapf(oErr, "failed to decrypt record")
+		}
+
+		_, err = dst.Write(data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to write decrypted record")
+		}
+	}
+
+	return errors.Wrapf(scanner.Err(), "failed to read encrypted records")
+}
+
+//
+// unexported
+//
+
+func newAead(provider KeyProvider, keyId string) (aead cipher.AEAD, err error) {
+
+	// key id is recorded ahead of a colon, so cannot have one itself
+
+	if strings.Contains(keyId, ":") {
+		err = errors.Errorf("key id cannot contain a colon: %s", keyId)
+		return
+	}
+
+	key, err := provider.Key(keyId)
+	if err != nil {
+		return
+	}
+
+	return aeadFor(key)
+}
+
+func aeadFor(key []byte) (aead cipher.AEAD, err error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to create cipher")
+		return
+	}
+
+	aead, err = cipher.NewGCM(block)
+	err = errors.Wrapf(err, "failed to create gcm")
+	return
+}
@@ -0,0 +1,64 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Encrypter", func() {
+
+	var (
+		key []byte
+		buf *bytes.Buffer
+		enc *Encrypter
+	)
+
+	BeforeEach(func() {
+		key = bytes.Repeat([]byte{7}, 32)
+		buf = &bytes.Buffer{}
+
+		cfg := &EncryptConfig{KeyId: "k1", Key: hex.EncodeToString(key)}
+
+		var err error
+		enc, err = cfg.New(buf, nil)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 2; i++ {
+			_, err := fmt.Fprintf(enc, `{"msg":"secret","idx":%d}`+"\n", i)
+			Expect(err).ToNot(HaveOccurred())
+		}
+	})
+
+	When("records are decrypted with the key", func() {
+		It("should recover the plaintext", func() {
+			Expect(buf.String()).ToNot(ContainSubstring("secret"))
+			Expect(bytes.Count(buf.Bytes(), []byte("\n"))).To(Equal(2))
+
+			out := &bytes.Buffer{}
+			err := Decrypt(out, buf, StaticKeys{"k1": key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out.String()).To(Equal(
+				`{"msg":"secret","idx":0}` + "\n" + `{"msg":"secret","idx":1}` + "\n",
+			))
+		})
+	})
+
+	When("the key is unknown", func() {
+		It("should fail to decrypt", func() {
+			err := Decrypt(&bytes.Buffer{}, buf, StaticKeys{})
+			Expect(err).To(MatchError(ContainSubstring("no key for id: k1")))
+		})
+	})
+
+	It("should refuse a key id with a colon", func() {
+		cfg := &EncryptConfig{KeyId: "kms:k1", Key: hex.EncodeToString(key)}
+		_, err := cfg.New(buf, nil)
+		Expect(err).To(MatchError("key id cannot contain a colon: kms:k1"))
+	})
+})
@@ -0,0 +1,249 @@
+package sink
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const (
+	// EnvelopeKey is the field holding the id of the key encrypting an event's data key, the wrapped data key,
+	// and the keys of fields encrypted.
+	EnvelopeKey string = "enc_key"
+	// encPrefix marks encrypted values.
+	encPrefix  string = "enc:"
+	dataKeyLen int    = 32
+)
+
+// FieldEncryptConfig is the configurable fields of FieldEncrypter.
+type FieldEncryptConfig struct {
+	Keys  []string `json:"keys" desc:"field keys whose values are encrypted"`
+	KeyId string   `json:"key_id" desc:"id of key encrypting data keys, recorded with each event"`
+	Key   string   `json:"key" desc:"hex aes key of 16, 24, or 32 bytes, used when no key provider is given" secret:"true"`
+}
+
+// New creates a FieldEncrypter, with key from provider when not nil.
+func (cfg *FieldEncryptConfig) New(provider KeyProvider) (fe *FieldEncrypter, err error) {
+
+	// keys are recorded in the envelope separated by commas, so cannot have one themselves
+
+	for _, key := range cfg.Keys {
+		if strings.Contains(key, ",") {
+			err = errors.Errorf("field key cannot contain a comma: %s", key)
+			return
+		}
+	}
+
+	if provider == nil {
+		key, dErr := hex.DecodeString(cfg.Key)
+		if dErr != nil {
+			err = errors.Wrapf(dErr, "failed to decode encryption key")
+			return
+		}
+		provider = StaticKeys{cfg.KeyId: key}
+	}
+
+	aead, err := newAead(provider, cfg.KeyId)
+	if err != nil {
+		return
+	}
+
+	fe = &FieldEncrypter{
+		Keys:  cfg.Keys,
+		keyId: cfg.KeyId,
+		aead:  aead,
+	}
+	return
+}
+
+// FieldEncrypter encrypts the values of designated field keys, rather than redacting them,
+// so that those authorized can recover PII while the default pipeline stays safe.
+//
+// Encryption is by envelope: each event gets a random data key, encrypted with the key of KeyId
+// and recorded with its id as the enc_key field, along with the keys encrypted.  Values are replaced with "enc:"
+// and the base64 nonce and ciphertext of their json, which DecryptFields reverses.
+// Encrypted values and the envelope are exempt from truncation, as a prefix cannot be decrypted.
+type FieldEncrypter struct {
+	// Keys are field keys whose values are encrypted.
+	Keys  []string
+	keyId string
+	aead  cipher.AEAD
+}
+
+// Apply encrypts an event's designated fields, and is suitable for use as a Hook.
+//
+// Should encryption fail, values are redacted instead, so that plaintext never leaks.
+func (fe *FieldEncrypter) Apply(ctx context.Context, evt *sabot.Event) bool {
+
+	found := false
+	for _, key := range fe.Keys {
+		_, ok := evt.Fields[key]
+		found = found || ok
+	}
+	if !found {
+		return true
+	}
+
+	err := fe.encrypt(evt)
+	if err != nil {
+		(&sabot.Redaction{Keys: fe.Keys}).Apply(ctx, evt)
+		evt.Fields["logerror"] = err.Error()
+	}
+
+	return true
+}
+
+// DecryptFields decrypts values encrypted by FieldEncrypter, with keys from provider.
+func DecryptFields(evt *sabot.Event, provider KeyProvider) (err error) {
+
+	envelope, ok := evt.Fields[EnvelopeKey].(string)
+	if !ok {
+		return
+	}
+
+	parts := strings.SplitN(envelope, ":", 3)
+	if len(parts) != 3 {
+		return errors.Errorf("malformed envelope: %s", envelope)
+	}
+	keyId, wrapped, keys := parts[0], parts[1], strings.Split(parts[2], ",")
+
+	kek, err := newAead(provider, keyId)
+	if err != nil {
+		return
+	}
+
+	dataKey, err := open(kek, wrapped, keyId)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unwrap data key")
+	}
+
+	aead, err := aeadFor(dataKey)
+	if err != nil {
+		return
+	}
+
+	// decrypt only fields recorded as encrypted, others may happen to look the part
+
+	for _, key := range keys {
+		val, ok := evt.Fields[key]
+		if !ok {
+			continue
+		}
+
+		str, ok := val.(string)
+		if !ok || !strings.HasPrefix(str, encPrefix) {
+			return errors.Errorf("field not encrypted: %s", key)
+		}
+
+		data, oErr := open(aead, strings.TrimPrefix(str, encPrefix), key)
+		if oErr != nil {
+			return errors.Wrapf(oErr, "failed to decrypt field: %s", key)
+		}
+
+		var plain any
+		err = json.Unmarshal(data, &plain)
+		if err != nil {
+			return errors.Wrapf(err, "failed to unmarshal field: %s", key)
+		}
+		evt.Fields[key] = plain
+	}
+
+	delete(evt.Fields, EnvelopeKey)
+	return
+}
+
+//
+// unexported
+//
+
+func (fe *FieldEncrypter) encrypt(evt *sabot.Event) (err error) {
+
+	dataKey := make([]byte, dataKeyLen)
+	_, err = rand.Read(dataKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to generate data key")
+	}
+
+	aead, err := aeadFor(dataKey)
+	if err != nil {
+		return
+	}
+
+	// seal all before replacing any, so that failure leaves the event as it was for redaction
+
+	sealed := map[string]string{}
+	for _, key := range fe.Keys {
+		val, ok := evt.Fields[key]
+		if !ok {
+			continue
+		}
+
+		data, mErr := json.Marshal(val)
+		if mErr != nil {
+			return errors.Wrapf(mErr, "failed to marshal field: %s", key)
+		}
+
+		sealed[key], err = seal(aead, data, key)
+		if err != nil {
+			return
+		}
+	}
+
+	wrapped, err := seal(fe.aead, dataKey, fe.keyId)
+	if err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(sealed))
+	for key, val := range sealed {
+		evt.Fields[key] = wholeValue(encPrefix + val)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	evt.Fields[EnvelopeKey] = wholeValue(fe.keyId + ":" + wrapped + ":" + strings.Join(keys, ","))
+
+	return
+}
+
+// seal encrypts data bound to ad, returning base64 of nonce and ciphertext.
+func seal(aead cipher.AEAD, data []byte, ad string) (encoded string, err error) {
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to generate nonce")
+		return
+	}
+
+	sealed := aead.Seal(nonce, nonce, data, []byte(ad))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// wholeValue returns str exempt from truncation.
+func wholeValue(str string) any {
+
+	return sabot.NoTruncate("", str).Val
+}
+
+func open(aead cipher.AEAD, encoded, ad string) (data []byte, err error) {
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, errors.Errorf("malformed sealed value")
+	}
+
+	size := aead.NonceSize()
+	data, err = aead.Open(nil, sealed[:size], sealed[size:], []byte(ad))
+	err = errors.Wrapf(err, "failed to open sealed value")
+	return
+}
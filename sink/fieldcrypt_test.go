@@ -0,0 +1,100 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+var _ = Describe("FieldEncrypter", func() {
+
+	var (
+		key []byte
+		buf *bytes.Buffer
+		lgr *sabot.Sabot
+	)
+
+	BeforeEach(func() {
+		key = bytes.Repeat([]byte{7}, 32)
+		buf = &bytes.Buffer{}
+
+		cfg := &FieldEncryptConfig{Keys: []string{"email", "ssn"}, KeyId: "k1", Key: hex.EncodeToString(key)}
+		fe, err := cfg.New(nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		lgr = &sabot.Sabot{Writer: buf, Hooks: []sabot.Hook{fe.Apply}}
+	})
+
+	When("an event has designated fields", func() {
+		BeforeEach(func() {
+			lgr.Info(context.Background(), "signed up", "email", "bob@example.com", "ssn", 123456789, "plan", "pro")
+		})
+
+		It("should encrypt them, recoverable with the key", func() {
+			Expect(buf.String()).ToNot(ContainSubstring("bob@example.com"))
+			Expect(buf.String()).To(ContainSubstring(`"enc_key":"k1:`))
+			Expect(buf.String()).To(ContainSubstring(`"plan":"pro"`))
+
+			evt, err := sabot.DecodeEvent(buf.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+
+			err = DecryptFields(evt, StaticKeys{"k1": key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evt.Fields).To(Equal(sabot.Fields{
+				"email": "bob@example.com",
+				"ssn":   float64(123456789),
+				"plan":  "pro",
+			}))
+		})
+
+		It("should not decrypt with another key", func() {
+			evt, err := sabot.DecodeEvent(buf.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+
+			err = DecryptFields(evt, StaticKeys{"k1": bytes.Repeat([]byte{8}, 32)})
+			Expect(err).To(MatchError(ContainSubstring("failed to unwrap data key")))
+		})
+	})
+
+	When("values are truncated", func() {
+		BeforeEach(func() {
+			lgr.MaxLen = 30
+			lgr.Info(context.Background(), "signed up", "email", "bob@example.com", "note", "enc:abc")
+		})
+
+		It("should still decrypt, leaving lookalike plaintext be", func() {
+			Expect(buf.String()).To(ContainSubstring(`"enc_key":"k1:`))
+			Expect(buf.String()).To(ContainSubstring(`:email"`))
+
+			evt, err := sabot.DecodeEvent(buf.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+
+			err = DecryptFields(evt, StaticKeys{"k1": key})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evt.Fields).To(Equal(sabot.Fields{
+				"email": "bob@example.com",
+				"note":  "enc:abc",
+			}))
+		})
+	})
+
+	When("an event has no designated fields", func() {
+		BeforeEach(func() {
+			lgr.Info(context.Background(), "browsing", "plan", "pro")
+		})
+
+		It("should leave it be", func() {
+			Expect(buf.String()).ToNot(ContainSubstring("enc_key"))
+		})
+	})
+
+	It("should refuse a key id with a colon", func() {
+		_, err := (&FieldEncryptConfig{KeyId: "kms:k1", Key: hex.EncodeToString(key)}).New(nil)
+		Expect(err).To(MatchError("key id cannot contain a colon: kms:k1"))
+	})
+})
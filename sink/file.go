@@ -0,0 +1,354 @@
+package sink
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const dayLayout = "2006-01-02"
+
+// FileConfig is the configurable fields of File.
+type FileConfig struct {
+	Dir          string        `json:"dir" desc:"directory log files are written in"`
+	Name         string        `json:"name" desc:"base name of log files, suffixed with the date on rotation"`
+	SealKey      string        `json:"seal_key" desc:"hex ed25519 seed for signing daily manifests, sealing disabled when empty" secret:"true"`
+	MaxAge       time.Duration `json:"max_age" desc:"age past which rotated files are purged, zero keeps indefinitely"`
+	MaxTotal     int64         `json:"max_total" desc:"total bytes of rotated files past which the oldest are purged, zero for no limit"`
+	Durability   string        `json:"durability" desc:"when writes are fsynced: none, interval, or line, defaulting to none"`
+	SyncInterval time.Duration `json:"sync_interval" desc:"how often writes are fsynced for interval durability, defaulting to one second"`
+	SyncJitter   time.Duration `json:"sync_jitter" desc:"random delay of up to which is added to each sync interval, spreading fsyncs across hosts"`
+	MaxSize      int64         `json:"max_size" desc:"bytes past which the active file is rotated, zero to rotate daily only"`
+	Shared       bool          `json:"shared" desc:"coordinate writes and rotation via a lock file, for processes sharing the active file"`
+	External     bool          `json:"external" desc:"leave rotation to an external tool such as logrotate, reopening the active file on Reopen"`
+}
+
+// New creates a File from FileConfig, opening or creating the active file.
+func (cfg *FileConfig) New() (fl *File, err error) {
+
+	fl = &File{
+		dir:        cfg.Dir,
+		name:       cfg.Name,
+		maxAge:     cfg.MaxAge,
+		maxTotal:   cfg.MaxTotal,
+		maxSize:    cfg.MaxSize,
+		external:   cfg.External,
+		durability: cfg.Durability,
+		now:        time.Now,
+	}
+
+	switch cfg.Durability {
+	case "", DurableNone, DurableInterval, DurableLine:
+	default:
+		err = errors.Errorf("unknown durability: %s", cfg.Durability)
+		return
+	}
+
+	if cfg.SealKey != "" {
+		if cfg.Shared {
+			err = errors.Errorf("sealing is not supported for shared log files")
+			return
+		}
+
+		fl.sealer, err = newSealer(cfg.SealKey)
+		if err != nil {
+			return
+		}
+	}
+
+	if cfg.Shared {
+		err = fl.openShared()
+	} else {
+		err = fl.open()
+	}
+	if err != nil {
+		return
+	}
+
+	if fl.durability == DurableInterval {
+		fl.startSync(cfg.SyncInterval, cfg.SyncJitter)
+	}
+	return
+}
+
+// File is an io.Writer sink appending to a file, rotated daily.
+//
+// The active file is <dir>/<name>.log, and is renamed <name>-<yyyy-mm-dd>.log on rotation,
+// numbered as <name>-<yyyy-mm-dd>.<n>.log when rotated more than once a day for size.
+// When sealing, a signed manifest is written alongside each rotated file.
+// When shared, writes and rotation are serialized across processes by a lock on <dir>/<name>.lock.
+// When external, the active file is never rotated by File, only reopened by Reopen.
+type File struct {
+	dir        string
+	name       string
+	maxAge     time.Duration
+	maxTotal   int64
+	maxSize    int64
+	size       int64
+	external   bool
+	durability string
+	file       *os.File
+	lock       *os.File
+	day        string
+	sealer     *sealer
+	dirty      bool
+	syncErr    error
+	stop       chan struct{}
+	done       chan struct{}
+	closeOnce  sync.Once
+	now        func() time.Time
+	mu         sync.Mutex
+}
+
+// Write appends data to the active file, rotating first when the day has changed or it would pass MaxSize.
+func (fl *File) Write(data []byte) (n int, err error) {
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	// report a failed background sync with the next write
+
+	if fl.syncErr != nil {
+		err = fl.syncErr
+		fl.syncErr = nil
+		return
+	}
+
+	if fl.lock != nil {
+		err = lockFile(fl.lock)
+		if err != nil {
+			return
+		}
+		defer unlockFile(fl.lock)
+
+		err = fl.refresh()
+		if err != nil {
+			return
+		}
+	}
+
+	day := fl.now().UTC().Format(dayLayout)
+	if !fl.external && (day != fl.day || fl.oversize(len(data))) {
+		err = fl.rotate(day)
+		if err != nil {
+			return
+		}
+	}
+
+	// a single write to a file opened for append lands whole, even when shared
+
+	n, err = fl.file.Write(data)
+	fl.size += int64(n)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to write to log file")
+	}
+
+	fl.sealer.add(data[:n])
+	if err != nil {
+		return
+	}
+
+	fl.dirty = true
+	if fl.durability == DurableLine {
+		err = fl.sync()
+	}
+	return
+}
+
+// Reopen closes and reopens the active file, as after it's been renamed by logrotate.
+func (fl *File) Reopen() (err error) {
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.lock != nil {
+		err = lockFile(fl.lock)
+		if err != nil {
+			return
+		}
+		defer unlockFile(fl.lock)
+	}
+
+	return fl.reopen()
+}
+
+// Close stops any background sync and closes the active file, syncing it first unless durability is none.
+//
+// Calls after the first do nothing.
+func (fl *File) Close() (err error) {
+
+	fl.closeOnce.Do(func() {
+		err = fl.close()
+	})
+
+	return
+}
+
+//
+// unexported
+//
+
+func (fl *File) close() (err error) {
+
+	if fl.stop != nil {
+		close(fl.stop)
+		<-fl.done
+	}
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.durable() {
+		err = fl.sync()
+		if err != nil {
+			return
+		}
+	}
+
+	if fl.lock != nil {
+		err = fl.lock.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to close lock file")
+		}
+	}
+
+	return errors.Wrapf(fl.file.Close(), "failed to close log file")
+}
+
+func (fl *File) activePath() string {
+
+	return filepath.Join(fl.dir, fmt.Sprintf("%s.log", fl.name))
+}
+
+func (fl *File) rotatedPath(day string) string {
+
+	return filepath.Join(fl.dir, fmt.Sprintf("%s-%s.log", fl.name, day))
+}
+
+// nextRotated returns the first unused rotated path for day, numbering when rotated more than once.
+func (fl *File) nextRotated(day string) (path string, err error) {
+
+	path = fl.rotatedPath(day)
+	for seq := 1; ; seq++ {
+		_, err = os.Stat(path)
+		if os.IsNotExist(err) {
+			return path, nil
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to stat rotated file")
+		}
+
+		path = filepath.Join(fl.dir, fmt.Sprintf("%s-%s.%d.log", fl.name, day, seq))
+	}
+}
+
+func (fl *File) oversize(length int) bool {
+
+	// never rotate an empty file, however large the write
+
+	return fl.maxSize > 0 && fl.size > 0 && fl.size+int64(length) > fl.maxSize
+}
+
+func (fl *File) open() (err error) {
+
+	path := fl.activePath()
+
+	fl.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file")
+	}
+
+	info, err := fl.file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat log file")
+	}
+
+	fl.size = info.Size()
+
+	// an empty file belongs to today, otherwise to the day last written
+
+	fl.day = fl.now().UTC().Format(dayLayout)
+	if info.Size() > 0 {
+		fl.day = info.ModTime().UTC().Format(dayLayout)
+	}
+
+	return fl.sealer.resume(path)
+}
+
+// reopen closes the file, which may have been renamed, and opens the active file afresh.
+func (fl *File) reopen() (err error) {
+
+	if fl.durable() {
+		err = fl.sync()
+		if err != nil {
+			return
+		}
+	}
+
+	err = fl.file.Close()
+	if err != nil {
+		return errors.Wrapf(err, "failed to close log file for reopen")
+	}
+
+	return fl.open()
+}
+
+func (fl *File) rotate(day string) (err error) {
+
+	if fl.durable() {
+		err = fl.sync()
+		if err != nil {
+			return
+		}
+	}
+
+	err = fl.file.Close()
+
+	// reopen the active file when rotation fails, so that later writes can carry on,
+	// keeping its day when not yet renamed, so that rotation is retried
+
+	prev, renamed := fl.day, false
+	defer func() {
+		if err != nil {
+			_ = fl.open()
+			if !renamed {
+				fl.day = prev
+			}
+		}
+	}()
+
+	if err != nil {
+		return errors.Wrapf(err, "failed to close log file for rotation")
+	}
+
+	rotated, err := fl.nextRotated(fl.day)
+	if err != nil {
+		return
+	}
+
+	// seal before renaming, so that a failure leaves the day active, to be sealed when rotation is retried,
+	// rather than a rotated file missing from the chain
+
+	err = fl.sealer.seal(rotated, fl.day)
+	if err != nil {
+		return
+	}
+
+	err = os.Rename(fl.activePath(), rotated)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rename log file for rotation")
+	}
+	renamed = true
+
+	err = fl.open()
+	if err != nil {
+		return
+	}
+	fl.day = day
+
+	return
+}
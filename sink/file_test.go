@@ -0,0 +1,169 @@
+package sink
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("File", func() {
+
+	var (
+		dir  string
+		seed []byte
+		fl   *File
+		now  time.Time
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		seed = make([]byte, ed25519.SeedSize)
+		now = time.Date(2026, 10, 13, 23, 0, 0, 0, time.UTC)
+
+		cfg := &FileConfig{Dir: dir, Name: "app", SealKey: hex.EncodeToString(seed)}
+
+		var err error
+		fl, err = cfg.New()
+		Expect(err).ToNot(HaveOccurred())
+		fl.now = func() time.Time { return now }
+		fl.day = now.Format(dayLayout)
+	})
+
+	AfterEach(func() {
+		Expect(fl.Close()).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		for i := 0; i < 3; i++ {
+			_, err := fmt.Fprintf(fl, `{"msg":"filed","idx":%d}`+"\n", i)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		now = now.Add(2 * time.Hour)
+
+		_, err := fmt.Fprintf(fl, `{"msg":"next day"}`+"\n")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	When("the day changes", func() {
+		It("should rotate and seal the previous day", func() {
+			rotated := filepath.Join(dir, "app-2026-10-13.log")
+
+			data, err := os.ReadFile(rotated)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(HavePrefix(`{"msg":"filed","idx":0}`))
+
+			data, err = os.ReadFile(filepath.Join(dir, "app.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"msg":"next day"}` + "\n"))
+
+			pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey) //nolint: forcetypeassert
+			Expect(VerifySeal(rotated, pub)).To(Succeed())
+		})
+	})
+
+	When("sealing fails on rotation", func() {
+		It("should keep the day active, sealing it when rotation is retried", func() {
+			rotated := filepath.Join(dir, "app-2026-10-14.log")
+			blocker := ManifestPath(rotated)
+			Expect(os.Mkdir(blocker, 0o755)).To(Succeed())
+			now = now.Add(24 * time.Hour)
+
+			_, err := fmt.Fprintf(fl, `{"msg":"held"}`+"\n")
+			Expect(err).To(MatchError(ContainSubstring("failed to write manifest")))
+			Expect(rotated).ToNot(BeAnExistingFile())
+
+			Expect(os.Remove(blocker)).To(Succeed())
+			_, err = fmt.Fprintf(fl, `{"msg":"carry on"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := os.ReadFile(rotated)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"msg":"next day"}` + "\n"))
+
+			pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey) //nolint: forcetypeassert
+			Expect(VerifySeal(rotated, pub)).To(Succeed())
+
+			data, err = os.ReadFile(filepath.Join(dir, "app.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"msg":"carry on"}` + "\n"))
+		})
+	})
+
+	When("closed twice", func() {
+		It("should not panic", func() {
+			Expect(fl.Close()).To(Succeed())
+		})
+	})
+
+	When("a sealed file is altered", func() {
+		It("should fail verification", func() {
+			rotated := filepath.Join(dir, "app-2026-10-13.log")
+
+			data, err := os.ReadFile(rotated)
+			Expect(err).ToNot(HaveOccurred())
+			data[10] = 'X'
+			Expect(os.WriteFile(rotated, data, 0o644)).To(Succeed())
+
+			pub := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey) //nolint: forcetypeassert
+			Expect(VerifySeal(rotated, pub)).To(MatchError(ContainSubstring("does not match")))
+		})
+	})
+})
+
+var _ = Describe("Reopen", func() {
+
+	var (
+		dir string
+		fl  *File
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+
+		cfg := &FileConfig{Dir: dir, Name: "app", External: true}
+
+		var err error
+		fl, err = cfg.New()
+		Expect(err).ToNot(HaveOccurred())
+		fl.now = func() time.Time { return time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC) }
+	})
+
+	AfterEach(func() {
+		Expect(fl.Close()).To(Succeed())
+	})
+
+	When("logrotate renames the active file", func() {
+		It("should write to the renamed file until reopened", func() {
+			_, err := fmt.Fprintf(fl, `{"msg":"before"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			moved := filepath.Join(dir, "app.log.1")
+			Expect(os.Rename(filepath.Join(dir, "app.log"), moved)).To(Succeed())
+
+			_, err = fmt.Fprintf(fl, `{"msg":"straggler"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(fl.Reopen()).To(Succeed())
+			_, err = fmt.Fprintf(fl, `{"msg":"after"}`+"\n")
+			Expect(err).ToNot(HaveOccurred())
+
+			data, err := os.ReadFile(moved)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"msg":"before"}` + "\n" + `{"msg":"straggler"}` + "\n"))
+
+			data, err = os.ReadFile(filepath.Join(dir, "app.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`{"msg":"after"}` + "\n"))
+
+			rotated, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rotated).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,256 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// templateName matches the field name of a template placeholder, as in {tenant} or {http.route}.
+var templateName = regexp.MustCompile(`^[\w.]+$`)
+
+// KafkaMessage is a message as handed to a Producer.
+type KafkaMessage struct {
+	Topic   string
+	Key     []byte
+	Headers map[string]string
+	Value   []byte
+}
+
+// Producer produces a message to kafka, via a client of your choosing.
+//
+// Messages with a nil Key are left to the client's partitioner, typically round robin.
+type Producer interface {
+	Produce(msg KafkaMessage) error
+}
+
+// KafkaConfig is the configurable fields of Kafka.
+type KafkaConfig struct {
+	Topic    string            `json:"topic" desc:"topic messages are produced to"`
+	Key      string            `json:"key" desc:"template of the partition key, with fields placed as in {tenant}/{run_id}"`
+	Headers  map[string]string `json:"headers" desc:"templates of message headers by name"`
+	Strict   bool              `json:"strict" desc:"reject events missing a field of the key, rather than producing them unkeyed"`
+	Encoding string            `json:"encoding" desc:"message value encoding: json, otlp for protobuf log records, or msgpack, json when empty"`
+}
+
+// New creates a Kafka from KafkaConfig, returning an error when a template is malformed.
+func (cfg *KafkaConfig) New(producer Producer) (kfk *Kafka, err error) {
+
+	if cfg.Topic == "" {
+		err = errors.Errorf("kafka topic is required")
+		return
+	}
+
+	kfk = &Kafka{
+		topic:    cfg.Topic,
+		strict:   cfg.Strict,
+		producer: producer,
+		headers:  map[string]template{},
+	}
+
+	switch cfg.Encoding {
+	case "", "json":
+		kfk.encoder = sabot.JSON{}
+	case "otlp":
+		kfk.encoder = sabot.OTLP{}
+	case "msgpack":
+		kfk.encoder = sabot.Msgpack{}
+	default:
+		return nil, errors.Errorf("unknown kafka encoding: %s", cfg.Encoding)
+	}
+
+	if cfg.Key != "" {
+		kfk.key, err = parseTemplate(cfg.Key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid kafka key template")
+		}
+	}
+
+	for name, tmpl := range cfg.Headers {
+		if name == "" {
+			return nil, errors.Errorf("kafka header name is empty")
+		}
+
+		kfk.headers[name], err = parseTemplate(tmpl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid kafka header template: %s", name)
+		}
+	}
+
+	return
+}
+
+// Kafka is a sabot.EventWriter producing events to a kafka topic, keyed and headed from their fields,
+// so that events sharing a tenant or run land on the same partition and are consumed in order.
+//
+// Placeholders in templates are replaced with the value of the named field, ts, level, and msg included.
+// An event missing a field of the key is produced unkeyed, or rejected when Strict,
+// and a header missing a field is left off.
+// Values are encoded as json, or as OTLP protobuf log records or msgpack, smaller and quicker to parse.
+type Kafka struct {
+	topic    string
+	key      template
+	headers  map[string]template
+	strict   bool
+	encoder  sabot.Encoder
+	producer Producer
+}
+
+// WriteEvent produces an event to the topic.
+func (kfk *Kafka) WriteEvent(evt *sabot.Event) (err error) {
+
+	msg, err := kfk.message(evt)
+	if err != nil {
+		return
+	}
+
+	return errors.Wrapf(kfk.producer.Produce(msg), "failed to produce to: %s", kfk.topic)
+}
+
+// Write decodes encoded events and produces each, for when Sabot writes bytes rather than events.
+func (kfk *Kafka) Write(data []byte) (n int, err error) {
+
+	scn := sabot.NewScanner(bytes.NewReader(data))
+	for scn.Scan() {
+
+		var evt *sabot.Event
+		evt, err = scn.Event()
+		if err != nil {
+			return
+		}
+
+		err = kfk.WriteEvent(evt)
+		if err != nil {
+			return
+		}
+	}
+
+	err = scn.Err()
+	if err != nil {
+		return
+	}
+
+	return len(data), nil
+}
+
+//
+// unexported
+//
+
+// template is a parsed template, alternating literal text and field names.
+type template []segment
+
+type segment struct {
+	text  string
+	field bool
+}
+
+func (kfk *Kafka) message(evt *sabot.Event) (msg KafkaMessage, err error) {
+
+	fields := evt.Merged()
+
+	msg = KafkaMessage{Topic: kfk.topic}
+
+	if kfk.key != nil {
+		key, missing := kfk.key.render(fields)
+		if missing != "" && kfk.strict {
+			err = errors.Errorf("event lacks kafka key field: %s", missing)
+			return
+		}
+		if missing == "" {
+			msg.Key = []byte(key)
+		}
+	}
+
+	if len(kfk.headers) > 0 {
+		msg.Headers = map[string]string{}
+		for name, tmpl := range kfk.headers {
+			val, missing := tmpl.render(fields)
+			if missing == "" {
+				msg.Headers[name] = val
+			}
+		}
+	}
+
+	msg.Value, err = kfk.encoder.Encode(evt)
+	return
+}
+
+// parseTemplate parses placeholders of a template, rejecting unbalanced braces and bad names.
+func parseTemplate(str string) (tmpl template, err error) {
+
+	rest := str
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open == -1 {
+			tmpl = append(tmpl, segment{text: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, errors.Errorf("unopened brace in: %s", str)
+		}
+		if open > 0 {
+			tmpl = append(tmpl, segment{text: rest[:open]})
+		}
+
+		name, after, ok := strings.Cut(rest[open+1:], "}")
+		if !ok {
+			return nil, errors.Errorf("unclosed brace in: %s", str)
+		}
+		if !templateName.MatchString(name) {
+			return nil, errors.Errorf("bad field name %q in: %s", name, str)
+		}
+
+		tmpl = append(tmpl, segment{text: name, field: true})
+		rest = after
+	}
+
+	return
+}
+
+// render places field values into the template, returning the name of the first field missing, if any.
+func (tmpl template) render(fields sabot.Fields) (str, missing string) {
+
+	var bldr strings.Builder
+	for _, seg := range tmpl {
+		if !seg.field {
+			bldr.WriteString(seg.text)
+			continue
+		}
+
+		val, ok := fields[seg.text]
+		if !ok || val == nil {
+			return "", seg.text
+		}
+		bldr.WriteString(renderValue(val))
+	}
+
+	return bldr.String(), ""
+}
+
+func renderValue(val any) string {
+
+	switch val := val.(type) {
+	case string:
+		return val
+	case json.Marshaler:
+		data, err := val.MarshalJSON()
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+
+		var str string
+		if json.Unmarshal(data, &str) == nil {
+			return str
+		}
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
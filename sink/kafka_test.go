@@ -0,0 +1,184 @@
+package sink
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// producer records messages produced.
+type producer struct {
+	msgs []KafkaMessage
+	err  error
+}
+
+func (pdr *producer) Produce(msg KafkaMessage) error {
+
+	if pdr.err != nil {
+		return pdr.err
+	}
+	pdr.msgs = append(pdr.msgs, msg)
+	return nil
+}
+
+var _ = Describe("Kafka", func() {
+
+	var (
+		pdr *producer
+		cfg *KafkaConfig
+		kfk *Kafka
+		lgr *sabot.Sabot
+		ctx context.Context
+		err error
+	)
+
+	BeforeEach(func() {
+		pdr = &producer{}
+		cfg = &KafkaConfig{
+			Topic:   "logs",
+			Key:     "{tenant}/{run_id}",
+			Headers: map[string]string{"tenant": "{tenant}", "kind": "log-{level}"},
+		}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		kfk, err = cfg.New(pdr)
+		if err == nil {
+			lgr = &sabot.Sabot{Writer: kfk}
+		}
+	})
+
+	When("events carry the key fields", func() {
+		It("should key and head messages from them", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx = lgr.WithFields(ctx, "tenant", "acme")
+			lgr.Info(ctx, "started", "run_id", 42)
+
+			Expect(pdr.msgs).To(HaveLen(1))
+			msg := pdr.msgs[0]
+			Expect(msg.Topic).To(Equal("logs"))
+			Expect(string(msg.Key)).To(Equal("acme/42"))
+			Expect(msg.Headers).To(Equal(map[string]string{"tenant": "acme", "kind": "log-info"}))
+			Expect(string(msg.Value)).To(ContainSubstring(`"msg":"started"`))
+		})
+	})
+
+	When("an event lacks a key field", func() {
+		It("should produce it unkeyed, leaving off headers lacking fields", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			lgr.Info(ctx, "orphan", "run_id", 7)
+
+			Expect(pdr.msgs).To(HaveLen(1))
+			Expect(pdr.msgs[0].Key).To(BeNil())
+			Expect(pdr.msgs[0].Headers).To(Equal(map[string]string{"kind": "log-info"}))
+		})
+
+		When("strict", func() {
+			BeforeEach(func() {
+				cfg.Strict = true
+			})
+
+			It("should reject it", func() {
+				Expect(err).ToNot(HaveOccurred())
+
+				err = kfk.WriteEvent(&sabot.Event{Level: "info", Msg: "orphan", Fields: sabot.Fields{"run_id": 7}})
+				Expect(err).To(MatchError("event lacks kafka key field: tenant"))
+				Expect(pdr.msgs).To(BeEmpty())
+			})
+		})
+	})
+
+	When("writing encoded events", func() {
+		It("should decode and produce each", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			data := []byte(`{"ts":"2024-01-02T03:04:05Z","level":"info","msg":"one","tenant":"acme","run_id":"r1"}` + "\n" +
+				`{"ts":"2024-01-02T03:04:06Z","level":"info","msg":"two","tenant":"acme","run_id":"r2"}` + "\n")
+
+			n, err := kfk.Write(data)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n).To(Equal(len(data)))
+
+			Expect(pdr.msgs).To(HaveLen(2))
+			Expect(string(pdr.msgs[0].Key)).To(Equal("acme/r1"))
+			Expect(string(pdr.msgs[1].Key)).To(Equal("acme/r2"))
+		})
+	})
+
+	When("encoding as otlp", func() {
+		BeforeEach(func() {
+			cfg.Encoding = "otlp"
+		})
+
+		It("should produce protobuf log records", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx = lgr.WithFields(ctx, "tenant", "acme")
+			lgr.Info(ctx, "started", "run_id", 42)
+
+			Expect(pdr.msgs).To(HaveLen(1))
+			Expect(string(pdr.msgs[0].Key)).To(Equal("acme/42"))
+			Expect(string(pdr.msgs[0].Value)).ToNot(ContainSubstring(`"msg"`))
+			Expect(string(pdr.msgs[0].Value)).To(ContainSubstring("started"))
+		})
+	})
+
+	When("the encoding is unknown", func() {
+		BeforeEach(func() {
+			cfg.Encoding = "avro"
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError("unknown kafka encoding: avro"))
+		})
+	})
+
+	When("the producer fails", func() {
+		BeforeEach(func() {
+			pdr.err = errors.Errorf("broker unavailable")
+		})
+
+		It("should return the error", func() {
+			Expect(err).ToNot(HaveOccurred())
+
+			err = kfk.WriteEvent(&sabot.Event{Level: "info", Msg: "lost", Fields: sabot.Fields{}})
+			Expect(err).To(MatchError("failed to produce to: logs: broker unavailable"))
+		})
+	})
+
+	DescribeTable("validating templates",
+		func(key, expected string) {
+			cfg.Key = key
+			_, err := cfg.New(pdr)
+
+			if expected == "" {
+				Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			Expect(err).To(MatchError(expected))
+		},
+		Entry("fields and literals", "{tenant}:{http.route}", ""),
+		Entry("literal only", "fixed", ""),
+		Entry("unclosed", "{tenant", "invalid kafka key template: unclosed brace in: {tenant"),
+		Entry("unopened", "tenant}", "invalid kafka key template: unopened brace in: tenant}"),
+		Entry("empty name", "{}/{run_id}", `invalid kafka key template: bad field name "" in: {}/{run_id}`),
+		Entry("bad name", "{ten ant}", `invalid kafka key template: bad field name "ten ant" in: {ten ant}`),
+	)
+
+	When("no topic is given", func() {
+		BeforeEach(func() {
+			cfg.Topic = ""
+		})
+
+		It("should error", func() {
+			Expect(err).To(MatchError("kafka topic is required"))
+		})
+	})
+})
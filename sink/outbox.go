@@ -0,0 +1,449 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DeliveryKey is the field stamped on json events delivered via Outbox, stable across retransmission.
+	DeliveryKey  string = "delivery_key"
+	defaultRetry        = time.Second
+	deliveryLen  int    = 16
+)
+
+// Deliverer delivers an event to a network sink, returning nil only once it is acknowledged.
+type Deliverer interface {
+	Deliver(data []byte) error
+}
+
+// KeyedDeliverer is implemented by Deliverers to sinks supporting idempotent ingestion,
+// which are handed each event's delivery key in place of finding it among the fields.
+//
+// When the Deliverer given Outbox implements it, DeliverKeyed is used in place of Deliver.
+type KeyedDeliverer interface {
+	DeliverKeyed(key string, data []byte) error
+}
+
+// OutboxConfig is the configurable fields of Outbox.
+type OutboxConfig struct {
+	Dir    string        `json:"dir" desc:"directory of the outbox wal and its delivered offset"`
+	Levels []string      `json:"levels" desc:"levels of events delivered via the outbox, such as audit"`
+	Retry  time.Duration `json:"retry" desc:"wait between delivery attempts after a failure, defaulting to one second"`
+}
+
+// New creates an Outbox from OutboxConfig, delivering designated events via dlv and writing the rest to writer.
+//
+// Events left undelivered by a previous run are replayed.
+func (cfg *OutboxConfig) New(dlv Deliverer, writer io.Writer) (ob *Outbox, err error) {
+
+	ob = &Outbox{
+		dir:     cfg.Dir,
+		levels:  cfg.Levels,
+		retry:   cfg.Retry,
+		deliver: dlv,
+		writer:  writer,
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if ob.retry <= 0 {
+		ob.retry = defaultRetry
+	}
+
+	err = ob.open()
+	if err != nil {
+		return
+	}
+
+	go ob.loop()
+	ob.signal()
+
+	return
+}
+
+// Outbox is a sabot.LevelWriter sink guaranteeing at-least-once delivery of business-critical events,
+// such as audit or billing, to a network sink.
+//
+// Events at designated levels are appended and synced to a write-ahead log, <dir>/outbox.wal,
+// each record a "<delivery key> <length>" line followed by the event as written, binary formats included,
+// before WriteLevel returns, then delivered in order from the log in the background, retrying until acknowledged.
+// The offset delivered through is kept in <dir>/outbox.offset, so that a restart replays only what's pending,
+// while a crash between delivery and recording its offset delivers an event twice.
+// Each event is given a random delivery key when logged, stamped as the delivery_key field of json events,
+// so that consumers can deduplicate those delivered more than once.
+// Other events are written to the given writer as-is.
+type Outbox struct {
+	dir       string
+	levels    []string
+	retry     time.Duration
+	deliver   Deliverer
+	writer    io.Writer
+	wal       *os.File
+	size      int64
+	offset    int64
+	lastErr   error
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	mu        sync.Mutex
+}
+
+// Write writes data, not being designated, to the writer.
+func (ob *Outbox) Write(data []byte) (n int, err error) {
+
+	return ob.writer.Write(data)
+}
+
+// WriteLevel appends data at designated levels to the log, syncing before returning, and writes the rest to the writer.
+func (ob *Outbox) WriteLevel(level string, data []byte) (n int, err error) {
+
+	if !slices.Contains(ob.levels, level) {
+		return ob.writer.Write(data)
+	}
+
+	key, err := newDeliveryKey()
+	if err != nil {
+		return
+	}
+
+	// the key leads each record of the log, as not every format can be stamped
+	// and length rather than newline ends it, as binary formats may have newlines of their own
+
+	stamped := stampKey(data, key)
+	record := append([]byte(fmt.Sprintf("%s %d\n", key, len(stamped))), stamped...)
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	written, err := ob.wal.Write(record)
+	ob.size += int64(written)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to write to outbox")
+		return
+	}
+
+	err = ob.wal.Sync()
+	if err != nil {
+		err = errors.Wrapf(err, "failed to sync outbox")
+		return
+	}
+
+	ob.signal()
+	return len(data), nil
+}
+
+// Pending returns the bytes logged but not yet delivered, and the error of the last failed delivery, if any.
+func (ob *Outbox) Pending() (pending int64, err error) {
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	return ob.size - ob.offset, ob.lastErr
+}
+
+// Close stops delivery and closes the log, leaving any pending events for replay.
+//
+// Calls after the first do nothing.
+func (ob *Outbox) Close() (err error) {
+
+	ob.closeOnce.Do(func() {
+		close(ob.stop)
+		<-ob.done
+
+		ob.mu.Lock()
+		defer ob.mu.Unlock()
+
+		err = errors.Wrapf(ob.wal.Close(), "failed to close outbox")
+	})
+
+	return
+}
+
+//
+// unexported
+//
+
+func (ob *Outbox) walPath() string {
+
+	return filepath.Join(ob.dir, "outbox.wal")
+}
+
+func (ob *Outbox) offsetPath() string {
+
+	return filepath.Join(ob.dir, "outbox.offset")
+}
+
+func (ob *Outbox) open() (err error) {
+
+	ob.wal, err = os.OpenFile(ob.walPath(), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open outbox")
+	}
+
+	info, err := ob.wal.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat outbox")
+	}
+
+	// drop a record torn by a crash mid-write, as it was never acknowledged to the logger
+
+	reader := bufio.NewReader(io.NewSectionReader(ob.wal, 0, info.Size()))
+	for {
+		_, _, length, rErr := readRecord(reader)
+		if rErr == io.EOF || rErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rErr != nil {
+			return rErr
+		}
+		ob.size += length
+	}
+
+	if ob.size < info.Size() {
+		err = ob.wal.Truncate(ob.size)
+		if err != nil {
+			return errors.Wrapf(err, "failed to truncate torn outbox")
+		}
+	}
+
+	ob.offset, err = readOffset(ob.offsetPath())
+	if err != nil {
+		return
+	}
+	if ob.offset > ob.size {
+		ob.offset = 0
+	}
+
+	return
+}
+
+func (ob *Outbox) signal() {
+
+	select {
+	case ob.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ob *Outbox) loop() {
+
+	defer close(ob.done)
+
+	for {
+		select {
+		case <-ob.stop:
+			return
+		case <-ob.wake:
+		}
+
+		for {
+			err := ob.drain()
+			if err == nil {
+				break
+			}
+
+			ob.mu.Lock()
+			ob.lastErr = err
+			ob.mu.Unlock()
+
+			timer := time.NewTimer(ob.retry)
+			select {
+			case <-ob.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// drain delivers pending events in order, recording the offset after each.
+func (ob *Outbox) drain() (err error) {
+
+	ob.mu.Lock()
+	offset, size := ob.offset, ob.size
+	ob.mu.Unlock()
+
+	if offset < size {
+		err = ob.deliverRange(offset, size)
+		if err != nil {
+			return
+		}
+	}
+
+	ob.mu.Lock()
+	defer ob.mu.Unlock()
+
+	// events written meanwhile have signalled, otherwise start the log afresh rather than let it grow
+
+	ob.lastErr = nil
+	if ob.size == 0 || ob.offset < ob.size {
+		return
+	}
+
+	// offset first, so that a crash in between redelivers rather than skips what's written next
+
+	err = writeOffset(ob.offsetPath(), 0)
+	if err != nil {
+		return
+	}
+	ob.offset = 0
+
+	err = ob.wal.Truncate(0)
+	if err != nil {
+		return errors.Wrapf(err, "failed to truncate outbox")
+	}
+	ob.size = 0
+
+	return
+}
+
+func (ob *Outbox) deliverRange(offset, size int64) (err error) {
+
+	file, err := os.Open(ob.walPath())
+	if err != nil {
+		return errors.Wrapf(err, "failed to open outbox for delivery")
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, size-offset))
+	for {
+		key, data, length, rErr := readRecord(reader)
+		if rErr == io.EOF {
+			return nil
+		}
+		if rErr != nil {
+			return rErr
+		}
+
+		err = ob.deliverRecord(key, data)
+		if err != nil {
+			return errors.Wrapf(err, "failed to deliver event")
+		}
+
+		offset += length
+		err = writeOffset(ob.offsetPath(), offset)
+		if err != nil {
+			return
+		}
+
+		ob.mu.Lock()
+		ob.offset = offset
+		ob.mu.Unlock()
+	}
+}
+
+func (ob *Outbox) deliverRecord(key string, data []byte) error {
+
+	keyed, ok := ob.deliver.(KeyedDeliverer)
+	if ok {
+		return keyed.DeliverKeyed(key, data)
+	}
+
+	return ob.deliver.Deliver(data)
+}
+
+// readRecord reads a record of the log, returning io.EOF at its end and io.ErrUnexpectedEOF when torn.
+func readRecord(reader *bufio.Reader) (key string, data []byte, length int64, err error) {
+
+	header, err := reader.ReadBytes('\n')
+	if err == io.EOF && len(header) > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return
+	}
+
+	key, size, ok := strings.Cut(strings.TrimSuffix(string(header), "\n"), " ")
+	dataLen, aErr := strconv.Atoi(size)
+	if !ok || aErr != nil || dataLen < 0 {
+		err = errors.Errorf("malformed outbox record: %q", header)
+		return
+	}
+
+	data = make([]byte, dataLen)
+	_, err = io.ReadFull(reader, data)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return
+	}
+
+	length = int64(len(header) + dataLen)
+	return
+}
+
+func newDeliveryKey() (key string, err error) {
+
+	buf := make([]byte, deliveryLen)
+	_, err = rand.Read(buf)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to generate delivery key")
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// stampKey adds the delivery key as the first field of a json object, leaving other data as-is.
+func stampKey(data []byte, key string) []byte {
+
+	trimmed := bytes.TrimLeft(data, " \t")
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return data
+	}
+
+	body := bytes.TrimLeft(trimmed[1:], " \t")
+	sep := ","
+	if len(body) > 0 && body[0] == '}' {
+		sep = ""
+	}
+
+	return append([]byte(fmt.Sprintf(`{"%s":"%s"%s`, DeliveryKey, key, sep)), body...)
+}
+
+func readOffset(path string) (offset int64, err error) {
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read outbox offset")
+	}
+
+	offset, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse outbox offset")
+	}
+
+	return
+}
+
+// writeOffset replaces the offset file via rename, so that it's never seen half written.
+func writeOffset(path string, offset int64) (err error) {
+
+	tmp := path + ".tmp"
+
+	err = os.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write outbox offset")
+	}
+
+	return errors.Wrapf(os.Rename(tmp, path), "failed to replace outbox offset")
+}
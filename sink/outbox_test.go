@@ -0,0 +1,252 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// deliverer records events delivered, failing while down.
+type deliverer struct {
+	events []string
+	down   bool
+	mu     sync.Mutex
+}
+
+func (dlv *deliverer) Deliver(data []byte) error {
+
+	dlv.mu.Lock()
+	defer dlv.mu.Unlock()
+
+	if dlv.down {
+		return errors.Errorf("connection refused")
+	}
+	dlv.events = append(dlv.events, string(data))
+	return nil
+}
+
+func (dlv *deliverer) delivered() []string {
+
+	dlv.mu.Lock()
+	defer dlv.mu.Unlock()
+
+	return append([]string{}, dlv.events...)
+}
+
+func (dlv *deliverer) setDown(down bool) {
+
+	dlv.mu.Lock()
+	defer dlv.mu.Unlock()
+
+	dlv.down = down
+}
+
+// keyedDeliverer records delivery keys, losing the first acknowledgement.
+type keyedDeliverer struct {
+	keys   []string
+	events []string
+	lost   bool
+	mu     sync.Mutex
+}
+
+func (kd *keyedDeliverer) Deliver(data []byte) error {
+
+	return errors.Errorf("unkeyed delivery")
+}
+
+func (kd *keyedDeliverer) DeliverKeyed(key string, data []byte) error {
+
+	kd.mu.Lock()
+	defer kd.mu.Unlock()
+
+	kd.keys = append(kd.keys, key)
+	kd.events = append(kd.events, string(data))
+	if !kd.lost {
+		kd.lost = true
+		return errors.Errorf("ack lost")
+	}
+	return nil
+}
+
+func (kd *keyedDeliverer) delivered() []string {
+
+	kd.mu.Lock()
+	defer kd.mu.Unlock()
+
+	return append([]string{}, kd.keys...)
+}
+
+var _ = Describe("Outbox", func() {
+
+	var (
+		dir  string
+		dlv  *deliverer
+		dlvr Deliverer
+		buf  *bytes.Buffer
+		cfg  *OutboxConfig
+		ob   *Outbox
+		lgr  *sabot.Sabot
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		dlv = &deliverer{}
+		dlvr = dlv
+		buf = &bytes.Buffer{}
+		cfg = &OutboxConfig{Dir: dir, Levels: []string{"audit"}, Retry: 10 * time.Millisecond}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		ob, err = cfg.New(dlvr, buf)
+		Expect(err).ToNot(HaveOccurred())
+		lgr = &sabot.Sabot{Writer: ob}
+	})
+
+	audit := func(action string) {
+		Expect(lgr.Audit(ctx, action, "actor", "u1", "target", "t1", "outcome", "ok")).To(Succeed())
+	}
+
+	pending := func() int64 {
+		pnd, _ := ob.Pending()
+		return pnd
+	}
+
+	When("logging designated and other events", func() {
+		It("should deliver designated via the outbox and write others as-is", func() {
+			lgr.Info(ctx, "routine")
+			audit("charged")
+
+			Eventually(dlv.delivered).Should(HaveLen(1))
+			Expect(dlv.delivered()[0]).To(ContainSubstring(`"msg":"charged"`))
+			Expect(buf.String()).To(ContainSubstring(`"msg":"routine"`))
+			Expect(buf.String()).ToNot(ContainSubstring("charged"))
+
+			Eventually(pending).Should(BeZero())
+			Expect(ob.Close()).To(Succeed())
+
+			info, err := os.Stat(filepath.Join(dir, "outbox.wal"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Size()).To(BeZero())
+		})
+	})
+
+	When("delivery fails for a time", func() {
+		BeforeEach(func() {
+			dlv.setDown(true)
+		})
+
+		It("should retry in order until delivered", func() {
+			audit("one")
+			audit("two")
+
+			Eventually(func() error { _, err := ob.Pending(); return err }).Should(MatchError(ContainSubstring("connection refused")))
+			Expect(pending()).ToNot(BeZero())
+
+			dlv.setDown(false)
+			Eventually(dlv.delivered).Should(HaveLen(2))
+			Expect(dlv.delivered()[0]).To(ContainSubstring(`"msg":"one"`))
+			Expect(dlv.delivered()[1]).To(ContainSubstring(`"msg":"two"`))
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+
+	When("an acknowledgement is lost", func() {
+		var (
+			kd *keyedDeliverer
+		)
+
+		BeforeEach(func() {
+			kd = &keyedDeliverer{}
+			dlvr = kd
+		})
+
+		It("should retransmit with the same delivery key", func() {
+			audit("charged")
+
+			Eventually(kd.delivered).Should(HaveLen(2))
+			Expect(kd.keys[0]).To(HaveLen(32))
+			Expect(kd.keys[1]).To(Equal(kd.keys[0]))
+			Expect(kd.events[1]).To(HavePrefix(`{"delivery_key":"` + kd.keys[0] + `","`))
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+
+	When("restarting with events pending", func() {
+		BeforeEach(func() {
+			dlv.setDown(true)
+		})
+
+		It("should replay them, dropping a torn record", func() {
+			audit("one")
+			Expect(ob.Close()).To(Succeed())
+
+			wal, err := os.OpenFile(filepath.Join(dir, "outbox.wal"), os.O_APPEND|os.O_WRONLY, 0o644)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = wal.WriteString("k2 40\n" + `{"msg":"tor`)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(wal.Close()).To(Succeed())
+
+			dlv.setDown(false)
+			ob, err = cfg.New(dlv, buf)
+			Expect(err).ToNot(HaveOccurred())
+			lgr = &sabot.Sabot{Writer: ob}
+			audit("two")
+
+			Eventually(dlv.delivered).Should(HaveLen(2))
+			Expect(dlv.delivered()[0]).To(ContainSubstring(`"msg":"one"`))
+			Expect(dlv.delivered()[1]).To(ContainSubstring(`"msg":"two"`))
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+
+	When("events are binary", func() {
+		BeforeEach(func() {
+			dlv.setDown(true)
+		})
+
+		It("should replay them whole, newlines and all", func() {
+			lgr.Encoder = sabot.Msgpack{}
+			audit("line\nfeed")
+			audit("two")
+			Expect(ob.Close()).To(Succeed())
+
+			dlv.setDown(false)
+			var err error
+			ob, err = cfg.New(dlv, buf)
+			Expect(err).ToNot(HaveOccurred())
+
+			Eventually(dlv.delivered).Should(HaveLen(2))
+			Expect(dlv.delivered()[0]).To(ContainSubstring("line\nfeed"))
+			Expect(dlv.delivered()[1]).To(ContainSubstring("two"))
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+
+	When("closed twice", func() {
+		It("should not panic", func() {
+			Expect(ob.Close()).To(Succeed())
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+
+	Describe("stamping delivery keys", func() {
+		It("should stamp json objects only", func() {
+			Expect(string(stampKey([]byte(`{"msg":"hi"}`), "k1"))).To(Equal(`{"delivery_key":"k1","msg":"hi"}`))
+			Expect(string(stampKey([]byte(`{}`), "k1"))).To(Equal(`{"delivery_key":"k1"}`))
+			Expect(string(stampKey([]byte(`msg=hi`), "k1"))).To(Equal(`msg=hi`))
+			Expect(ob.Close()).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,559 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const (
+	parquetMagic     string        = "PAR1"
+	defaultMaxEvents int           = 10000
+	defaultFlush     time.Duration = time.Minute
+	// FieldsColumn is the column of fields not given their own, as a json object.
+	FieldsColumn string = "fields"
+)
+
+// ParquetConfig is the configurable fields of Parquet.
+type ParquetConfig struct {
+	Dir       string        `json:"dir" desc:"directory parquet files are written under, partitioned as dt=2006-01-02/hour=15"`
+	Columns   []string      `json:"columns" desc:"field keys written as columns of their own, the rest going to a fields column as json"`
+	MaxEvents int           `json:"max_events" desc:"events buffered before being written, defaulting to 10000"`
+	Flush     time.Duration `json:"flush" desc:"longest events are buffered before being written, defaulting to one minute"`
+}
+
+// New creates a Parquet from ParquetConfig, flushing in the background until closed.
+func (cfg *ParquetConfig) New() (pq *Parquet, err error) {
+
+	for _, col := range cfg.Columns {
+		if col == "" || col == "ts" || col == "level" || col == "msg" || col == FieldsColumn {
+			return nil, errors.Errorf("invalid parquet column: %q", col)
+		}
+	}
+
+	err = os.MkdirAll(cfg.Dir, 0o755)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create parquet dir: %s", cfg.Dir)
+	}
+
+	pq = &Parquet{
+		dir:       cfg.Dir,
+		columns:   cfg.Columns,
+		maxEvents: cfg.MaxEvents,
+		flush:     cfg.Flush,
+		hours:     map[time.Time][]*sabot.Event{},
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if pq.maxEvents <= 0 {
+		pq.maxEvents = defaultMaxEvents
+	}
+	if pq.flush <= 0 {
+		pq.flush = defaultFlush
+	}
+
+	go pq.loop()
+
+	return
+}
+
+// Parquet is a sabot.EventWriter batching events into columnar Parquet files,
+// for querying directly with DuckDB or Athena rather than via a log pipeline.
+//
+// Events are buffered by the hour of their ts and written, once MaxEvents are buffered or Flush has passed,
+// to a file per hour under <dir>/dt=<date>/hour=<hour>, the hive partitioning both understand.
+// Columns are ts, as a utc timestamp, level, msg, those of Columns, and fields holding the rest as json.
+// Files are written uncompressed, with a single row group, and appear whole via rename.
+type Parquet struct {
+	dir       string
+	columns   []string
+	maxEvents int
+	flush     time.Duration
+	hours     map[time.Time][]*sabot.Event
+	count     int
+	lastErr   error
+	seq       int
+	stop      chan struct{}
+	done      chan struct{}
+	stopOnce  sync.Once
+	mu        sync.Mutex
+	writeMu   sync.Mutex
+}
+
+// WriteEvent buffers an event, writing those buffered once there are enough.
+//
+// An error from a background write is returned by the next call.
+func (pq *Parquet) WriteEvent(evt *sabot.Event) (err error) {
+
+	hour := evt.Time.UTC().Truncate(time.Hour)
+
+	pq.mu.Lock()
+	pq.hours[hour] = append(pq.hours[hour], evt)
+	pq.count++
+	full := pq.count >= pq.maxEvents
+	err, pq.lastErr = pq.lastErr, nil
+	pq.mu.Unlock()
+
+	if err != nil || !full {
+		return
+	}
+
+	return pq.Flush()
+}
+
+// Write decodes encoded events and buffers each, for when Sabot writes bytes rather than events.
+func (pq *Parquet) Write(data []byte) (n int, err error) {
+
+	scn := sabot.NewScanner(bytes.NewReader(data))
+	for scn.Scan() {
+
+		var evt *sabot.Event
+		evt, err = scn.Event()
+		if err != nil {
+			return
+		}
+
+		err = pq.WriteEvent(evt)
+		if err != nil {
+			return
+		}
+	}
+
+	err = scn.Err()
+	if err != nil {
+		return
+	}
+
+	return len(data), nil
+}
+
+// Flush writes buffered events, a file per hour.
+func (pq *Parquet) Flush() (err error) {
+
+	pq.mu.Lock()
+	hours := pq.hours
+	pq.hours = map[time.Time][]*sabot.Event{}
+	pq.count = 0
+	pq.mu.Unlock()
+
+	// serialize writes, so that files are named in order
+
+	pq.writeMu.Lock()
+	defer pq.writeMu.Unlock()
+
+	keys := make([]time.Time, 0, len(hours))
+	for hour := range hours {
+		keys = append(keys, hour)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+
+	for idx, hour := range keys {
+		err = pq.writeFile(hour, hours[hour])
+		if err != nil {
+			pq.restore(keys[idx:], hours)
+			return
+		}
+	}
+
+	return
+}
+
+// Close stops background flushing and writes what's buffered.
+func (pq *Parquet) Close() (err error) {
+
+	pq.stopOnce.Do(func() {
+		close(pq.stop)
+		<-pq.done
+	})
+
+	return pq.Flush()
+}
+
+//
+// unexported
+//
+
+func (pq *Parquet) loop() {
+
+	defer close(pq.done)
+
+	ticker := time.NewTicker(pq.flush)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-pq.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := pq.Flush()
+		if err != nil {
+			pq.mu.Lock()
+			pq.lastErr = err
+			pq.mu.Unlock()
+		}
+	}
+}
+
+// restore puts back hours left unwritten, ahead of any events buffered meanwhile, for the next flush to retry.
+func (pq *Parquet) restore(keys []time.Time, hours map[time.Time][]*sabot.Event) {
+
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	for _, hour := range keys {
+		pq.hours[hour] = append(hours[hour], pq.hours[hour]...)
+		pq.count += len(hours[hour])
+	}
+}
+
+// partition returns the hive partition dir of an hour.
+func (pq *Parquet) partition(hour time.Time) string {
+
+	return filepath.Join(pq.dir, "dt="+hour.Format("2006-01-02"), "hour="+hour.Format("15"))
+}
+
+// writeFile writes events to a new file in their hour's partition, via a dot file hidden from readers until renamed.
+func (pq *Parquet) writeFile(hour time.Time, events []*sabot.Event) (err error) {
+
+	dir := pq.partition(hour)
+	err = os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create parquet partition: %s", dir)
+	}
+
+	pq.seq++
+	name := fmt.Sprintf("events-%d-%d.parquet", time.Now().UnixNano(), pq.seq)
+	path := filepath.Join(dir, name)
+	tmp := filepath.Join(dir, "."+name)
+
+	err = os.WriteFile(tmp, pq.encode(events), 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write parquet file: %s", tmp)
+	}
+
+	return errors.Wrapf(os.Rename(tmp, path), "failed to rename parquet file: %s", path)
+}
+
+// parquet physical and converted types, repetitions, and encodings.
+const (
+	pqInt64      int32 = 2
+	pqByteArray  int32 = 6
+	pqUtf8       int32 = 0
+	pqMicros     int32 = 10
+	pqRequired   int32 = 0
+	pqOptional   int32 = 1
+	pqPlain      int32 = 0
+	pqRle        int32 = 3
+	pqDataPage   int32 = 0
+	pqUncompress int32 = 0
+)
+
+// pqColumn is a column's values, byte arrays nil where null, or micros when a timestamp.
+type pqColumn struct {
+	name      string
+	optional  bool
+	timestamp bool
+	micros    []int64
+	values    [][]byte
+}
+
+func (pq *Parquet) encode(events []*sabot.Event) []byte {
+
+	cols := []*pqColumn{{name: "ts", timestamp: true}, {name: "level"}, {name: "msg"}}
+	for _, name := range pq.columns {
+		cols = append(cols, &pqColumn{name: name, optional: true})
+	}
+	fieldsCol := &pqColumn{name: FieldsColumn, optional: true}
+	cols = append(cols, fieldsCol)
+
+	for _, evt := range events {
+		cols[0].micros = append(cols[0].micros, evt.Time.UnixMicro())
+		cols[1].values = append(cols[1].values, []byte(evt.Level))
+		cols[2].values = append(cols[2].values, []byte(evt.Msg))
+
+		rest := sabot.Fields{}
+		for key, val := range evt.Fields {
+			if !slices.Contains(pq.columns, key) {
+				rest[key] = val
+			}
+		}
+
+		for idx, name := range pq.columns {
+			val, ok := evt.Fields[name]
+			cols[3+idx].values = append(cols[3+idx].values, columnValue(val, ok))
+		}
+
+		fieldsCol.values = append(fieldsCol.values, columnValue(rest, len(rest) > 0))
+	}
+
+	data := []byte(parquetMagic)
+
+	meta := &compact{}
+	meta.begin()
+	meta.i32(1, 1)
+
+	meta.list(2, tStruct, len(cols)+1)
+	meta.begin()
+	meta.str(4, "schema")
+	meta.i32(5, int32(len(cols)))
+	meta.end()
+	for _, col := range cols {
+		col.schema(meta)
+	}
+
+	meta.i64(3, int64(len(events)))
+	meta.list(4, tStruct, 1)
+	meta.begin()
+	meta.list(1, tStruct, len(cols))
+
+	var total int64
+	for _, col := range cols {
+		offset := int64(len(data))
+		data = append(data, col.chunk(len(events))...)
+		size := int64(len(data)) - offset
+		total += size
+
+		meta.begin()
+		meta.i64(2, offset)
+		meta.structField(3)
+		meta.i32(1, col.physical())
+		meta.list(2, tI32, 2)
+		meta.listI32(pqPlain)
+		meta.listI32(pqRle)
+		meta.list(3, tBinary, 1)
+		meta.listStr(col.name)
+		meta.i32(4, pqUncompress)
+		meta.i64(5, int64(len(events)))
+		meta.i64(6, size)
+		meta.i64(7, size)
+		meta.i64(9, offset)
+		meta.end()
+		meta.end()
+	}
+
+	meta.i64(2, total)
+	meta.i64(3, int64(len(events)))
+	meta.end()
+	meta.str(6, "sabot")
+	meta.end()
+
+	data = append(data, meta.buf...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(meta.buf)))
+	return append(data, parquetMagic...)
+}
+
+func (col *pqColumn) physical() int32 {
+
+	if col.timestamp {
+		return pqInt64
+	}
+	return pqByteArray
+}
+
+// schema writes the column's SchemaElement.
+func (col *pqColumn) schema(meta *compact) {
+
+	repetition := pqRequired
+	if col.optional {
+		repetition = pqOptional
+	}
+
+	converted := pqUtf8
+	if col.physical() == pqInt64 {
+		converted = pqMicros
+	}
+
+	meta.begin()
+	meta.i32(1, col.physical())
+	meta.i32(3, repetition)
+	meta.str(4, col.name)
+	meta.i32(6, converted)
+	meta.end()
+}
+
+// chunk returns the column chunk, a page header and single data page of plain values,
+// preceded by definition levels when optional.
+func (col *pqColumn) chunk(rows int) []byte {
+
+	var page []byte
+	if col.optional {
+		levels := defLevels(col.values)
+		page = binary.LittleEndian.AppendUint32(page, uint32(len(levels)))
+		page = append(page, levels...)
+	}
+
+	for _, micros := range col.micros {
+		page = binary.LittleEndian.AppendUint64(page, uint64(micros))
+	}
+	for _, val := range col.values {
+		if val == nil {
+			continue
+		}
+		page = binary.LittleEndian.AppendUint32(page, uint32(len(val)))
+		page = append(page, val...)
+	}
+
+	header := &compact{}
+	header.begin()
+	header.i32(1, pqDataPage)
+	header.i32(2, int32(len(page)))
+	header.i32(3, int32(len(page)))
+	header.structField(5)
+	header.i32(1, int32(rows))
+	header.i32(2, pqPlain)
+	header.i32(3, pqRle)
+	header.i32(4, pqRle)
+	header.end()
+	header.end()
+
+	return append(header.buf, page...)
+}
+
+// defLevels encodes definition levels of width one as rle runs, one for present and zero for null.
+func defLevels(values [][]byte) (data []byte) {
+
+	for idx := 0; idx < len(values); {
+		present := values[idx] != nil
+		run := 1
+		for idx+run < len(values) && (values[idx+run] != nil) == present {
+			run++
+		}
+
+		data = binary.AppendUvarint(data, uint64(run)<<1)
+		if present {
+			data = append(data, 1)
+		} else {
+			data = append(data, 0)
+		}
+		idx += run
+	}
+
+	return
+}
+
+// columnValue is a field's value for its column, strings as-is and others as json, nil when absent.
+func columnValue(val any, ok bool) []byte {
+
+	if !ok {
+		return nil
+	}
+
+	str, isStr := val.(string)
+	if isStr {
+		return []byte(str)
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return []byte(fmt.Sprintf("%v", val))
+	}
+
+	var unquoted string
+	if json.Unmarshal(data, &unquoted) == nil {
+		return []byte(unquoted)
+	}
+
+	return data
+}
+
+// thrift compact protocol types.
+const (
+	tI32    byte = 5
+	tI64    byte = 6
+	tBinary byte = 8
+	tList   byte = 9
+	tStruct byte = 12
+)
+
+// compact writes the thrift compact protocol, as parquet metadata is encoded.
+type compact struct {
+	buf  []byte
+	last []int16
+}
+
+// begin starts a struct, its fields numbered afresh.
+func (cp *compact) begin() {
+
+	cp.last = append(cp.last, 0)
+}
+
+// end stops a struct.
+func (cp *compact) end() {
+
+	cp.buf = append(cp.buf, 0)
+	cp.last = cp.last[:len(cp.last)-1]
+}
+
+func (cp *compact) field(id int16, typ byte) {
+
+	top := len(cp.last) - 1
+	delta := id - cp.last[top]
+	if delta > 0 && delta <= 15 {
+		cp.buf = append(cp.buf, byte(delta)<<4|typ)
+	} else {
+		cp.buf = append(cp.buf, typ)
+		cp.buf = binary.AppendVarint(cp.buf, int64(id))
+	}
+	cp.last[top] = id
+}
+
+func (cp *compact) i32(id int16, val int32) {
+
+	cp.field(id, tI32)
+	cp.buf = binary.AppendVarint(cp.buf, int64(val))
+}
+
+func (cp *compact) i64(id int16, val int64) {
+
+	cp.field(id, tI64)
+	cp.buf = binary.AppendVarint(cp.buf, val)
+}
+
+func (cp *compact) str(id int16, val string) {
+
+	cp.field(id, tBinary)
+	cp.listStr(val)
+}
+
+// structField starts a struct valued field, ended with end.
+func (cp *compact) structField(id int16) {
+
+	cp.field(id, tStruct)
+	cp.begin()
+}
+
+// list starts a list field, its elements following.
+func (cp *compact) list(id int16, elem byte, size int) {
+
+	cp.field(id, tList)
+	if size < 15 {
+		cp.buf = append(cp.buf, byte(size)<<4|elem)
+		return
+	}
+	cp.buf = append(cp.buf, 0xf0|elem)
+	cp.buf = binary.AppendUvarint(cp.buf, uint64(size))
+}
+
+func (cp *compact) listI32(val int32) {
+
+	cp.buf = binary.AppendVarint(cp.buf, int64(val))
+}
+
+func (cp *compact) listStr(val string) {
+
+	cp.buf = binary.AppendUvarint(cp.buf, uint64(len(val)))
+	cp.buf = append(cp.buf, val...)
+}
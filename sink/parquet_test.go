@@ -0,0 +1,307 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// thriftReader reads the thrift compact protocol, structs as maps by field id, enough for checking parquet metadata.
+type thriftReader struct {
+	data []byte
+}
+
+func (tr *thriftReader) uvarint() uint64 {
+
+	val, size := binary.Uvarint(tr.data)
+	Expect(size).To(BeNumerically(">", 0))
+	tr.data = tr.data[size:]
+	return val
+}
+
+func (tr *thriftReader) varint() int64 {
+
+	val, size := binary.Varint(tr.data)
+	Expect(size).To(BeNumerically(">", 0))
+	tr.data = tr.data[size:]
+	return val
+}
+
+func (tr *thriftReader) value(typ byte) any {
+
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case tI32, tI64:
+		return tr.varint()
+	case tBinary:
+		size := tr.uvarint()
+		val := tr.data[:size]
+		tr.data = tr.data[size:]
+		return val
+	case tList:
+		head := tr.data[0]
+		tr.data = tr.data[1:]
+		size := int(head >> 4)
+		if size == 15 {
+			size = int(tr.uvarint())
+		}
+		list := []any{}
+		for range size {
+			list = append(list, tr.value(head&0x0f))
+		}
+		return list
+	case tStruct:
+		return tr.fields()
+	}
+
+	Fail("unexpected thrift type")
+	return nil
+}
+
+func (tr *thriftReader) fields() map[int64]any {
+
+	fields := map[int64]any{}
+	var last int64
+	for {
+		head := tr.data[0]
+		tr.data = tr.data[1:]
+		if head == 0 {
+			return fields
+		}
+
+		id := last + int64(head>>4)
+		if head>>4 == 0 {
+			id = tr.varint()
+		}
+		last = id
+		fields[id] = tr.value(head & 0x0f)
+	}
+}
+
+// readParquet reads the rows of a file written by Parquet, as strings by column name, nulls omitted.
+func readParquet(path string) (rows []map[string]any) {
+
+	data, err := os.ReadFile(path)
+	Expect(err).ToNot(HaveOccurred())
+	Expect(string(data[:4])).To(Equal(parquetMagic))
+	Expect(string(data[len(data)-4:])).To(Equal(parquetMagic))
+
+	size := binary.LittleEndian.Uint32(data[len(data)-8:])
+	meta := (&thriftReader{data: data[len(data)-8-int(size) : len(data)-8]}).fields()
+
+	numRows := int(meta[3].(int64))
+	rows = make([]map[string]any, numRows)
+	for idx := range rows {
+		rows[idx] = map[string]any{}
+	}
+
+	schema := meta[2].([]any)
+	Expect(schema[0].(map[int64]any)[5]).To(BeEquivalentTo(len(schema) - 1))
+
+	group := meta[4].([]any)[0].(map[int64]any)
+	for idx, chunk := range group[1].([]any) {
+		elem := schema[idx+1].(map[int64]any)
+		name := string(elem[4].([]byte))
+		optional := elem[3].(int64) == int64(pqOptional)
+
+		colMeta := chunk.(map[int64]any)[3].(map[int64]any)
+		Expect(string(colMeta[3].([]any)[0].([]byte))).To(Equal(name))
+
+		rdr := &thriftReader{data: data[colMeta[9].(int64):]}
+		header := rdr.fields()
+		Expect(header[5].(map[int64]any)[1]).To(BeEquivalentTo(numRows))
+		page := rdr.data[:header[3].(int64)]
+
+		present := make([]bool, numRows)
+		for idx := range present {
+			present[idx] = true
+		}
+		if optional {
+			levels := &thriftReader{data: page[4 : 4+binary.LittleEndian.Uint32(page)]}
+			page = page[4+len(levels.data):]
+
+			row := 0
+			for len(levels.data) > 0 {
+				run := int(levels.uvarint() >> 1)
+				val := levels.data[0] == 1
+				levels.data = levels.data[1:]
+				for range run {
+					present[row] = val
+					row++
+				}
+			}
+		}
+
+		for row := range rows {
+			switch {
+			case !present[row]:
+			case elem[1].(int64) == int64(pqInt64):
+				rows[row][name] = time.UnixMicro(int64(binary.LittleEndian.Uint64(page))).UTC()
+				page = page[8:]
+			default:
+				length := binary.LittleEndian.Uint32(page)
+				rows[row][name] = string(page[4 : 4+length])
+				page = page[4+length:]
+			}
+		}
+	}
+
+	return
+}
+
+var _ = Describe("Parquet", func() {
+
+	var (
+		dir string
+		cfg *ParquetConfig
+		pq  *Parquet
+		lgr *sabot.Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		cfg = &ParquetConfig{Dir: dir, Columns: []string{"tenant", "status"}}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		pq, err = cfg.New()
+		Expect(err).ToNot(HaveOccurred())
+		lgr = &sabot.Sabot{Writer: pq}
+	})
+
+	files := func() []string {
+		found, err := filepath.Glob(filepath.Join(dir, "dt=*", "hour=*", "*.parquet"))
+		Expect(err).ToNot(HaveOccurred())
+		return found
+	}
+
+	When("closed with events buffered", func() {
+		It("should write them as columns", func() {
+			lgr.Info(ctx, "served", "tenant", "acme", "status", 200, "path", "/a")
+			lgr.Info(ctx, "served", "tenant", "bolt")
+			lgr.Info(ctx, "idle")
+			Expect(files()).To(BeEmpty())
+
+			Expect(pq.Close()).To(Succeed())
+
+			found := files()
+			Expect(found).To(HaveLen(1))
+
+			hour := time.Now().UTC().Truncate(time.Hour)
+			Expect(filepath.Dir(found[0])).To(Equal(filepath.Join(dir, "dt="+hour.Format("2006-01-02"), "hour="+hour.Format("15"))))
+
+			rows := readParquet(found[0])
+			Expect(rows).To(HaveLen(3))
+			Expect(rows[0]["ts"]).To(BeTemporally("~", time.Now(), time.Second))
+
+			delete(rows[0], "ts")
+			delete(rows[1], "ts")
+			delete(rows[2], "ts")
+			Expect(rows).To(Equal([]map[string]any{
+				{"level": "info", "msg": "served", "tenant": "acme", "status": "200", "fields": `{"path":"/a"}`},
+				{"level": "info", "msg": "served", "tenant": "bolt"},
+				{"level": "info", "msg": "idle"},
+			}))
+		})
+	})
+
+	When("events span hours", func() {
+		It("should write a file per hour", func() {
+			base := time.Date(2024, 1, 2, 3, 59, 0, 0, time.UTC)
+			Expect(pq.WriteEvent(&sabot.Event{Time: base, Level: "info", Msg: "one", Fields: sabot.Fields{}})).To(Succeed())
+			Expect(pq.WriteEvent(&sabot.Event{Time: base.Add(time.Minute), Level: "info", Msg: "two", Fields: sabot.Fields{}})).To(Succeed())
+			Expect(pq.Close()).To(Succeed())
+
+			Expect(files()).To(HaveLen(2))
+			Expect(filepath.Glob(filepath.Join(dir, "dt=2024-01-02", "hour=03", "*.parquet"))).To(HaveLen(1))
+			Expect(filepath.Glob(filepath.Join(dir, "dt=2024-01-02", "hour=04", "*.parquet"))).To(HaveLen(1))
+		})
+	})
+
+	When("a write fails", func() {
+		It("should keep the events for the next flush", func() {
+			blocker := filepath.Join(dir, "dt=2024-01-02")
+			Expect(os.WriteFile(blocker, nil, 0o644)).To(Succeed())
+
+			base := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+			Expect(pq.WriteEvent(&sabot.Event{Time: base, Level: "info", Msg: "one", Fields: sabot.Fields{}})).To(Succeed())
+			Expect(pq.WriteEvent(&sabot.Event{Time: base.Add(time.Hour), Level: "info", Msg: "two", Fields: sabot.Fields{}})).To(Succeed())
+			Expect(pq.Flush()).ToNot(Succeed())
+
+			Expect(os.Remove(blocker)).To(Succeed())
+			Expect(pq.Close()).To(Succeed())
+			Expect(files()).To(HaveLen(2))
+		})
+	})
+
+	When("closed twice", func() {
+		It("should not panic", func() {
+			Expect(pq.Close()).To(Succeed())
+			Expect(pq.Close()).To(Succeed())
+		})
+	})
+
+	When("max events are buffered", func() {
+		BeforeEach(func() {
+			cfg.MaxEvents = 2
+		})
+
+		It("should write them straight away", func() {
+			lgr.Info(ctx, "one")
+			Expect(files()).To(BeEmpty())
+			lgr.Info(ctx, "two")
+			Expect(files()).To(HaveLen(1))
+
+			Expect(pq.Close()).To(Succeed())
+			Expect(files()).To(HaveLen(1))
+		})
+	})
+
+	When("flush interval passes", func() {
+		BeforeEach(func() {
+			cfg.Flush = 20 * time.Millisecond
+		})
+
+		It("should write in the background", func() {
+			lgr.Info(ctx, "one")
+			Eventually(files).Should(HaveLen(1))
+			Expect(pq.Close()).To(Succeed())
+		})
+	})
+
+	When("many columns and rows", func() {
+		BeforeEach(func() {
+			cfg.Columns = []string{"c01", "c02", "c03", "c04", "c05", "c06", "c07", "c08", "c09", "c10", "c11", "c12", "c13"}
+		})
+
+		It("should still read back", func() {
+			for range 20 {
+				lgr.Info(ctx, "wide", "c13", "last", "c01", bytes.Repeat([]byte("x"), 3))
+			}
+			Expect(pq.Close()).To(Succeed())
+
+			rows := readParquet(files()[0])
+			Expect(rows).To(HaveLen(20))
+			Expect(rows[19]).To(HaveKeyWithValue("c13", "last"))
+			Expect(rows[19]).To(HaveKeyWithValue("c01", "eHh4"))
+			Expect(rows[19]).ToNot(HaveKey("fields"))
+		})
+	})
+
+	It("should refuse reserved columns", func() {
+		_, err := (&ParquetConfig{Dir: dir, Columns: []string{"msg"}}).New()
+		Expect(err).To(MatchError(`invalid parquet column: "msg"`))
+	})
+})
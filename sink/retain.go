@@ -0,0 +1,174 @@
+package sink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const purgeNotice = "purged rotated log files"
+
+// Purged summarizes rotated files removed by retention.
+type Purged struct {
+	Files []string `json:"files"`
+	Bytes int64    `json:"bytes"`
+}
+
+// Retain removes rotated files, oldest first, that are older than MaxAge
+// or push the total size of rotated files past MaxTotal.
+//
+// Only files named as rotated by this File, and their manifests, are removed;
+// the active file never is.
+func (fl *File) Retain() (purged Purged, err error) {
+
+	fl.mu.Lock()
+	defer fl.mu.Unlock()
+
+	if fl.lock != nil {
+		err = lockFile(fl.lock)
+		if err != nil {
+			return
+		}
+		defer unlockFile(fl.lock)
+	}
+
+	rotated, err := fl.rotated()
+	if err != nil {
+		return
+	}
+
+	var total int64
+	for _, rf := range rotated {
+		total += rf.size
+	}
+
+	cutoff := fl.now().UTC().Add(-fl.maxAge)
+
+	for _, rf := range rotated {
+		// age from end of day, when the last events were written
+
+		expired := fl.maxAge > 0 && rf.day.Add(24*time.Hour).Before(cutoff)
+		oversize := fl.maxTotal > 0 && total > fl.maxTotal
+		if !expired && !oversize {
+			break
+		}
+
+		err = os.Remove(rf.path)
+		if err != nil {
+			err = errors.Wrapf(err, "failed to remove rotated file")
+			return
+		}
+
+		// manifest may not exist when not sealing
+		mErr := os.Remove(ManifestPath(rf.path))
+		if mErr != nil && !os.IsNotExist(mErr) {
+			err = errors.Wrapf(mErr, "failed to remove manifest")
+			return
+		}
+
+		purged.Files = append(purged.Files, filepath.Base(rf.path))
+		purged.Bytes += rf.size
+		total -= rf.size
+	}
+
+	return
+}
+
+// RunRetention enforces retention every interval until ctx is done,
+// logging a summary of what was purged.
+func (fl *File) RunRetention(ctx context.Context, lgr *sabot.Sabot, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := fl.Retain()
+			if err != nil {
+				lgr.Error(ctx, "failed to enforce retention", err)
+			}
+			if len(purged.Files) > 0 {
+				lgr.Info(ctx, purgeNotice, "purged", purged, "count", len(purged.Files))
+			}
+		}
+	}
+}
+
+//
+// unexported
+//
+
+type rotatedFile struct {
+	path string
+	day  time.Time
+	seq  int
+	size int64
+}
+
+// rotated finds rotated files, oldest first.
+func (fl *File) rotated() (rotated []rotatedFile, err error) {
+
+	entries, err := os.ReadDir(fl.dir)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to read log dir")
+		return
+	}
+
+	prefix := fl.name + "-"
+	for _, entry := range entries {
+
+		base := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasPrefix(base, prefix) || !strings.HasSuffix(base, ".log") {
+			continue
+		}
+
+		// skip anything not dated, and optionally numbered, as by rotate
+
+		stamp, num, numbered := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(base, prefix), ".log"), ".")
+
+		day, pErr := time.Parse(dayLayout, stamp)
+		if pErr != nil {
+			continue
+		}
+
+		var seq int
+		if numbered {
+			seq, pErr = strconv.Atoi(num)
+			if pErr != nil || seq < 1 {
+				continue
+			}
+		}
+
+		info, iErr := entry.Info()
+		if iErr != nil {
+			err = errors.Wrapf(iErr, "failed to stat rotated file")
+			return
+		}
+
+		rotated = append(rotated, rotatedFile{
+			path: filepath.Join(fl.dir, base),
+			day:  day,
+			seq:  seq,
+			size: info.Size(),
+		})
+	}
+
+	sort.Slice(rotated, func(i, j int) bool {
+		if rotated[i].day.Equal(rotated[j].day) {
+			return rotated[i].seq < rotated[j].seq
+		}
+		return rotated[i].day.Before(rotated[j].day)
+	})
+	return
+}
@@ -0,0 +1,82 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retain", func() {
+
+	var (
+		dir    string
+		fl     *File
+		cfg    *FileConfig
+		purged Purged
+		err    error
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		cfg = &FileConfig{Dir: dir, Name: "app"}
+
+		for _, day := range []string{"2026-10-10", "2026-10-11", "2026-10-12"} {
+			path := filepath.Join(dir, "app-"+day+".log")
+			Expect(os.WriteFile(path, []byte(strings.Repeat("x", 100)), 0o644)).To(Succeed())
+		}
+		Expect(os.WriteFile(filepath.Join(dir, "app-backup.log"), []byte("keep"), 0o644)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		fl, err = cfg.New()
+		Expect(err).ToNot(HaveOccurred())
+		fl.now = func() time.Time { return time.Date(2026, 10, 13, 1, 0, 0, 0, time.UTC) }
+
+		purged, err = fl.Retain()
+	})
+
+	AfterEach(func() {
+		Expect(fl.Close()).To(Succeed())
+	})
+
+	When("files are older than max age", func() {
+		BeforeEach(func() {
+			cfg.MaxAge = 48 * time.Hour
+		})
+
+		It("should purge them", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged).To(Equal(Purged{Files: []string{"app-2026-10-10.log"}, Bytes: 100}))
+		})
+	})
+
+	When("files exceed max total", func() {
+		BeforeEach(func() {
+			cfg.MaxTotal = 150
+		})
+
+		It("should purge the oldest and leave others alone", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged.Files).To(Equal([]string{"app-2026-10-10.log", "app-2026-10-11.log"}))
+
+			entries, err := os.ReadDir(dir)
+			Expect(err).ToNot(HaveOccurred())
+			names := []string{}
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			Expect(names).To(ConsistOf("app-2026-10-12.log", "app-backup.log", "app.log"))
+		})
+	})
+
+	When("no limits are set", func() {
+		It("should purge nothing", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(purged.Files).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,186 @@
+package sink
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Manifest describes a sealed log file.
+type Manifest struct {
+	// File is the base name of the sealed file.
+	File string `json:"file"`
+	// Day is the date the file's events were written.
+	Day string `json:"day"`
+	// Lines is the count of lines in the file.
+	Lines int64 `json:"lines"`
+	// Bytes is the size of the file.
+	Bytes int64 `json:"bytes"`
+	// Hash is the hex sha256 of the file's content.
+	Hash string `json:"hash"`
+	// Signature is the hex ed25519 signature of the manifest sans signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// ManifestPath returns the path of the manifest for a sealed log file.
+func ManifestPath(logPath string) string {
+
+	return strings.TrimSuffix(logPath, filepath.Ext(logPath)) + ".manifest.json"
+}
+
+// VerifySeal checks a log file against its manifest and the manifest's signature.
+func VerifySeal(logPath string, key ed25519.PublicKey) (err error) {
+
+	data, err := os.ReadFile(ManifestPath(logPath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read manifest")
+	}
+
+	mfst := Manifest{}
+	err = json.Unmarshal(data, &mfst)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unmarshal manifest")
+	}
+
+	sig, err := hex.DecodeString(mfst.Signature)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode signature")
+	}
+
+	if !ed25519.Verify(key, mfst.signed(), sig) {
+		return errors.Errorf("manifest signature is invalid")
+	}
+
+	slr := &sealer{}
+	err = slr.resume(logPath)
+	if err != nil {
+		return
+	}
+
+	if slr.lines != mfst.Lines || slr.bytes != mfst.Bytes || slr.sum() != mfst.Hash {
+		return errors.Errorf("log file does not match manifest")
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+// sealer keeps a running hash of written content, a nil sealer doing nothing.
+type sealer struct {
+	key   ed25519.PrivateKey
+	hash  hash.Hash
+	lines int64
+	bytes int64
+}
+
+func newSealer(seed string) (slr *sealer, err error) {
+
+	data, err := hex.DecodeString(seed)
+	if err != nil || len(data) != ed25519.SeedSize {
+		err = errors.Errorf("seal key must be %d hex encoded bytes", ed25519.SeedSize)
+		return
+	}
+
+	slr = &sealer{
+		key:  ed25519.NewKeyFromSeed(data),
+		hash: sha256.New(),
+	}
+	return
+}
+
+func (slr *sealer) add(data []byte) {
+
+	if slr == nil {
+		return
+	}
+
+	slr.hash.Write(data)
+	slr.bytes += int64(len(data))
+	for _, bt := range data {
+		if bt == '\n' {
+			slr.lines++
+		}
+	}
+}
+
+func (slr *sealer) sum() string {
+
+	return hex.EncodeToString(slr.hash.Sum(nil))
+}
+
+func (slr *sealer) resume(path string) (err error) {
+
+	if slr == nil {
+		return
+	}
+
+	// rebuild running hash from existing content, as after a restart
+
+	slr.hash = sha256.New()
+	slr.lines = 0
+	slr.bytes = 0
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file for hashing")
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	buf := make([]byte, 32*1024)
+	for {
+		n, rErr := reader.Read(buf)
+		slr.add(buf[:n])
+
+		if rErr == io.EOF {
+			return
+		}
+		if rErr != nil {
+			return errors.Wrapf(rErr, "failed to read log file for hashing")
+		}
+	}
+}
+
+func (slr *sealer) seal(path, day string) (err error) {
+
+	if slr == nil {
+		return
+	}
+
+	mfst := Manifest{
+		File:  filepath.Base(path),
+		Day:   day,
+		Lines: slr.lines,
+		Bytes: slr.bytes,
+		Hash:  slr.sum(),
+	}
+	mfst.Signature = hex.EncodeToString(ed25519.Sign(slr.key, mfst.signed()))
+
+	data, err := json.MarshalIndent(mfst, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal manifest")
+	}
+
+	err = os.WriteFile(ManifestPath(path), append(data, '\n'), 0o644)
+	return errors.Wrapf(err, "failed to write manifest")
+}
+
+func (mfst Manifest) signed() []byte {
+
+	mfst.Signature = ""
+	data, _ := json.Marshal(mfst)
+
+	return data
+}
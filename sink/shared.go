@@ -0,0 +1,54 @@
+package sink
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+//
+// unexported
+//
+
+func (fl *File) lockPath() string {
+
+	return filepath.Join(fl.dir, fl.name+".lock")
+}
+
+// openShared opens the lock file and, holding it, the active file.
+func (fl *File) openShared() (err error) {
+
+	fl.lock, err = os.OpenFile(fl.lockPath(), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open lock file")
+	}
+
+	err = lockFile(fl.lock)
+	if err != nil {
+		return
+	}
+	defer unlockFile(fl.lock)
+
+	return fl.open()
+}
+
+// refresh reopens the active file when another process has rotated it, lock held.
+func (fl *File) refresh() (err error) {
+
+	current, err := fl.file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat log file")
+	}
+
+	active, err := os.Stat(fl.activePath())
+	switch {
+	case err == nil && os.SameFile(current, active):
+		fl.size = current.Size()
+		return
+	case err != nil && !os.IsNotExist(err):
+		return errors.Wrapf(err, "failed to stat active log file")
+	}
+
+	return fl.reopen()
+}
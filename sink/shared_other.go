@@ -0,0 +1,20 @@
+//go:build !unix
+
+package sink
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+//
+// unexported
+//
+
+func lockFile(file *os.File) error {
+
+	return errors.Errorf("shared log files are not supported on this platform")
+}
+
+func unlockFile(file *os.File) {}
@@ -0,0 +1,90 @@
+package sink
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shared", func() {
+
+	var (
+		dir     string
+		workers []*File
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+
+		// each worker stands in for a separate process, with its own descriptors
+
+		workers = nil
+		for i := 0; i < 3; i++ {
+			cfg := &FileConfig{Dir: dir, Name: "app", Shared: true, MaxSize: 2000}
+			fl, err := cfg.New()
+			Expect(err).ToNot(HaveOccurred())
+			workers = append(workers, fl)
+		}
+	})
+
+	AfterEach(func() {
+		for _, fl := range workers {
+			Expect(fl.Close()).To(Succeed())
+		}
+	})
+
+	When("workers write and rotate concurrently", func() {
+		JustBeforeEach(func() {
+			wg := sync.WaitGroup{}
+			for w, fl := range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						_, err := fmt.Fprintf(fl, `{"msg":"shared","worker":%d,"idx":%d}`+"\n", w, i)
+						Expect(err).ToNot(HaveOccurred())
+					}
+				}()
+			}
+			wg.Wait()
+		})
+
+		It("should neither tear nor lose lines", func() {
+			paths, err := filepath.Glob(filepath.Join(dir, "app*.log"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(paths)).To(BeNumerically(">", 2))
+
+			count := 0
+			for _, path := range paths {
+				file, err := os.Open(path)
+				Expect(err).ToNot(HaveOccurred())
+
+				scanner := bufio.NewScanner(file)
+				for scanner.Scan() {
+					Expect(json.Valid(scanner.Bytes())).To(BeTrue())
+					count++
+				}
+				Expect(file.Close()).To(Succeed())
+
+				info, err := os.Stat(path)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(info.Size()).To(BeNumerically("<=", 2000))
+			}
+			Expect(count).To(Equal(300))
+		})
+	})
+
+	When("sealing", func() {
+		It("should error", func() {
+			cfg := &FileConfig{Dir: dir, Name: "app", Shared: true, SealKey: "00"}
+			_, err := cfg.New()
+			Expect(err).To(MatchError("sealing is not supported for shared log files"))
+		})
+	})
+})
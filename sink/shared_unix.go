@@ -0,0 +1,27 @@
+//go:build unix
+
+package sink
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+//
+// unexported
+//
+
+func lockFile(file *os.File) error {
+
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+	return errors.Wrapf(err, "failed to lock log file")
+}
+
+func unlockFile(file *os.File) {
+
+	// unlock fails only for a bad descriptor, and closing releases the lock regardless
+
+	_ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,80 @@
+package sabot
+
+import (
+	"sort"
+	"sync"
+)
+
+// SiteStats counts events and bytes by call site, when set on Sabot,
+// so that the lines dominating ingest can be found and cleaned up.
+type SiteStats struct {
+	counts map[uintptr]*SiteCount
+	mu     sync.Mutex
+}
+
+// SiteCount is the volume logged from a call site.
+type SiteCount struct {
+	Site   string `json:"site"`
+	Events int64  `json:"events"`
+	Bytes  int64  `json:"bytes"`
+}
+
+// Top returns the n call sites logging the most bytes, all when n is zero.
+func (sts *SiteStats) Top(n int) (top []SiteCount) {
+
+	sts.mu.Lock()
+	for _, count := range sts.counts {
+		top = append(top, *count)
+	}
+	sts.mu.Unlock()
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Bytes == top[j].Bytes {
+			return top[i].Site < top[j].Site
+		}
+		return top[i].Bytes > top[j].Bytes
+	})
+
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return
+}
+
+// Reset clears counts.
+func (sts *SiteStats) Reset() {
+
+	sts.mu.Lock()
+	defer sts.mu.Unlock()
+
+	sts.counts = nil
+}
+
+//
+// unexported
+//
+
+func (sts *SiteStats) count(size int) {
+
+	if sts == nil {
+		return
+	}
+
+	pc, site := caller(0)
+
+	sts.mu.Lock()
+	defer sts.mu.Unlock()
+
+	if sts.counts == nil {
+		sts.counts = map[uintptr]*SiteCount{}
+	}
+
+	count, ok := sts.counts[pc]
+	if !ok {
+		count = &SiteCount{Site: site}
+		sts.counts[pc] = count
+	}
+
+	count.Events++
+	count.Bytes += int64(size)
+}
@@ -0,0 +1,53 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sites", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = (&Config{SiteStats: true}).New(buf)
+		ctx = context.Background()
+	})
+
+	When("logging from several call sites", func() {
+		It("should report the noisiest first", func() {
+			for i := 0; i < 3; i++ {
+				lgr.Info(ctx, "quiet")
+			}
+			lgr.Info(ctx, "noisy", "body", strings.Repeat("n", 500))
+
+			top := lgr.Sites.Top(1)
+			Expect(top).To(HaveLen(1))
+			Expect(top[0].Site).To(MatchRegexp(`sites_test\.go:\d+$`))
+			Expect(top[0].Events).To(Equal(int64(1)))
+			Expect(top[0].Bytes).To(BeNumerically(">", 500))
+
+			all := lgr.Sites.Top(0)
+			Expect(all).To(HaveLen(2))
+			Expect(all[1].Events).To(Equal(int64(3)))
+		})
+	})
+
+	When("reset", func() {
+		It("should clear counts", func() {
+			lgr.Info(ctx, "counted")
+			lgr.Sites.Reset()
+
+			Expect(lgr.Sites.Top(0)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,92 @@
+package slogger
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Backend is a writer for Sabot handing events to an slog.Handler,
+// so that the ctx fields api can be kept while output goes via the slog handler ecosystem.
+//
+// For example, lgr := cfg.New(slogger.NewBackend(handler)).
+// Fields become attrs, ordered by key, and levels map by value, slog and sabot sharing debug through error.
+type Backend struct {
+	handler slog.Handler
+}
+
+// NewBackend creates a Backend handing events to handler.
+func NewBackend(handler slog.Handler) *Backend {
+
+	return &Backend{handler: handler}
+}
+
+// WriteEvent hands an event to the handler as a record, when the handler is enabled for its level.
+func (be *Backend) WriteEvent(evt *sabot.Event) error {
+
+	// ctx is not carried with events, its fields having been merged already
+
+	ctx := context.Background()
+
+	level := slogLevel(evt.Level)
+	if !be.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	keys := make([]string, 0, len(evt.Fields))
+	for key := range evt.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rec := slog.NewRecord(evt.Time, level, evt.Msg, 0)
+	for _, key := range keys {
+		rec.AddAttrs(slog.Any(key, evt.Fields[key]))
+	}
+
+	return be.handler.Handle(ctx, rec)
+}
+
+// Write decodes encoded events and hands each to the handler, for when Sabot writes bytes rather than events.
+func (be *Backend) Write(data []byte) (n int, err error) {
+
+	scn := sabot.NewScanner(bytes.NewReader(data))
+	for scn.Scan() {
+
+		var evt *sabot.Event
+		evt, err = scn.Event()
+		if err != nil {
+			return
+		}
+
+		err = be.WriteEvent(evt)
+		if err != nil {
+			return
+		}
+	}
+
+	err = scn.Err()
+	if err != nil {
+		return
+	}
+
+	return len(data), nil
+}
+
+//
+// unexported
+//
+
+// slogLevel maps a sabot level name to slog's, taking unknown names as info.
+func slogLevel(name string) slog.Level {
+
+	lvl, err := sabot.ParseLevel(name)
+	if err != nil {
+		return slog.LevelInfo
+	}
+
+	return slog.Level(lvl)
+}
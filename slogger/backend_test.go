@@ -0,0 +1,58 @@
+package slogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+var _ = Describe("Backend", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *sabot.Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		handler := slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})
+		lgr = (&sabot.Config{}).New(NewBackend(handler))
+		ctx = lgr.WithFields(context.Background(), "run_id", "123")
+	})
+
+	When("logging via sabot", func() {
+		JustBeforeEach(func() {
+			lgr.Info(ctx, "skipped by handler")
+			lgr.Warn(ctx, "retrying", "attempt", 2)
+		})
+
+		It("should hand events to the slog handler", func() {
+			fields := map[string]any{}
+			Expect(json.Unmarshal(buf.Bytes(), &fields)).To(Succeed())
+			delete(fields, "time")
+
+			Expect(fields).To(Equal(map[string]any{
+				"level":   "WARN",
+				"msg":     "retrying",
+				"run_id":  "123",
+				"attempt": float64(2),
+			}))
+		})
+	})
+
+	When("writing encoded events", func() {
+		It("should decode and hand them on", func() {
+			be := NewBackend(slog.NewJSONHandler(buf, nil))
+			_, err := be.Write([]byte(`{"ts":"2026-10-15T01:02:03Z","level":"error","msg":"failed","error":"oops"}` + "\n"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(buf.String()).To(ContainSubstring(`"level":"ERROR","msg":"failed","error":"oops"`))
+		})
+	})
+})
@@ -0,0 +1,151 @@
+// Package slogger bridges Sabot and log/slog in both directions.
+//
+// Handler adapts Sabot as an slog.Handler, so that libraries accepting a *slog.Logger
+// log through sabot, with its encoding and ctx fields.
+// Attrs become fields, with keys in groups dotted as "group.key".
+//
+// Backend adapts an slog.Handler as a Sabot writer, so that sabot output goes via slog handlers.
+package slogger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/clarktrimble/sabot"
+)
+
+// Handler is an slog.Handler logging via Sabot.
+type Handler struct {
+	lgr    *sabot.Sabot
+	kv     []any
+	prefix string
+}
+
+// New creates a Handler logging via lgr.
+func New(lgr *sabot.Sabot) *Handler {
+
+	return &Handler{lgr: lgr}
+}
+
+// Enabled reports whether lgr logs at level.
+func (hdl *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+
+	return hdl.lgr.Enabled(ctx, levelOf(level))
+}
+
+// Handle logs a record, with attrs from WithAttrs ahead of the record's own.
+func (hdl *Handler) Handle(ctx context.Context, rec slog.Record) error {
+
+	kv := make([]any, 0, len(hdl.kv)+rec.NumAttrs()*2)
+	kv = append(kv, hdl.kv...)
+
+	rec.Attrs(func(attr slog.Attr) bool {
+		kv = appendAttr(kv, hdl.prefix, attr)
+		return true
+	})
+
+	hdl.lgr.Log(ctx, levelOf(rec.Level), rec.Message, kv...)
+	return nil
+}
+
+// WithAttrs returns a Handler adding attrs to each record.
+func (hdl *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+
+	clone := *hdl
+	clone.kv = append([]any(nil), hdl.kv...)
+	for _, attr := range attrs {
+		clone.kv = appendAttr(clone.kv, hdl.prefix, attr)
+	}
+
+	return &clone
+}
+
+// WithGroup returns a Handler qualifying subsequent attr keys with name.
+func (hdl *Handler) WithGroup(name string) slog.Handler {
+
+	if name == "" {
+		return hdl
+	}
+
+	clone := *hdl
+	clone.prefix = hdl.prefix + name + "."
+
+	return &clone
+}
+
+//
+// unexported
+//
+
+// appendAttr appends an attr as key-value pairs, flattening groups to dotted keys.
+func appendAttr(kv []any, prefix string, attr slog.Attr) []any {
+
+	val := attr.Value.Resolve()
+
+	if val.Kind() == slog.KindGroup {
+		// an unnamed group is inlined, per slog convention
+
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, member := range val.Group() {
+			kv = appendAttr(kv, prefix, member)
+		}
+		return kv
+	}
+
+	if attr.Key == "" {
+		return kv
+	}
+
+	return append(kv, prefix+attr.Key, valueOf(val))
+}
+
+func valueOf(val slog.Value) any {
+
+	// narrow to types sabot logs as-is, rather than marshaling
+
+	switch val.Kind() {
+	case slog.KindInt64:
+		return val.Int64()
+	case slog.KindUint64:
+		return int64(val.Uint64()) //nolint: gosec
+	case slog.KindFloat64:
+		return val.Float64()
+	case slog.KindString:
+		return val.String()
+	case slog.KindDuration:
+		return val.Duration()
+	case slog.KindTime:
+		return val.Time()
+	case slog.KindBool:
+		return val.Bool()
+	}
+
+	return val.Any()
+}
+
+// levelOf maps slog levels onto sabot's, which share values for debug through error.
+//
+// Levels above error are taken as error, leaving fatal and audit to sabot,
+// and unregistered levels as the built-in just below.
+func levelOf(level slog.Level) sabot.Level {
+
+	lvl := sabot.Level(level)
+	if lvl >= sabot.Error {
+		return sabot.Error
+	}
+
+	_, err := sabot.ParseLevel(lvl.String())
+	if err == nil {
+		return lvl
+	}
+
+	for _, known := range []sabot.Level{sabot.Warn, sabot.Info, sabot.Debug} {
+		if lvl > known {
+			return known
+		}
+	}
+
+	return sabot.Trace
+}
@@ -0,0 +1,81 @@
+package slogger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestSlogger(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Slogger Suite")
+}
+
+var _ = Describe("Handler", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *sabot.Sabot
+		slg *slog.Logger
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &sabot.Sabot{Writer: buf}
+		slg = slog.New(New(lgr))
+		ctx = lgr.WithFields(context.Background(), "run_id", "123")
+	})
+
+	When("logging with attrs and groups", func() {
+		JustBeforeEach(func() {
+			slg.With("component", "fetcher").WithGroup("http").InfoContext(ctx, "fetched",
+				"status", 200,
+				slog.Group("req", "method", "GET"),
+				slog.Duration("elapsed", time.Second),
+			)
+		})
+
+		It("should log fields with dotted keys and ctx fields", func() {
+			fields := sabot.Fields{}
+			Expect(json.Unmarshal(buf.Bytes(), &fields)).To(Succeed())
+			delete(fields, "ts")
+
+			Expect(fields).To(Equal(sabot.Fields{
+				"level":           "info",
+				"msg":             "fetched",
+				"run_id":          "123",
+				"component":       "fetcher",
+				"http.status":     float64(200),
+				"http.req.method": "GET",
+				"http.elapsed":    float64(time.Second),
+			}))
+		})
+	})
+
+	When("logging below the logger's level", func() {
+		It("should not be enabled", func() {
+			Expect(slg.Enabled(ctx, slog.LevelDebug)).To(BeFalse())
+
+			slg.DebugContext(ctx, "skipped")
+			Expect(buf.Len()).To(BeZero())
+		})
+	})
+
+	When("logging at levels sabot doesn't have", func() {
+		It("should map to the nearest below", func() {
+			Expect(levelOf(slog.LevelInfo + 1)).To(Equal(sabot.Info))
+			Expect(levelOf(slog.LevelError + 4)).To(Equal(sabot.Error))
+			Expect(levelOf(slog.LevelDebug - 1)).To(Equal(sabot.Trace))
+			Expect(levelOf(slog.LevelWarn)).To(Equal(sabot.Warn))
+		})
+	})
+})
@@ -0,0 +1,40 @@
+package sabot
+
+import (
+	"io"
+	"os"
+)
+
+// NewSplit creates a Sabot from Config writing info and below to stdout,
+// and warn through fatal to stderr, as several PaaS log routers expect.
+func (cfg *Config) NewSplit() *Sabot {
+
+	sabot := cfg.New(os.Stdout)
+	WithSplit(os.Stdout, os.Stderr)(sabot)
+
+	return sabot
+}
+
+// WithSplit sets Writer to out, and routes warn through fatal to errOut, formatted alike.
+//
+// Levels registered between warn and fatal before the option is applied are routed as well,
+// while audit stays with out, being a record rather than a problem.
+func WithSplit(out, errOut io.Writer) Option {
+
+	return func(sabot *Sabot) {
+		sabot.Writer = out
+
+		if sabot.Routes == nil {
+			sabot.Routes = map[Level]io.Writer{}
+		}
+
+		levelMu.RLock()
+		defer levelMu.RUnlock()
+
+		for lvl := range levelNames {
+			if lvl >= Warn && lvl <= Fatal {
+				sabot.Routes[lvl] = errOut
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split", func() {
+
+	var (
+		out    *bytes.Buffer
+		errOut *bytes.Buffer
+		lgr    *Sabot
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		errOut = &bytes.Buffer{}
+		lgr = (&Config{}).New(nil).Clone(WithSplit(out, errOut))
+		ctx = context.Background()
+	})
+
+	When("logging at each level", func() {
+		JustBeforeEach(func() {
+			lgr.SetLevel(Trace)
+			lgr.Trace(ctx, "tracing")
+			lgr.Info(ctx, "routine")
+			lgr.Warn(ctx, "retrying")
+			lgr.Error(ctx, "failed", nil)
+			Expect(lgr.Audit(ctx, "granted", "actor", "alice", "target", "role", "outcome", "success")).To(Succeed())
+		})
+
+		It("should split info and below from warn and above", func() {
+			Expect(msgs(out)).To(Equal([]string{"tracing", "routine", "granted"}))
+			Expect(msgs(errOut)).To(Equal([]string{"retrying", "failed"}))
+		})
+	})
+})
+
+func msgs(buf *bytes.Buffer) (msgs []string) {
+
+	for evt, err := range Decode(buf) {
+		Expect(err).ToNot(HaveOccurred())
+		msgs = append(msgs, evt.Msg)
+	}
+
+	return
+}
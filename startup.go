@@ -0,0 +1,77 @@
+package sabot
+
+import (
+	"context"
+	"net"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+const startupMsg = "starting"
+
+// Startup logs a single starting event with config, build info, and host facts.
+//
+// Extras are key-value pairs, such as listening addresses, with net.Listener
+// and net.Addr values logged as their address.
+func (sabot *Sabot) Startup(ctx context.Context, cfg any, extras ...any) {
+
+	kv := []any{
+		"config", cfg,
+		"build", buildInfo(),
+		"host", hostInfo(),
+	}
+
+	for i, extra := range extras {
+		switch val := extra.(type) {
+		case net.Listener:
+			extras[i] = val.Addr().String()
+		case net.Addr:
+			extras[i] = val.String()
+		}
+	}
+
+	sabot.Info(ctx, startupMsg, append(kv, extras...)...)
+}
+
+//
+// unexported
+//
+
+func buildInfo() map[string]string {
+
+	info := map[string]string{
+		"go_version": runtime.Version(),
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+
+	info["path"] = bi.Main.Path
+	info["version"] = bi.Main.Version
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			info[setting.Key] = setting.Value
+		}
+	}
+
+	return info
+}
+
+func hostInfo() map[string]any {
+
+	// hostname is best effort
+
+	hostname, _ := os.Hostname()
+
+	return map[string]any{
+		"hostname": hostname,
+		"pid":      os.Getpid(),
+		"os":       runtime.GOOS,
+		"arch":     runtime.GOARCH,
+		"num_cpu":  runtime.NumCPU(),
+	}
+}
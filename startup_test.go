@@ -0,0 +1,53 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Startup", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+		cfg *Config
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = context.Background()
+		cfg = &Config{MaxLen: 99}
+	})
+
+	When("logging startup with a listener", func() {
+		It("should log config, build, host, and address", func() {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).ToNot(HaveOccurred())
+			defer listener.Close()
+
+			lgr.Startup(ctx, cfg, "listen", listener)
+
+			fields := delog(buf)
+			Expect(fields["msg"]).To(Equal("starting"))
+			Expect(fields["config"]).To(ContainSubstring(`"max_len":99`))
+			Expect(fields["listen"]).To(Equal(listener.Addr().String()))
+
+			build := map[string]string{}
+			Expect(json.Unmarshal([]byte(fields["build"].(string)), &build)).To(Succeed()) //nolint: forcetypeassert
+			Expect(build["go_version"]).To(Equal(runtime.Version()))
+
+			host := map[string]any{}
+			Expect(json.Unmarshal([]byte(fields["host"].(string)), &host)).To(Succeed()) //nolint: forcetypeassert
+			Expect(host).To(HaveKeyWithValue("os", runtime.GOOS))
+			Expect(host).To(HaveKey("pid"))
+		})
+	})
+})
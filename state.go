@@ -0,0 +1,158 @@
+package sabot
+
+import (
+	"expvar"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const topSites int = 10
+
+// Stats counts what happens to events, when set on Sabot.
+type Stats struct {
+	written     atomic.Int64
+	dropped     atomic.Int64
+	writeErrors atomic.Int64
+	lastError   string
+	lastErrorAt time.Time
+	mu          sync.Mutex
+}
+
+// QueueDepther is implemented by writers that buffer, so their depth is seen in State.
+type QueueDepther interface {
+	QueueDepth() int
+}
+
+// State is a snapshot of a Sabot's settings and stats, answering "why aren't my debug lines showing up".
+type State struct {
+	Writer      string            `json:"writer"`
+	AltWriter   string            `json:"alt_writer,omitempty"`
+	Routes      map[string]string `json:"routes,omitempty"`
+	QueueDepth  *int              `json:"queue_depth,omitempty"`
+	Pressure    float64           `json:"pressure,omitempty"`
+	Format      string            `json:"format"`
+	MaxLen      int               `json:"max_len"`
+	MinLevel    string            `json:"min_level"`
+	EnableDebug bool              `json:"enable_debug"`
+	EnableTrace bool              `json:"enable_trace"`
+	Disabled    []string          `json:"disabled,omitempty"`
+	Hooks       int               `json:"hooks"`
+	Written     int64             `json:"written"`
+	Dropped     int64             `json:"dropped"`
+	WriteErrors int64             `json:"write_errors"`
+	LastError   string            `json:"last_error,omitempty"`
+	LastErrorAt time.Time         `json:"last_error_at"`
+	TopSites    []SiteCount       `json:"top_sites,omitempty"`
+}
+
+// State returns a snapshot of settings and, when Stats is set, counts.
+func (sabot *Sabot) State() (state State) {
+
+	state = State{
+		Writer:      typeOf(sabot.Writer),
+		AltWriter:   typeOf(sabot.AltWriter),
+		Format:      "json",
+		MaxLen:      sabot.MaxLen,
+		MinLevel:    sabot.GetLevel().String(),
+		EnableDebug: sabot.EnableDebug,
+		EnableTrace: sabot.EnableTrace,
+		Hooks:       len(sabot.Hooks),
+		Pressure:    sabot.Pressure(),
+	}
+
+	if sabot.Encoder != nil {
+		state.Format = fmt.Sprintf("%T", sabot.Encoder)
+	}
+
+	qd, ok := sabot.Writer.(QueueDepther)
+	if ok {
+		depth := qd.QueueDepth()
+		state.QueueDepth = &depth
+	}
+
+	for lvl, writer := range sabot.Routes {
+		if state.Routes == nil {
+			state.Routes = map[string]string{}
+		}
+		state.Routes[lvl.String()] = typeOf(writer)
+	}
+
+	for lvl, disabled := range sabot.Disabled {
+		if disabled {
+			state.Disabled = append(state.Disabled, lvl.String())
+		}
+	}
+	sort.Strings(state.Disabled)
+
+	if sabot.Sites != nil {
+		state.TopSites = sabot.Sites.Top(topSites)
+	}
+
+	sts := sabot.Stats
+	if sts == nil {
+		return
+	}
+
+	state.Written = sts.written.Load()
+	state.Dropped = sts.dropped.Load()
+	state.WriteErrors = sts.writeErrors.Load()
+
+	sts.mu.Lock()
+	state.LastError = sts.lastError
+	state.LastErrorAt = sts.lastErrorAt
+	sts.mu.Unlock()
+
+	return
+}
+
+// Publish exposes State via expvar under name, panicking if name is already published as does expvar.
+func (sabot *Sabot) Publish(name string) {
+
+	expvar.Publish(name, expvar.Func(func() any {
+		return sabot.State()
+	}))
+}
+
+//
+// unexported
+//
+
+func (sts *Stats) wrote(err error) {
+
+	if sts == nil {
+		return
+	}
+
+	if err == nil {
+		sts.written.Add(1)
+		return
+	}
+
+	sts.writeErrors.Add(1)
+
+	sts.mu.Lock()
+	sts.lastError = err.Error()
+	sts.lastErrorAt = time.Now().UTC()
+	sts.mu.Unlock()
+}
+
+func (sts *Stats) drop() {
+
+	if sts == nil {
+		return
+	}
+
+	sts.dropped.Add(1)
+}
+
+func typeOf(obj any) string {
+
+	if obj == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%T", obj)
+}
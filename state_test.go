@@ -0,0 +1,81 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fullWriter struct{}
+
+func (fw *fullWriter) Write(p []byte) (n int, err error) {
+
+	err = fmt.Errorf("disk is full")
+	return
+}
+
+func (fw *fullWriter) QueueDepth() int {
+
+	return 3
+}
+
+var _ = Describe("State", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = (&Config{Format: "logfmt"}).New(buf)
+		ctx = context.Background()
+	})
+
+	When("events are written and dropped", func() {
+		It("should count them", func() {
+			lgr.Hooks = []Hook{func(ctx context.Context, evt *Event) bool { return evt.Msg != "drop me" }}
+			lgr.Routes = map[Level]io.Writer{Error: &fullWriter{}}
+			lgr.Disabled = map[Level]bool{Trace: true}
+
+			lgr.Info(ctx, "keep me")
+			lgr.Info(ctx, "drop me")
+			lgr.Error(ctx, "fail me", nil)
+
+			state := lgr.State()
+			Expect(state.Writer).To(Equal("*bytes.Buffer"))
+			Expect(state.Routes).To(Equal(map[string]string{"error": "*sabot.fullWriter"}))
+			Expect(state.Format).To(Equal("sabot.Logfmt"))
+			Expect(state.Disabled).To(Equal([]string{"trace"}))
+			Expect(state.Written).To(Equal(int64(1)))
+			Expect(state.Dropped).To(Equal(int64(1)))
+			Expect(state.WriteErrors).To(Equal(int64(1)))
+			Expect(state.LastError).To(Equal("disk is full"))
+		})
+	})
+
+	When("writer reports queue depth", func() {
+		It("should include it", func() {
+			lgr.Writer = &fullWriter{}
+			Expect(*lgr.State().QueueDepth).To(Equal(3))
+		})
+	})
+
+	When("published via expvar", func() {
+		It("should serve the state as json", func() {
+			lgr.Info(ctx, "counted")
+			lgr.Publish("sabot_state_test")
+
+			state := State{}
+			Expect(json.Unmarshal([]byte(expvar.Get("sabot_state_test").String()), &state)).To(Succeed())
+			Expect(state.Written).To(Equal(int64(1)))
+		})
+	})
+})
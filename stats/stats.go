@@ -0,0 +1,282 @@
+// Package stats implements summary statistics of sabot events, for quick triage from a raw file.
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const sparks string = "▁▂▃▄▅▆▇█"
+
+// digits are replaced in fingerprints so that ids and counts don't split otherwise like events.
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// Config is the configurable fields of Summary.
+type Config struct {
+	Fields []string `json:"fields" desc:"numeric field keys to summarize with p50 and p95"`
+	Top    int      `json:"top" desc:"number of most frequent msgs and fingerprints shown, all when zero"`
+	Width  int      `json:"width" desc:"number of buckets in the timeline sparkline, none when zero"`
+}
+
+// New creates a Summary from Config.
+func (cfg *Config) New() *Summary {
+
+	values := make(map[string][]float64, len(cfg.Fields))
+	for _, key := range cfg.Fields {
+		values[key] = nil
+	}
+
+	return &Summary{
+		Fields:       cfg.Fields,
+		Top:          cfg.Top,
+		Width:        cfg.Width,
+		Levels:       map[string]int{},
+		Msgs:         map[string]int{},
+		Fingerprints: map[string]int{},
+		values:       values,
+	}
+}
+
+// Summary accumulates counts, numeric field values, and timestamps of events.
+type Summary struct {
+	// Fields are the numeric fields summarized.
+	Fields []string
+	// Top is the number of most frequent msgs and fingerprints shown, all when zero.
+	Top int
+	// Width is the number of buckets in the timeline, none when zero.
+	Width int
+	// Events is the count of events added.
+	Events int
+	// Levels is the count of events by level.
+	Levels map[string]int
+	// Msgs is the count of events by msg.
+	Msgs map[string]int
+	// Fingerprints is the count of events by fingerprint.
+	Fingerprints map[string]int
+	values       map[string][]float64
+	times        []time.Time
+}
+
+// Add adds an event to the summary.
+func (sum *Summary) Add(evt sabot.Event) {
+
+	sum.Events++
+	sum.Levels[evt.Level]++
+	sum.Msgs[evt.Msg]++
+	sum.Fingerprints[Fingerprint(evt)]++
+
+	for key := range sum.values {
+		val, ok := number(evt.Fields[key])
+		if ok {
+			sum.values[key] = append(sum.values[key], val)
+		}
+	}
+
+	if !evt.Time.IsZero() {
+		sum.times = append(sum.times, evt.Time)
+	}
+}
+
+// Percentile returns the nearest-rank percentile, from zero to 100, of a field's values.
+// The count of values is also returned, zero when there are none to rank.
+func (sum *Summary) Percentile(key string, pct float64) (val float64, count int) {
+
+	vals := sum.values[key]
+	count = len(vals)
+	if count == 0 {
+		return
+	}
+
+	sort.Float64s(vals)
+
+	rank := int(math.Ceil(pct / 100 * float64(count)))
+	rank = min(max(rank, 1), count)
+
+	return vals[rank-1], count
+}
+
+// Timeline returns a sparkline of event counts over time, in Width buckets from first to last event,
+// along with the span covered.
+func (sum *Summary) Timeline() (line string, first, last time.Time) {
+
+	if sum.Width < 1 || len(sum.times) == 0 {
+		return
+	}
+
+	first, last = sum.times[0], sum.times[0]
+	for _, ts := range sum.times {
+		if ts.Before(first) {
+			first = ts
+		}
+		if ts.After(last) {
+			last = ts
+		}
+	}
+
+	counts := make([]int, sum.Width)
+	span := last.Sub(first)
+	for _, ts := range sum.times {
+		bucket := 0
+		if span > 0 {
+			bucket = int(float64(ts.Sub(first)) / float64(span) * float64(sum.Width))
+		}
+		counts[min(bucket, sum.Width-1)]++
+	}
+
+	peak := 0
+	for _, count := range counts {
+		peak = max(peak, count)
+	}
+
+	levels := []rune(sparks)
+	runes := make([]rune, sum.Width)
+	for idx, count := range counts {
+		runes[idx] = levels[count*(len(levels)-1)/peak]
+		if count == 0 {
+			runes[idx] = ' '
+		}
+	}
+
+	return string(runes), first, last
+}
+
+// Write writes the summary as text.
+func (sum *Summary) Write(writer io.Writer) (err error) {
+
+	bld := &strings.Builder{}
+
+	fmt.Fprintf(bld, "events: %d\n", sum.Events)
+
+	line, first, last := sum.Timeline()
+	if line != "" {
+		fmt.Fprintf(bld, "\ntimeline: %s to %s (%s)\n  |%s|\n",
+			first.Format(time.RFC3339), last.Format(time.RFC3339), last.Sub(first).Round(time.Second), line)
+	}
+
+	bld.WriteString("\nlevels:\n")
+	writeCounts(bld, byLevel(sum.Levels), sum.Levels)
+
+	bld.WriteString("\nmsgs:\n")
+	writeCounts(bld, top(sum.Msgs, sum.Top), sum.Msgs)
+
+	bld.WriteString("\nfingerprints:\n")
+	writeCounts(bld, top(sum.Fingerprints, sum.Top), sum.Fingerprints)
+
+	if len(sum.Fields) > 0 {
+		bld.WriteString("\nfields:\n")
+	}
+	for _, key := range sum.Fields {
+		p50, count := sum.Percentile(key, 50)
+		if count == 0 {
+			fmt.Fprintf(bld, "  %s: none\n", key)
+			continue
+		}
+		p95, _ := sum.Percentile(key, 95)
+		fmt.Fprintf(bld, "  %s: count=%d p50=%s p95=%s\n", key, count, format(p50), format(p95))
+	}
+
+	_, err = io.WriteString(writer, bld.String())
+	return
+}
+
+// Fingerprint identifies like events by msg and the first line of any error, with digits masked.
+func Fingerprint(evt sabot.Event) string {
+
+	print := evt.Msg
+
+	val, ok := evt.Fields["error"]
+	if ok {
+		line, _, _ := strings.Cut(fmt.Sprintf("%v", val), "\n")
+		print += ": " + line
+	}
+
+	return digits.ReplaceAllString(print, "#")
+}
+
+//
+// unexported
+//
+
+func number(val any) (float64, bool) {
+
+	switch val := val.(type) {
+	case float64:
+		return val, true
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case json.Number:
+		num, err := val.Float64()
+		return num, err == nil
+	case string:
+		num, err := strconv.ParseFloat(val, 64)
+		return num, err == nil
+	}
+
+	return 0, false
+}
+
+func format(val float64) string {
+
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+func writeCounts(bld *strings.Builder, keys []string, counts map[string]int) {
+
+	for _, key := range keys {
+		fmt.Fprintf(bld, "  %7d  %s\n", counts[key], key)
+	}
+}
+
+// byLevel orders levels by severity, with unknown levels last by name.
+func byLevel(counts map[string]int) (keys []string) {
+
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		li, iErr := sabot.ParseLevel(keys[i])
+		lj, jErr := sabot.ParseLevel(keys[j])
+		switch {
+		case iErr == nil && jErr == nil:
+			return li < lj
+		case iErr == nil || jErr == nil:
+			return iErr == nil
+		}
+		return keys[i] < keys[j]
+	})
+
+	return
+}
+
+// top orders keys by count, most first, limited to n when positive.
+func top(counts map[string]int, n int) (keys []string) {
+
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	if n > 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+
+	return
+}
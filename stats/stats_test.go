@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestStats(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Stats Suite")
+}
+
+var _ = Describe("Summary", func() {
+
+	var (
+		sum *Summary
+	)
+
+	BeforeEach(func() {
+		sum = (&Config{Fields: []string{"latency_ms", "absent"}, Top: 2, Width: 4}).New()
+
+		start := time.Date(2026, 10, 15, 1, 0, 0, 0, time.UTC)
+		for idx := 0; idx < 20; idx++ {
+			sum.Add(sabot.Event{
+				Time:   start.Add(time.Duration(idx) * time.Second),
+				Level:  "info",
+				Msg:    "sent response",
+				Fields: sabot.Fields{"latency_ms": float64(idx + 1)},
+			})
+		}
+		for idx := 0; idx < 3; idx++ {
+			sum.Add(sabot.Event{
+				Time:   start.Add(19 * time.Second),
+				Level:  "error",
+				Msg:    "failed to fetch",
+				Fields: sabot.Fields{"error": "user 42 not found\nmain.main", "latency_ms": "100"},
+			})
+		}
+		sum.Add(sabot.Event{
+			Time:   start.Add(19 * time.Second),
+			Level:  "debug",
+			Msg:    "failed to fetch",
+			Fields: sabot.Fields{"error": "user 7 not found"},
+		})
+	})
+
+	When("counting", func() {
+		It("should count by level, msg, and fingerprint", func() {
+			Expect(sum.Events).To(Equal(24))
+			Expect(sum.Levels).To(Equal(map[string]int{"debug": 1, "info": 20, "error": 3}))
+			Expect(sum.Msgs).To(Equal(map[string]int{"sent response": 20, "failed to fetch": 4}))
+			Expect(sum.Fingerprints).To(Equal(map[string]int{
+				"sent response":                     20,
+				"failed to fetch: user # not found": 4,
+			}))
+		})
+	})
+
+	When("ranking field values", func() {
+		It("should find percentiles by nearest rank", func() {
+			p50, count := sum.Percentile("latency_ms", 50)
+			Expect(count).To(Equal(23))
+			Expect(p50).To(Equal(12.0))
+
+			p95, _ := sum.Percentile("latency_ms", 95)
+			Expect(p95).To(Equal(100.0))
+
+			_, count = sum.Percentile("absent", 50)
+			Expect(count).To(Equal(0))
+		})
+	})
+
+	When("drawing a timeline", func() {
+		It("should bucket events from first to last", func() {
+			line, first, last := sum.Timeline()
+			Expect(line).To(Equal("▄▄▄█"))
+			Expect(last.Sub(first)).To(Equal(19 * time.Second))
+		})
+	})
+
+	When("writing", func() {
+		It("should write levels by severity and the rest by count", func() {
+			buf := &bytes.Buffer{}
+			Expect(sum.Write(buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`events: 24
+
+timeline: 2026-10-15T01:00:00Z to 2026-10-15T01:00:19Z (19s)
+  |▄▄▄█|
+
+levels:
+        1  debug
+       20  info
+        3  error
+
+msgs:
+       20  sent response
+        4  failed to fetch
+
+fingerprints:
+       20  sent response
+        4  failed to fetch: user # not found
+
+fields:
+  latency_ms: count=23 p50=12 p95=100
+  absent: none
+`))
+		})
+	})
+})
+
+var _ = Describe("Fingerprint", func() {
+
+	It("should mask digits in msg and the first line of error", func() {
+		evt := sabot.Event{Msg: "retry 3", Fields: sabot.Fields{"error": errors.New("code 503")}}
+		Expect(Fingerprint(evt)).To(Equal("retry #: code #"))
+	})
+})
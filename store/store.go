@@ -0,0 +1,253 @@
+// Package store implements an embedded event store sink with a small query api.
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/clarktrimble/sabot"
+)
+
+var (
+	eventsBucket = []byte("events")
+	indexBucket  = []byte("index")
+)
+
+// Config is the configurable fields of Store.
+type Config struct {
+	Path    string        `json:"path" desc:"path to the database file"`
+	Indexed []string      `json:"indexed" desc:"field keys to index in addition to ts and level"`
+	Timeout time.Duration `json:"timeout" desc:"how long to wait for the database file lock, zero waits indefinitely"`
+}
+
+// New creates a Store from Config, opening or creating the database file.
+func (cfg *Config) New() (st *Store, err error) {
+
+	db, err := bolt.Open(cfg.Path, 0o600, &bolt.Options{Timeout: cfg.Timeout})
+	if err != nil {
+		err = errors.Wrapf(err, "failed to open store at: %s", cfg.Path)
+		return
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{eventsBucket, indexBucket} {
+			_, err := tx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to create bucket: %s", name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return
+	}
+
+	st = &Store{
+		db:      db,
+		indexed: append([]string{"level"}, cfg.Indexed...),
+	}
+	return
+}
+
+// Store is an io.Writer sink persisting events to a local bbolt database.
+//
+// Events are keyed by timestamp and indexed by level and any configured field keys,
+// so that Query can offer an in-app log viewer without a log pipeline.
+type Store struct {
+	db      *bolt.DB
+	indexed []string
+}
+
+// Query selects events from a Store.
+type Query struct {
+	// Level, when not empty, selects events of the given level.
+	Level string
+	// Fields, when not empty, selects events having each key with the given value.
+	Fields map[string]string
+	// Since, when not zero, selects events at or after.
+	Since time.Time
+	// Until, when not zero, selects events before.
+	Until time.Time
+	// Limit, when positive, is the maximum number of events returned.
+	Limit int
+}
+
+// Write stores a single json encoded event, as written by Sabot.
+func (st *Store) Write(data []byte) (n int, err error) {
+
+	fields := sabot.Fields{}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		err = errors.Wrapf(err, "failed to unmarshal event")
+		return
+	}
+
+	ts, err := eventTime(fields)
+	if err != nil {
+		return
+	}
+
+	err = st.db.Update(func(tx *bolt.Tx) error {
+
+		events := tx.Bucket(eventsBucket)
+
+		seq, err := events.NextSequence()
+		if err != nil {
+			return errors.Wrapf(err, "failed to get sequence")
+		}
+
+		key := eventKey(ts, seq)
+		err = events.Put(key, bytes.TrimSpace(data))
+		if err != nil {
+			return errors.Wrapf(err, "failed to put event")
+		}
+
+		for _, name := range st.indexed {
+			val, ok := fields[name]
+			if !ok {
+				continue
+			}
+
+			idx, err := tx.Bucket(indexBucket).CreateBucketIfNotExists(indexName(name, val))
+			if err != nil {
+				return errors.Wrapf(err, "failed to create index for: %s", name)
+			}
+
+			err = idx.Put(key, nil)
+			if err != nil {
+				return errors.Wrapf(err, "failed to put index for: %s", name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	n = len(data)
+	return
+}
+
+// Query returns events matching qry in ts order.
+func (st *Store) Query(qry Query) (events []sabot.Fields, err error) {
+
+	events = []sabot.Fields{}
+
+	err = st.db.View(func(tx *bolt.Tx) error {
+
+		bucket, ok := qry.pick(tx, st.indexed)
+		if !ok {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		for key, _ := cursor.Seek(timeKey(qry.Since)); key != nil; key, _ = cursor.Next() {
+
+			if !qry.Until.IsZero() && bytes.Compare(key, timeKey(qry.Until)) >= 0 {
+				break
+			}
+
+			fields := sabot.Fields{}
+			err := json.Unmarshal(tx.Bucket(eventsBucket).Get(key), &fields)
+			if err != nil {
+				return errors.Wrapf(err, "failed to unmarshal stored event")
+			}
+
+			if !qry.match(fields) {
+				continue
+			}
+
+			events = append(events, fields)
+			if qry.Limit > 0 && len(events) >= qry.Limit {
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return
+}
+
+// Close closes the underlying database.
+func (st *Store) Close() error {
+
+	return st.db.Close()
+}
+
+//
+// unexported
+//
+
+func (qry Query) pick(tx *bolt.Tx, indexed []string) (bucket *bolt.Bucket, ok bool) {
+
+	// use an index when one is available for the query, scanning all events otherwise
+
+	idx := tx.Bucket(indexBucket)
+	if qry.Level != "" {
+		bucket = idx.Bucket(indexName("level", qry.Level))
+		return bucket, bucket != nil
+	}
+
+	for _, name := range indexed {
+		val, ok := qry.Fields[name]
+		if ok {
+			bucket = idx.Bucket(indexName(name, val))
+			return bucket, bucket != nil
+		}
+	}
+
+	return tx.Bucket(eventsBucket), true
+}
+
+func (qry Query) match(fields sabot.Fields) bool {
+
+	if qry.Level != "" && fields["level"] != qry.Level {
+		return false
+	}
+
+	for key, val := range qry.Fields {
+		if fmt.Sprintf("%v", fields[key]) != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+func eventTime(fields sabot.Fields) (ts time.Time, err error) {
+
+	return sabot.ParseTs(fields["ts"])
+}
+
+func timeKey(ts time.Time) []byte {
+
+	key := make([]byte, 8)
+	if !ts.IsZero() {
+		binary.BigEndian.PutUint64(key, uint64(ts.UnixNano()))
+	}
+
+	return key
+}
+
+func eventKey(ts time.Time, seq uint64) []byte {
+
+	key := make([]byte, 16)
+	copy(key, timeKey(ts))
+	binary.BigEndian.PutUint64(key[8:], seq)
+
+	return key
+}
+
+func indexName(name string, val any) []byte {
+
+	return []byte(fmt.Sprintf("%s=%v", name, val))
+}
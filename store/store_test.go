@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Store Suite")
+}
+
+var _ = Describe("Store", func() {
+
+	var (
+		st  *Store
+		lgr *sabot.Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		cfg := &Config{
+			Path:    filepath.Join(GinkgoT().TempDir(), "events.db"),
+			Indexed: []string{"run_id"},
+			Timeout: time.Second,
+		}
+
+		var err error
+		st, err = cfg.New()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(st.Close)
+
+		lgr = &sabot.Sabot{Writer: st}
+		ctx = lgr.WithFields(context.Background(), "run_id", "123")
+
+		lgr.Info(ctx, "one")
+		lgr.Error(ctx, "two", errors.New("oops"))
+		lgr.Info(lgr.WithFields(context.Background(), "run_id", "456"), "three")
+	})
+
+	Describe("querying events", func() {
+		var (
+			qry    Query
+			events []sabot.Fields
+		)
+
+		JustBeforeEach(func() {
+			var err error
+			events, err = st.Query(qry)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		When("query is empty", func() {
+			BeforeEach(func() {
+				qry = Query{}
+			})
+
+			It("should return all events in order", func() {
+				Expect(msgs(events)).To(Equal([]string{"one", "two", "three"}))
+			})
+		})
+
+		When("querying by level", func() {
+			BeforeEach(func() {
+				qry = Query{Level: "error"}
+			})
+
+			It("should return matching events", func() {
+				Expect(msgs(events)).To(Equal([]string{"two"}))
+			})
+		})
+
+		When("querying by indexed field and level", func() {
+			BeforeEach(func() {
+				qry = Query{Level: "info", Fields: map[string]string{"run_id": "123"}}
+			})
+
+			It("should return matching events", func() {
+				Expect(msgs(events)).To(Equal([]string{"one"}))
+			})
+		})
+
+		When("querying by time with a limit", func() {
+			BeforeEach(func() {
+				qry = Query{Since: time.Now().Add(-time.Minute), Limit: 2}
+			})
+
+			It("should return the first matching events", func() {
+				Expect(msgs(events)).To(Equal([]string{"one", "two"}))
+			})
+		})
+
+		When("querying the future", func() {
+			BeforeEach(func() {
+				qry = Query{Since: time.Now().Add(time.Minute)}
+			})
+
+			It("should return nothing", func() {
+				Expect(events).To(BeEmpty())
+			})
+		})
+
+		When("querying an unseen level", func() {
+			BeforeEach(func() {
+				qry = Query{Level: "trace"}
+			})
+
+			It("should return nothing", func() {
+				Expect(events).To(BeEmpty())
+			})
+		})
+	})
+
+	When("the update fails", func() {
+		It("should report nothing written", func() {
+			Expect(st.Close()).To(Succeed())
+
+			n, err := st.Write([]byte(`{"ts":"2024-01-02T03:04:05Z","msg":"late"}`))
+			Expect(err).To(HaveOccurred())
+			Expect(n).To(Equal(0))
+		})
+	})
+})
+
+func msgs(events []sabot.Fields) (msgs []string) {
+
+	msgs = []string{}
+	for _, event := range events {
+		msgs = append(msgs, event["msg"].(string)) //nolint: forcetypeassert
+	}
+
+	return
+}
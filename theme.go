@@ -0,0 +1,110 @@
+package sabot
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Theme is the colors Console paints with, as ansi sgr parameters such as "1;31" by class.
+//
+// Classes are level names, along with key, column, count, and stack for the error trace.
+// Levels without a class of their own take that of the built-in just below.
+type Theme map[string]string
+
+// Themes are the built-in themes by name, dark being the default.
+var Themes = map[string]Theme{
+	"dark": {
+		"trace":  "36",
+		"debug":  "36",
+		"info":   "32",
+		"warn":   "33",
+		"error":  "31",
+		"fatal":  "1;31",
+		"audit":  "35",
+		"key":    "2",
+		"column": "1",
+		"count":  "2",
+		"stack":  "31",
+	},
+	"light": {
+		"trace":  "34",
+		"debug":  "34",
+		"info":   "32",
+		"warn":   "38;5;166",
+		"error":  "31",
+		"fatal":  "1;31",
+		"audit":  "35",
+		"key":    "90",
+		"column": "1",
+		"count":  "90",
+		"stack":  "31",
+	},
+}
+
+// NewTheme returns the named theme, dark when empty, with colors overridden by class.
+func NewTheme(name string, colors map[string]string) (theme Theme, err error) {
+
+	if name == "" {
+		name = "dark"
+	}
+
+	base, ok := Themes[name]
+	if !ok {
+		return nil, errors.Errorf("unknown theme: %s", name)
+	}
+
+	theme = make(Theme, len(base)+len(colors))
+	for class, sgr := range base {
+		theme[class] = sgr
+	}
+
+	for class, sgr := range colors {
+		if strings.Trim(sgr, "0123456789;") != "" {
+			return nil, errors.Errorf("invalid color for %s: %s", class, sgr)
+		}
+		theme[class] = sgr
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+var defaultTheme = Themes["dark"]
+
+func (theme Theme) paint(buf *bytes.Buffer, class, str string) {
+
+	sgr := theme[class]
+	if sgr == "" {
+		buf.WriteString(str)
+		return
+	}
+
+	buf.WriteString("\x1b[")
+	buf.WriteString(sgr)
+	buf.WriteByte('m')
+	buf.WriteString(str)
+	buf.WriteString("\x1b[0m")
+}
+
+// levelClass returns the class for a level, falling back to the built-in just below.
+func (theme Theme) levelClass(name string) string {
+
+	_, ok := theme[name]
+	if ok {
+		return name
+	}
+
+	lvl := levelOf(name)
+	for _, known := range []Level{Audit, Fatal, Error, Warn, Info, Debug} {
+		if lvl >= known {
+			return known.String()
+		}
+	}
+
+	return Trace.String()
+}
@@ -0,0 +1,91 @@
+package sabot
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TimeUnixMilli is the TimeLayout of ts as a number of epoch milliseconds, rather than a string,
+// for ingestion indexing numeric timestamps more cheaply.
+const TimeUnixMilli string = "unixms"
+
+// TimeLayouts are ts layouts available by name in Config, others being taken as go layouts.
+var TimeLayouts = map[string]string{
+	"rfc3339":      time.RFC3339,
+	"rfc3339milli": "2006-01-02T15:04:05.000Z07:00",
+	"rfc3339micro": "2006-01-02T15:04:05.000000Z07:00",
+	"rfc3339nano":  time.RFC3339Nano,
+}
+
+// ParseTs parses the ts of a decoded event, a string as encoded by default or a number of epoch milliseconds.
+func ParseTs(val any) (ts time.Time, err error) {
+
+	switch val := val.(type) {
+	case float64:
+		return time.UnixMilli(int64(val)).UTC(), nil
+	case json.Number:
+		var ms int64
+		ms, err = val.Int64()
+		if err != nil {
+			return ts, errors.Wrapf(err, "failed to parse event ts")
+		}
+		return time.UnixMilli(ms).UTC(), nil
+	case string:
+		ts, err = time.Parse(time.RFC3339Nano, val)
+		err = errors.Wrapf(err, "failed to parse event ts")
+		return
+	}
+
+	return ts, errors.Errorf("event has no ts")
+}
+
+//
+// unexported
+//
+
+// timeLayout resolves a layout name, leaving custom layouts as-is.
+func timeLayout(name string) string {
+
+	layout, ok := TimeLayouts[name]
+	if ok {
+		return layout
+	}
+
+	return name
+}
+
+func validateTimeLayout(name string) error {
+
+	layout := timeLayout(name)
+	if layout == "" || layout == TimeUnixMilli {
+		return nil
+	}
+
+	if (time.Time{}).Format(layout) == layout {
+		return errors.Errorf("time layout has no elements: %s", name)
+	}
+
+	// events are read back via ParseTs, by store and replay say, so ts must parse as rfc3339
+
+	_, err := ParseTs(time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.UTC).Format(layout))
+	if err != nil {
+		return errors.Errorf("time layout is not rfc3339: %s", name)
+	}
+
+	return nil
+}
+
+// ts is the time of an event as encoded, formatted when a layout is set.
+func (evt *Event) ts() any {
+
+	switch evt.layout {
+	case "":
+		return evt.Time
+	case TimeUnixMilli:
+		return evt.Time.UnixMilli()
+	}
+
+	return evt.Time.Format(evt.layout)
+}
@@ -0,0 +1,118 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TimeLayout", func() {
+
+	var (
+		buf *bytes.Buffer
+		cfg *Config
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		cfg = &Config{TimeLayout: "rfc3339milli"}
+		ctx = context.Background()
+	})
+
+	JustBeforeEach(func() {
+		lgr = cfg.New(buf)
+		lgr.Info(ctx, "stamped")
+	})
+
+	When("configured by name", func() {
+		It("should format ts with millisecond precision", func() {
+			Expect(buf.String()).To(MatchRegexp(`"ts":"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d\d:\d\d)"`))
+		})
+	})
+
+	When("configured with a go layout", func() {
+		BeforeEach(func() {
+			cfg.TimeLayout = "2006-01-02T15:04:05.00Z"
+		})
+
+		It("should format ts with it", func() {
+			Expect(buf.String()).To(MatchRegexp(`"ts":"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d\dZ"`))
+		})
+	})
+
+	When("encoding as logfmt", func() {
+		BeforeEach(func() {
+			cfg.Format = "logfmt"
+		})
+
+		It("should format ts the same", func() {
+			Expect(buf.String()).To(MatchRegexp(`^ts=\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d\d:\d\d) `))
+		})
+	})
+
+	When("encoding as ecs", func() {
+		BeforeEach(func() {
+			cfg.Format = "ecs"
+		})
+
+		It("should format @timestamp the same", func() {
+			Expect(buf.String()).To(MatchRegexp(`"@timestamp":"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}(Z|[+-]\d\d:\d\d)"`))
+		})
+	})
+
+	When("configured as epoch milliseconds", func() {
+		BeforeEach(func() {
+			cfg.TimeLayout = TimeUnixMilli
+		})
+
+		It("should log ts as a number, decoding back", func() {
+			Expect(buf.String()).To(MatchRegexp(`"ts":\d{13}\b`))
+
+			evt, err := DecodeEvent(bytes.TrimSpace(buf.Bytes()))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(evt.Time).To(BeTemporally("~", time.Now(), time.Second))
+		})
+	})
+
+	When("not configured", func() {
+		BeforeEach(func() {
+			cfg.TimeLayout = ""
+		})
+
+		It("should format ts as json.Marshal would", func() {
+			Expect(buf.String()).To(MatchRegexp(`"ts":"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?(Z|[+-]\d\d:\d\d)"`))
+		})
+	})
+
+	It("should validate the layout", func() {
+		Expect((&Config{TimeLayout: "rfc3339milli"}).Validate()).To(Succeed())
+		Expect((&Config{TimeLayout: "2006-01-02T15:04:05.00Z"}).Validate()).To(Succeed())
+		Expect((&Config{TimeLayout: "iso"}).Validate()).To(MatchError("time layout has no elements: iso"))
+		Expect((&Config{TimeLayout: "2006-01-02 15:04:05"}).Validate()).To(
+			MatchError("time layout is not rfc3339: 2006-01-02 15:04:05"),
+		)
+	})
+
+	DescribeTable("parsing ts",
+		func(val any, expected time.Time, msg string) {
+			ts, err := ParseTs(val)
+			if msg != "" {
+				Expect(err).To(MatchError(HavePrefix(msg)))
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ts).To(BeTemporally("==", expected))
+		},
+		Entry("string", "2024-01-02T03:04:05.123Z", time.Date(2024, 1, 2, 3, 4, 5, 123e6, time.UTC), ""),
+		Entry("epoch ms", float64(1704164645123), time.Date(2024, 1, 2, 3, 4, 5, 123e6, time.UTC), ""),
+		Entry("epoch ms number", json.Number("1704164645123"), time.Date(2024, 1, 2, 3, 4, 5, 123e6, time.UTC), ""),
+		Entry("bad string", "yesterday", time.Time{}, "failed to parse event ts"),
+		Entry("missing", nil, time.Time{}, "event has no ts"),
+	)
+})
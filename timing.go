@@ -0,0 +1,50 @@
+package sabot
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	elapsedKey    string = "elapsed_ms"
+	budgetMsKey   string = "budget_ms"
+	overBudgetKey string = "over_budget_ms"
+)
+
+// Start begins timing an operation, returning a func logging its completion at info as msg,
+// with elapsed_ms along with kv given to either.
+func (sabot *Sabot) Start(ctx context.Context, msg string, kv ...any) (done func(kv ...any)) {
+
+	return sabot.StartBudget(ctx, msg, 0, kv...)
+}
+
+// StartBudget is Start with an expected latency budget, zero for none.
+//
+// A completion over budget is escalated to warn, or to error at twice the budget, with budget_ms and over_budget_ms,
+// so that logs serve as cheap SLO signals.
+func (sabot *Sabot) StartBudget(ctx context.Context, msg string, budget time.Duration, kv ...any) (done func(kv ...any)) {
+
+	start := time.Now()
+
+	return func(doneKv ...any) {
+
+		elapsed := time.Since(start)
+
+		lvl := Info
+		fields := []any{elapsedKey, elapsed.Milliseconds()}
+		if budget > 0 && elapsed > budget {
+			lvl = Warn
+			if elapsed >= 2*budget {
+				lvl = Error
+			}
+			fields = append(fields, budgetMsKey, budget.Milliseconds(), overBudgetKey, (elapsed - budget).Milliseconds())
+		}
+
+		if !sabot.enabled(ctx, lvl) {
+			return
+		}
+
+		all := append(append(append([]any{}, kv...), doneKv...), fields...)
+		sabot.log(ctx, lvl.String(), msg, nil, all)
+	}
+}
@@ -0,0 +1,81 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Start", func() {
+
+	var (
+		buf *bytes.Buffer
+		lgr *Sabot
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		lgr = &Sabot{Writer: buf}
+		ctx = context.Background()
+	})
+
+	When("timing without a budget", func() {
+		It("should log completion at info with elapsed", func() {
+			done := lgr.Start(ctx, "fetched", "table", "users")
+			done("rows", 3)
+
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("level", "info"))
+			Expect(logged).To(HaveKeyWithValue("table", "users"))
+			Expect(logged).To(HaveKeyWithValue("rows", 3.0))
+			Expect(logged).To(HaveKey("elapsed_ms"))
+			Expect(logged).ToNot(HaveKey("over_budget_ms"))
+		})
+	})
+
+	When("completing within budget", func() {
+		It("should log at info", func() {
+			lgr.StartBudget(ctx, "fetched", time.Hour)()
+
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("level", "info"))
+			Expect(logged).ToNot(HaveKey("budget_ms"))
+		})
+	})
+
+	When("completing over budget", func() {
+		It("should escalate to warn", func() {
+			done := lgr.StartBudget(ctx, "fetched", 100*time.Millisecond)
+			time.Sleep(110 * time.Millisecond)
+			done()
+
+			logged := delog(buf)
+			Expect(logged).To(HaveKeyWithValue("level", "warn"))
+			Expect(logged).To(HaveKeyWithValue("budget_ms", 100.0))
+			Expect(logged["over_budget_ms"]).To(BeNumerically(">=", 10))
+		})
+	})
+
+	When("completing at twice the budget", func() {
+		It("should escalate to error", func() {
+			done := lgr.StartBudget(ctx, "fetched", 5*time.Millisecond)
+			time.Sleep(10 * time.Millisecond)
+			done()
+
+			Expect(delog(buf)).To(HaveKeyWithValue("level", "error"))
+		})
+	})
+
+	When("info is not enabled", func() {
+		It("should log only when escalated", func() {
+			lgr.MinLevel = Warn
+			lgr.StartBudget(ctx, "fetched", time.Hour)()
+
+			Expect(buf.Len()).To(BeZero())
+		})
+	})
+})
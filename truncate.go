@@ -0,0 +1,242 @@
+package sabot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	origLenSuffix string = "_orig_len"
+	hashMarker    string = "…#"
+	hashMarkerLen int    = len(hashMarker) + 8
+)
+
+//
+// unexported
+//
+
+// truncation is how values are truncated.
+type truncation struct {
+	max    int
+	hashed bool
+}
+
+func (sabot *Sabot) truncation() truncation {
+
+	return truncation{
+		max:    sabot.MaxLen,
+		hashed: sabot.HashTruncated,
+	}
+}
+
+func (trc truncation) truncate(str string) (string, bool) {
+
+	if trc.hashed {
+		return truncateHashed(str, trc.max)
+	}
+
+	return truncate(str, trc.max)
+}
+
+// truncate truncates string values, adding a companion field with the original length of each.
+func (fields Fields) truncate(trc truncation) {
+
+	var origLens Fields
+	for key, val := range fields {
+
+		// unwrap values exempt from truncation
+
+		wh, ok := val.(whole)
+		if ok {
+			fields[key] = wh.val
+			continue
+		}
+
+		// truncate within a copy of groups, as they may be shared via ctx
+
+		grp, ok := val.(group)
+		if ok {
+			cp := make(Fields, len(grp))
+			for gk, gv := range grp {
+				cp[gk] = gv
+			}
+			cp.truncate(trc)
+			fields[key] = group(cp)
+			continue
+		}
+
+		// truncate within hints, as with bound fields
+
+		hnt, ok := val.(hinted)
+		if ok {
+			str, isStr := hnt.val.(string)
+			if isStr {
+				hnt.val, _ = trc.truncate(str)
+				fields[key] = hnt
+			}
+			continue
+		}
+
+		// marshalled values become strings only when truncated, as a prefix isn't valid json
+
+		str, ok := val.(string)
+		raw, isRaw := val.(json.RawMessage)
+		if nst, isNested := val.(nested); isNested {
+			raw, isRaw = json.RawMessage(nst), true
+		}
+		if isRaw {
+			str, ok = string(raw), true
+		}
+		if !ok {
+			continue
+		}
+
+		truncated, ok := trc.truncate(str)
+		if ok {
+			fields[key] = truncated
+
+			if origLens == nil {
+				origLens = Fields{}
+			}
+			origLens[key+origLenSuffix] = len(str)
+		}
+	}
+
+	for key, val := range origLens {
+		fields[key] = val
+	}
+}
+
+func truncate(str string, max int) (string, bool) {
+
+	// account for notice length in truncation result
+
+	keep := max - len(truncationNotice)
+	if keep < 1 || len(str) <= keep {
+		return str, false
+	}
+
+	switch {
+	case looksJson(str):
+		return truncateJson(str, keep), true
+	case looksBase64(str):
+		// keep whole blocks so that the prefix still decodes
+		if keep > 4 {
+			keep -= keep % 4
+		}
+	}
+
+	return strings.Join([]string{str[:keep], truncationNotice}, ""), true
+}
+
+// truncateHashed replaces the end of an over-long value with a short hash of the whole,
+// so that truncated values can still be compared.
+func truncateHashed(str string, max int) (string, bool) {
+
+	keep := max - hashMarkerLen
+	if keep < 1 || len(str) <= max {
+		return str, false
+	}
+
+	sum := sha256.Sum256([]byte(str))
+
+	return strings.Join([]string{str[:keep], hashMarker, hex.EncodeToString(sum[:4])}, ""), true
+}
+
+func looksJson(str string) bool {
+
+	trimmed := strings.TrimLeft(str, " \t\r\n")
+	return strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")
+}
+
+func looksBase64(str string) bool {
+
+	var upper, lower, digit bool
+	for _, char := range str {
+		switch {
+		case char >= 'A' && char <= 'Z':
+			upper = true
+		case char >= 'a' && char <= 'z':
+			lower = true
+		case char >= '0' && char <= '9':
+			digit = true
+		case char == '+' || char == '/' || char == '-' || char == '_' || char == '=':
+		default:
+			return false
+		}
+	}
+
+	// mixed classes distinguish encoded bytes from words
+	return upper && lower && digit
+}
+
+// truncateJson truncates json to keep bytes or fewer, closing any open string,
+// with the notice inside, and any open brackets.
+func truncateJson(str string, keep int) string {
+
+	// find the longest prefix that fits along with its closers
+
+	cut := 0
+	scanJson(str[:keep], func(idx int, open []byte, inStr, escaped bool) {
+		closers := len(open)
+		if inStr {
+			closers++
+		}
+		if !escaped && idx+closers <= keep {
+			cut = idx
+		}
+	})
+
+	var closers []byte
+	var inString bool
+	scanJson(str[:cut], func(idx int, open []byte, inStr, escaped bool) {
+		if idx == cut {
+			closers = append(closers, open...)
+			inString = inStr
+		}
+	})
+
+	var bldr strings.Builder
+	bldr.WriteString(str[:cut])
+	bldr.WriteString(truncationNotice)
+	if inString {
+		bldr.WriteByte('"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		bldr.WriteByte(closers[i])
+	}
+
+	return bldr.String()
+}
+
+// scanJson calls visit with the state after each prefix of str, from empty through whole.
+func scanJson(str string, visit func(idx int, open []byte, inStr, escaped bool)) {
+
+	var open []byte
+	var inStr, escaped bool
+
+	visit(0, open, inStr, escaped)
+	for idx := 0; idx < len(str); idx++ {
+
+		char := str[idx]
+		switch {
+		case escaped:
+			escaped = false
+		case inStr && char == '\\':
+			escaped = true
+		case char == '"':
+			inStr = !inStr
+		case inStr:
+		case char == '{':
+			open = append(open, '}')
+		case char == '[':
+			open = append(open, ']')
+		case (char == '}' || char == ']') && len(open) > 0:
+			open = open[:len(open)-1]
+		}
+
+		visit(idx+1, open, inStr, escaped)
+	}
+}
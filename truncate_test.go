@@ -0,0 +1,97 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Truncate", func() {
+
+	var (
+		fields Fields
+	)
+
+	JustBeforeEach(func() {
+		fields.truncate(truncation{max: 40})
+	})
+
+	When("truncating json mid-string", func() {
+		BeforeEach(func() {
+			fields = Fields{"body": `{"user":{"name":"` + strings.Repeat("y", 40) + `"}}`}
+		})
+
+		It("should close the string and braces, keeping valid json", func() {
+			body := fields["body"].(string) //nolint: forcetypeassert
+			Expect(body).To(Equal(`{"user":{"name":"yyyyyyy--truncated--"}}`))
+			Expect(body).To(HaveLen(40))
+			Expect(json.Valid([]byte(body))).To(BeTrue())
+			Expect(fields["body_orig_len"]).To(Equal(60))
+		})
+	})
+
+	When("truncating json just after an escape", func() {
+		BeforeEach(func() {
+			fields = Fields{"body": `["abcdefghijklmnopqrstu\"` + strings.Repeat("z", 40) + `"]`}
+		})
+
+		It("should not split the escape", func() {
+			body := fields["body"].(string) //nolint: forcetypeassert
+			Expect(json.Valid([]byte(body))).To(BeTrue())
+		})
+	})
+
+	When("truncating base64", func() {
+		BeforeEach(func() {
+			fields = Fields{"blob": base64.StdEncoding.EncodeToString([]byte(strings.Repeat("Go1 rocks", 9)))}
+		})
+
+		It("should keep whole blocks", func() {
+			blob := fields["blob"].(string) //nolint: forcetypeassert
+			prefix := strings.TrimSuffix(blob, truncationNotice)
+			Expect(prefix).To(HaveLen(24))
+
+			_, err := base64.StdEncoding.DecodeString(prefix)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	When("values are short enough", func() {
+		BeforeEach(func() {
+			fields = Fields{"short": "abc", "count": 3}
+		})
+
+		It("should leave them alone, without companions", func() {
+			Expect(fields).To(Equal(Fields{"short": "abc", "count": 3}))
+		})
+	})
+
+	Describe("hash-and-truncate mode", func() {
+		var (
+			buf *bytes.Buffer
+			lgr *Sabot
+		)
+
+		BeforeEach(func() {
+			buf = &bytes.Buffer{}
+			lgr = (&Config{MaxLen: 30, HashTruncated: true}).New(buf)
+		})
+
+		It("should end over-long values with a hash of the whole", func() {
+			payload := strings.Repeat("p", 50)
+			lgr.Info(context.Background(), "hashing", "one", payload, "two", payload, "three", payload+"!")
+
+			fields := delog(buf)
+			Expect(fields["one"]).To(MatchRegexp(`^p{18}…#[0-9a-f]{8}$`))
+			Expect(fields["one"]).To(HaveLen(30))
+			Expect(fields["one"]).To(Equal(fields["two"]))
+			Expect(fields["one"]).ToNot(Equal(fields["three"]))
+			Expect(fields["one_orig_len"]).To(Equal(float64(50)))
+		})
+	})
+})
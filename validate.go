@@ -0,0 +1,140 @@
+package sabot
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Validate checks Config for nonsensical settings.
+//
+// MaxLen of zero is unlimited, otherwise it must leave room for the truncation notice.
+func (cfg *Config) Validate() (err error) {
+
+	err = validateMaxLen(cfg.MaxLen)
+	if err != nil {
+		return
+	}
+
+	err = validateLevel(cfg.MinLevel)
+	if err != nil {
+		return
+	}
+
+	if cfg.Format != "" && cfg.Format != "json" && cfg.Format != "auto" {
+		_, ok := Encoders[cfg.Format]
+		if !ok {
+			return errors.Errorf("unknown format: %s", cfg.Format)
+		}
+	}
+
+	_, err = ParseColumns(cfg.Columns)
+	if err != nil {
+		return
+	}
+
+	_, err = NewTheme(cfg.Theme, cfg.ThemeColors)
+	if err != nil {
+		return
+	}
+
+	if cfg.Color != ColorAuto && cfg.Color != ColorAlways && cfg.Color != ColorNever {
+		return errors.Errorf("unknown color mode: %s", cfg.Color)
+	}
+
+	err = validateTimeLayout(cfg.TimeLayout)
+	if err != nil {
+		return
+	}
+
+	switch cfg.Bytes {
+	case BytesBase64, BytesHex, BytesText, BytesLength:
+	default:
+		return errors.Errorf("unknown bytes mode: %s", cfg.Bytes)
+	}
+
+	if cfg.TenantEvents < 0 || cfg.TenantBytes < 0 || cfg.TenantWindow < 0 {
+		return errors.Errorf("tenant quota is negative")
+	}
+
+	if len(cfg.Redact.Pseudonymize) > 0 && cfg.Redact.PseudoKey == "" {
+		return errors.Errorf("pseudonymize requires a pseudo key")
+	}
+
+	if cfg.CallerSkip < 0 {
+		return errors.Errorf("caller skip is negative: %d", cfg.CallerSkip)
+	}
+
+	switch {
+	case cfg.Keys != KeysAsIs && cfg.Keys != KeysExpand && cfg.Keys != KeysFlatten:
+		return errors.Errorf("unknown keys mode: %s", cfg.Keys)
+	case cfg.Keys != KeysAsIs && cfg.Format != "" && cfg.Format != "json" && cfg.Format != "ecs" && cfg.Format != "auto":
+		return errors.Errorf("keys mode not supported by format: %s", cfg.Format)
+	case cfg.Deterministic && cfg.Format != "cbor":
+		return errors.Errorf("deterministic not supported by format: %s", cfg.Format)
+	}
+
+	return
+}
+
+// Validate checks Sabot for nonsensical settings, such as a missing Writer.
+func (sabot *Sabot) Validate() (err error) {
+
+	err = validateMaxLen(sabot.MaxLen)
+	if err != nil {
+		return
+	}
+
+	if sabot.Writer == nil {
+		return errors.Errorf("writer is nil")
+	}
+
+	err = validateLevel(sabot.MinLevel)
+	if err != nil {
+		return
+	}
+
+	for lvl := range sabot.Disabled {
+		err = validateLevel(lvl)
+		if err != nil {
+			return
+		}
+	}
+
+	for lvl, writer := range sabot.Routes {
+		err = validateLevel(lvl)
+		if err != nil {
+			return
+		}
+		if writer == nil {
+			return errors.Errorf("route writer is nil for level: %s", lvl)
+		}
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+func validateMaxLen(max int) error {
+
+	if max < 0 {
+		return errors.Errorf("max len is negative: %d", max)
+	}
+
+	if max > 0 && max <= len(truncationNotice) {
+		return errors.Errorf("max len %d is too short to hold truncation notice", max)
+	}
+
+	return nil
+}
+
+func validateLevel(lvl Level) error {
+
+	_, err := ParseLevel(lvl.String())
+	if err != nil {
+		return errors.Errorf("unregistered level value: %d", lvl)
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package sabot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Validate", func() {
+
+	Describe("validating config", func() {
+		var (
+			cfg *Config
+		)
+
+		BeforeEach(func() {
+			cfg = &Config{MaxLen: 99, Format: "logfmt"}
+		})
+
+		When("all is well", func() {
+			It("should not error", func() {
+				Expect(cfg.Validate()).To(Succeed())
+			})
+		})
+
+		When("max len is zero", func() {
+			It("should not error, being unlimited", func() {
+				cfg.MaxLen = 0
+				Expect(cfg.Validate()).To(Succeed())
+			})
+		})
+
+		When("max len is negative", func() {
+			It("should error", func() {
+				cfg.MaxLen = -1
+				Expect(cfg.Validate()).To(MatchError("max len is negative: -1"))
+			})
+		})
+
+		When("max len is shorter than the notice", func() {
+			It("should error", func() {
+				cfg.MaxLen = 9
+				Expect(cfg.Validate()).To(MatchError("max len 9 is too short to hold truncation notice"))
+			})
+		})
+
+		When("format is a typo", func() {
+			It("should error", func() {
+				cfg.Format = "jsno"
+				Expect(cfg.Validate()).To(MatchError("unknown format: jsno"))
+			})
+		})
+	})
+
+	Describe("validating a logger", func() {
+		var (
+			lgr *Sabot
+		)
+
+		BeforeEach(func() {
+			lgr = &Sabot{Writer: &bytes.Buffer{}}
+		})
+
+		When("all is well", func() {
+			It("should not error", func() {
+				Expect(lgr.Validate()).To(Succeed())
+			})
+		})
+
+		When("writer is missing", func() {
+			It("should error", func() {
+				lgr.Writer = nil
+				Expect(lgr.Validate()).To(MatchError("writer is nil"))
+			})
+		})
+
+		When("a disabled level is not registered", func() {
+			It("should error", func() {
+				lgr.Disabled = map[Level]bool{Info + 3: true}
+				Expect(lgr.Validate()).To(MatchError("unregistered level value: 3"))
+			})
+		})
+
+		When("a route writer is missing", func() {
+			It("should error", func() {
+				lgr.Routes = map[Level]io.Writer{Error: nil}
+				Expect(lgr.Validate()).To(MatchError("route writer is nil for level: error"))
+			})
+		})
+	})
+
+	Describe("logging with max len of zero", func() {
+		It("should not truncate", func() {
+			buf := &bytes.Buffer{}
+			lgr := &Sabot{Writer: buf}
+
+			long := strings.Repeat("x", 9999)
+			lgr.Info(context.Background(), "unlimited", "long", long)
+
+			Expect(delog(buf)["long"]).To(Equal(long))
+		})
+	})
+})
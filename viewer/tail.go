@@ -0,0 +1,79 @@
+package viewer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"time"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const pollInterval = 250 * time.Millisecond
+
+// Tail sends events read from src until ctx is done, polling for more at the end when follow.
+//
+// Lines not decodable as events are sent as a msg alone, so that nothing is hidden.
+func Tail(ctx context.Context, src io.Reader, follow bool) <-chan sabot.Event {
+
+	events := make(chan sabot.Event)
+
+	go func() {
+		defer close(events)
+
+		reader := bufio.NewReader(src)
+		var partial []byte
+		for {
+			line, err := reader.ReadBytes('\n')
+			partial = append(partial, line...)
+
+			// hold a partial line until the rest is written
+
+			if err == nil {
+				if !send(ctx, events, partial) {
+					return
+				}
+				partial = nil
+				continue
+			}
+
+			if err != io.EOF || !follow {
+				send(ctx, events, partial)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollInterval):
+			}
+		}
+	}()
+
+	return events
+}
+
+//
+// unexported
+//
+
+func send(ctx context.Context, events chan<- sabot.Event, line []byte) bool {
+
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return true
+	}
+
+	evt, err := sabot.DecodeEvent(line)
+	if err != nil {
+		evt = &sabot.Event{Msg: string(line), Fields: sabot.Fields{}}
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case events <- *evt:
+		return true
+	}
+}
@@ -0,0 +1,453 @@
+// Package viewer implements an interactive terminal view of sabot events,
+// with live tail, level filter, search, and a detail pane.
+package viewer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/clarktrimble/sabot"
+)
+
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	reverse     = "\x1b[7m"
+	reset       = "\x1b[0m"
+	enterAlt    = "\x1b[?1049h\x1b[?25l"
+	leaveAlt    = "\x1b[?25h\x1b[?1049l"
+	help        = "q quit  / search  l level  enter detail  f follow"
+)
+
+// filterLevels are cycled through by the level filter, trace showing all.
+var filterLevels = []sabot.Level{sabot.Trace, sabot.Debug, sabot.Info, sabot.Warn, sabot.Error}
+
+// Key is a keypress, as parsed from terminal input.
+type Key string
+
+// Keys other than printable runes.
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdown"
+	KeyEnter     Key = "enter"
+	KeyEscape    Key = "esc"
+	KeyBackspace Key = "backspace"
+)
+
+// Viewer is the state of an interactive view of events.
+type Viewer struct {
+	events  []sabot.Event
+	lines   []string
+	shown   []int
+	filter  int
+	search  string
+	input   string
+	typing  bool
+	cursor  int
+	top     int
+	detail  bool
+	follow  bool
+	width   int
+	height  int
+	console sabot.Console
+}
+
+// New creates a Viewer for a screen of width by height, following new events when follow.
+func New(width, height int, follow bool) *Viewer {
+
+	return &Viewer{
+		width:   width,
+		height:  height,
+		follow:  follow,
+		console: sabot.Console{},
+	}
+}
+
+// Add appends an event, moving to it when following.
+func (vwr *Viewer) Add(evt sabot.Event) {
+
+	line := evt.Msg
+	data, err := vwr.console.Encode(&evt)
+	if err == nil {
+		line, _, _ = strings.Cut(string(data), "\n")
+	}
+
+	vwr.events = append(vwr.events, evt)
+	vwr.lines = append(vwr.lines, line)
+
+	idx := len(vwr.events) - 1
+	if vwr.matches(idx) {
+		vwr.shown = append(vwr.shown, idx)
+		if vwr.follow {
+			vwr.cursor = len(vwr.shown) - 1
+		}
+	}
+}
+
+// Press handles a keypress, returning true when it's time to quit.
+func (vwr *Viewer) Press(key Key) (quit bool) {
+
+	if vwr.typing {
+		vwr.typeKey(key)
+		return
+	}
+
+	switch key {
+	case "q":
+		return true
+	case "j", KeyDown:
+		vwr.move(1)
+	case "k", KeyUp:
+		vwr.follow = false
+		vwr.move(-1)
+	case KeyPageDown, " ":
+		vwr.move(vwr.listHeight())
+	case KeyPageUp:
+		vwr.follow = false
+		vwr.move(-vwr.listHeight())
+	case "g":
+		vwr.follow = false
+		vwr.move(-len(vwr.shown))
+	case "G":
+		vwr.move(len(vwr.shown))
+	case KeyEnter:
+		vwr.detail = !vwr.detail
+	case "f":
+		vwr.follow = !vwr.follow
+		if vwr.follow {
+			vwr.move(len(vwr.shown))
+		}
+	case "l":
+		vwr.filter = (vwr.filter + 1) % len(filterLevels)
+		vwr.refilter()
+	case "/":
+		vwr.typing = true
+		vwr.input = vwr.search
+	case KeyEscape:
+		vwr.detail = false
+	}
+
+	return
+}
+
+// Render draws the screen.
+func (vwr *Viewer) Render(screen io.Writer) {
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(clearScreen)
+
+	listHeight := vwr.listHeight()
+	vwr.scroll(listHeight)
+
+	for row := 0; row < listHeight; row++ {
+		pos := vwr.top + row
+		if pos >= len(vwr.shown) {
+			buf.WriteString("\r\n")
+			continue
+		}
+
+		line := cut(vwr.lines[vwr.shown[pos]], vwr.width)
+		if pos == vwr.cursor {
+			line = reverse + line + reset
+		}
+		buf.WriteString(line + "\r\n")
+	}
+
+	if vwr.detail {
+		detail := vwr.detailLines()
+		for row := 0; row < vwr.detailHeight(); row++ {
+			if row < len(detail) {
+				buf.WriteString(cut(detail[row], vwr.width))
+			}
+			buf.WriteString("\r\n")
+		}
+	}
+
+	buf.WriteString(reverse + cut(vwr.status(), vwr.width) + reset)
+
+	_, _ = screen.Write(buf.Bytes())
+}
+
+// Run views events as they arrive, reading keys until quit or ctx is done.
+//
+// The screen is switched to the alternate buffer while running, and restored on return.
+func (vwr *Viewer) Run(ctx context.Context, events <-chan sabot.Event, keys io.Reader, screen io.Writer) {
+
+	_, _ = io.WriteString(screen, enterAlt)
+	defer func() { _, _ = io.WriteString(screen, leaveAlt) }()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	pressed := make(chan Key)
+	go readKeys(keys, pressed, done)
+
+	vwr.Render(screen)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			vwr.Add(evt)
+		case key, ok := <-pressed:
+			if !ok || vwr.Press(key) {
+				return
+			}
+		}
+
+		vwr.Render(screen)
+	}
+}
+
+// ParseKeys parses terminal input in raw mode into keys.
+func ParseKeys(input []byte) (keys []Key) {
+
+	for len(input) > 0 {
+		switch {
+		case bytes.HasPrefix(input, []byte("\x1b[A")):
+			keys, input = append(keys, KeyUp), input[3:]
+		case bytes.HasPrefix(input, []byte("\x1b[B")):
+			keys, input = append(keys, KeyDown), input[3:]
+		case bytes.HasPrefix(input, []byte("\x1b[5~")):
+			keys, input = append(keys, KeyPageUp), input[4:]
+		case bytes.HasPrefix(input, []byte("\x1b[6~")):
+			keys, input = append(keys, KeyPageDown), input[4:]
+		case bytes.HasPrefix(input, []byte("\x1b[")):
+			// skip other sequences, such as left and right, through their final byte
+			end := bytes.IndexFunc(input[2:], func(rn rune) bool { return rn >= 0x40 && rn <= 0x7e })
+			if end < 0 {
+				return
+			}
+			input = input[end+3:]
+		case input[0] == 0x1b:
+			keys, input = append(keys, KeyEscape), input[1:]
+		case input[0] == '\r' || input[0] == '\n':
+			keys, input = append(keys, KeyEnter), input[1:]
+		case input[0] == 0x7f || input[0] == 0x08:
+			keys, input = append(keys, KeyBackspace), input[1:]
+		case input[0] == 0x03:
+			// ctrl-c, as raw mode doesn't signal
+			keys, input = append(keys, "q"), input[1:]
+		default:
+			rn := []rune(string(input))[0]
+			keys, input = append(keys, Key(string(rn))), input[len(string(rn)):]
+		}
+	}
+
+	return
+}
+
+//
+// unexported
+//
+
+func readKeys(src io.Reader, pressed chan<- Key, done <-chan struct{}) {
+
+	defer close(pressed)
+
+	buf := make([]byte, 64)
+	for {
+		n, err := src.Read(buf)
+		for _, key := range ParseKeys(buf[:n]) {
+			select {
+			case <-done:
+				return
+			case pressed <- key:
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (vwr *Viewer) typeKey(key Key) {
+
+	switch key {
+	case KeyEnter:
+		vwr.typing = false
+		vwr.search = vwr.input
+		vwr.refilter()
+	case KeyEscape:
+		vwr.typing = false
+	case KeyBackspace:
+		runes := []rune(vwr.input)
+		if len(runes) > 0 {
+			vwr.input = string(runes[:len(runes)-1])
+		}
+	case KeyUp, KeyDown, KeyPageUp, KeyPageDown:
+	default:
+		vwr.input += string(key)
+	}
+}
+
+func (vwr *Viewer) matches(idx int) bool {
+
+	lvl, err := sabot.ParseLevel(vwr.events[idx].Level)
+	if err == nil && lvl < filterLevels[vwr.filter] {
+		return false
+	}
+
+	if vwr.search == "" {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(vwr.lines[idx]), strings.ToLower(vwr.search))
+}
+
+func (vwr *Viewer) refilter() {
+
+	// keep the cursor on the same event when it's still shown
+
+	selected := -1
+	if vwr.cursor < len(vwr.shown) {
+		selected = vwr.shown[vwr.cursor]
+	}
+
+	vwr.shown = vwr.shown[:0]
+	vwr.cursor = 0
+	for idx := range vwr.events {
+		if vwr.matches(idx) {
+			if idx <= selected {
+				vwr.cursor = len(vwr.shown)
+			}
+			vwr.shown = append(vwr.shown, idx)
+		}
+	}
+
+	if vwr.follow {
+		vwr.cursor = max(len(vwr.shown)-1, 0)
+	}
+}
+
+func (vwr *Viewer) move(delta int) {
+
+	vwr.cursor = min(max(vwr.cursor+delta, 0), max(len(vwr.shown)-1, 0))
+}
+
+func (vwr *Viewer) scroll(listHeight int) {
+
+	switch {
+	case vwr.cursor < vwr.top:
+		vwr.top = vwr.cursor
+	case vwr.cursor >= vwr.top+listHeight:
+		vwr.top = vwr.cursor - listHeight + 1
+	}
+}
+
+func (vwr *Viewer) listHeight() int {
+
+	return max(vwr.height-1-vwr.detailHeight(), 1)
+}
+
+func (vwr *Viewer) detailHeight() int {
+
+	if !vwr.detail {
+		return 0
+	}
+
+	return (vwr.height - 1) / 2
+}
+
+func (vwr *Viewer) status() string {
+
+	if vwr.typing {
+		return "/" + vwr.input
+	}
+
+	parts := []string{fmt.Sprintf("%d/%d", min(vwr.cursor+1, len(vwr.shown)), len(vwr.shown))}
+	if vwr.filter > 0 {
+		parts = append(parts, filterLevels[vwr.filter].String()+"+")
+	}
+	if vwr.search != "" {
+		parts = append(parts, "/"+vwr.search)
+	}
+	if vwr.follow {
+		parts = append(parts, "following")
+	}
+
+	return strings.Join(append(parts, help), "  ")
+}
+
+// detailLines expands the selected event, indenting json values and putting stacks on lines of their own.
+func (vwr *Viewer) detailLines() (lines []string) {
+
+	if vwr.cursor >= len(vwr.shown) {
+		return
+	}
+	evt := vwr.events[vwr.shown[vwr.cursor]]
+
+	lines = append(lines,
+		"ts:    "+evt.Time.Format("2006-01-02T15:04:05.000Z07:00"),
+		"level: "+evt.Level,
+		"msg:   "+evt.Msg,
+	)
+
+	keys := make([]string, 0, len(evt.Fields))
+	for key := range evt.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := expand(evt.Fields[key])
+		if !strings.Contains(val, "\n") {
+			lines = append(lines, key+": "+val)
+			continue
+		}
+
+		lines = append(lines, key+":")
+		for _, line := range strings.Split(val, "\n") {
+			lines = append(lines, "  "+strings.ReplaceAll(line, "\t", "  "))
+		}
+	}
+
+	return
+}
+
+func expand(val any) string {
+
+	str, ok := val.(string)
+	if !ok {
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprintf("%v", val)
+		}
+		str = string(data)
+	}
+
+	// objects are logged as json strings, so indent any that are
+
+	trimmed := strings.TrimSpace(str)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") {
+		return str
+	}
+
+	buf := &bytes.Buffer{}
+	err := json.Indent(buf, []byte(trimmed), "", "  ")
+	if err != nil {
+		return str
+	}
+
+	return buf.String()
+}
+
+func cut(line string, width int) string {
+
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return line
+	}
+
+	return string(runes[:width])
+}
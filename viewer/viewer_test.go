@@ -0,0 +1,108 @@
+package viewer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/clarktrimble/sabot"
+)
+
+func TestViewer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Viewer Suite")
+}
+
+var _ = Describe("Viewer", func() {
+
+	var (
+		vwr *Viewer
+	)
+
+	BeforeEach(func() {
+		vwr = New(80, 11, false)
+
+		ts := time.Date(2026, 10, 15, 1, 2, 3, 0, time.UTC)
+		vwr.Add(sabot.Event{Time: ts, Level: "debug", Msg: "looking", Fields: sabot.Fields{}})
+		vwr.Add(sabot.Event{Time: ts, Level: "info", Msg: "fetched", Fields: sabot.Fields{
+			"req": `{"method":"GET","path":"/users"}`,
+		}})
+		vwr.Add(sabot.Event{Time: ts, Level: "error", Msg: "failed", Fields: sabot.Fields{
+			"error": "oops\nmain.main\n\tmain.go:38",
+		}})
+	})
+
+	When("filtering by level", func() {
+		It("should show those at or above", func() {
+			Expect(vwr.shown).To(HaveLen(3))
+
+			vwr.Press("l")
+			vwr.Press("l")
+			Expect(vwr.shown).To(Equal([]int{1, 2}))
+			Expect(vwr.status()).To(HavePrefix("1/2  info+"))
+		})
+	})
+
+	When("searching", func() {
+		It("should show those matching, ignoring case", func() {
+			for _, key := range ParseKeys([]byte("/FAIL\r")) {
+				vwr.Press(key)
+			}
+			Expect(vwr.shown).To(Equal([]int{2}))
+			Expect(vwr.search).To(Equal("FAIL"))
+		})
+	})
+
+	When("showing detail", func() {
+		It("should expand json and stacks", func() {
+			vwr.Press(KeyDown)
+			vwr.Press(KeyEnter)
+
+			buf := &bytes.Buffer{}
+			vwr.Render(buf)
+			Expect(buf.String()).To(ContainSubstring("msg:   fetched\r\nreq:\r\n  {\r\n"))
+			Expect(strings.Join(vwr.detailLines(), "\n")).To(ContainSubstring("req:\n  {\n    \"method\": \"GET\","))
+
+			vwr.Press("j")
+			Expect(strings.Join(vwr.detailLines(), "\n")).To(ContainSubstring("error:\n  oops\n  main.main\n    main.go:38"))
+		})
+	})
+
+	When("following", func() {
+		It("should move to new events until scrolled up", func() {
+			vwr.Press("f")
+			vwr.Add(sabot.Event{Level: "info", Msg: "latest", Fields: sabot.Fields{}})
+			Expect(vwr.cursor).To(Equal(3))
+
+			vwr.Press("k")
+			vwr.Add(sabot.Event{Level: "info", Msg: "later", Fields: sabot.Fields{}})
+			Expect(vwr.cursor).To(Equal(2))
+		})
+	})
+
+	When("parsing keys", func() {
+		It("should recognize sequences and runes", func() {
+			Expect(ParseKeys([]byte("\x1b[Aj\x1b[C\x1b[6~é\x7f\x03"))).To(Equal([]Key{
+				KeyUp, "j", KeyPageDown, "é", KeyBackspace, "q",
+			}))
+		})
+	})
+})
+
+var _ = Describe("Tail", func() {
+
+	It("should send events, and undecodable lines as msg alone", func() {
+		src := strings.NewReader(`{"ts":"2026-10-15T01:02:03Z","level":"info","msg":"hi"}` + "\nnot json\n")
+
+		msgs := []string{}
+		for evt := range Tail(context.Background(), src, false) {
+			msgs = append(msgs, evt.Msg)
+		}
+		Expect(msgs).To(Equal([]string{"hi", "not json"}))
+	})
+})